@@ -61,6 +61,8 @@ type PackageInfoTemplate struct {
 	Version string `json:"version"`
 	// RepositoryName is the name of the repository to pull the package from (optional)
 	RepositoryName string `json:"repositoryName,omitempty"`
+	// SyncInterval overrides the sync interval of the repository for this package (optional)
+	SyncInterval *metav1.Duration `json:"syncInterval,omitempty"`
 }
 
 type ObjectKeyValueSource struct {
@@ -74,12 +76,23 @@ type PackageValueSource struct {
 	Value string `json:"value"`
 }
 
+// ResourceValueSource references a field of an arbitrary resource.
+// Path is a JSONPath expression that must produce exactly one result, e.g. "{.spec.clusterIP}".
+type ResourceValueSource struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Name       string `json:"name"`
+	Namespace  string `json:"namespace,omitempty"`
+	Path       string `json:"path"`
+}
+
 // +kubebuilder:validation:MinProperties:=1
 // +kubebuilder:validation:MaxProperties:=1
 type ValueReference struct {
 	ConfigMapRef *ObjectKeyValueSource `json:"configMapRef,omitempty"`
 	SecretRef    *ObjectKeyValueSource `json:"secretRef,omitempty"`
 	PackageRef   *PackageValueSource   `json:"packageRef,omitempty"`
+	ResourceRef  *ResourceValueSource  `json:"resourceRef,omitempty"`
 }
 
 type InlineValueConfiguration struct {
@@ -102,8 +115,25 @@ type PackageSpec struct {
 	//
 	// +kubebuilder:validation:Optional
 	Suspend bool `json:"suspend"`
+
+	// DriftPolicy enables drift detection for the resources of plain manifests. If set, the live state of these
+	// resources is compared with their desired state on every reconciliation and any drift is either corrected or
+	// only reported. If empty, drift detection is disabled.
+	//
+	// +kubebuilder:validation:Optional
+	DriftPolicy DriftPolicy `json:"driftPolicy,omitempty"`
 }
 
+// +kubebuilder:validation:Enum=Correct;Report
+type DriftPolicy string
+
+const (
+	// DriftCorrect reverts changes that were made to resources outside of Glasskube.
+	DriftCorrect DriftPolicy = "Correct"
+	// DriftReport only reports changes that were made to resources outside of Glasskube and leaves them in place.
+	DriftReport DriftPolicy = "Report"
+)
+
 func (spec *PackageSpec) Hashed() (string, error) {
 	h := sha256.New()
 	if err := json.NewEncoder(h).Encode(spec); err != nil {
@@ -120,4 +150,69 @@ type PackageStatus struct {
 	OwnedResources    []OwnedResourceRef `json:"ownedResources,omitempty"`
 	OwnedPackageInfos []OwnedResourceRef `json:"ownedPackageInfos,omitempty"`
 	OwnedPackages     []OwnedResourceRef `json:"ownedPackages,omitempty"`
+	// ResolvedValuesHash is the hash of the resolved values that were applied by the last successful reconciliation.
+	// Secret values are not part of the hash, only the references they are resolved from.
+	ResolvedValuesHash string `json:"resolvedValuesHash,omitempty"`
+	// Hooks contains the state of the last run of every hook of the package.
+	Hooks []HookStatus `json:"hooks,omitempty"`
+	// Revisions is the bounded history of the versions and values that were applied, the most recent one last.
+	Revisions []PackageRevision `json:"revisions,omitempty"`
+	// Drift contains the resources that differ from their desired state, as found by the last drift detection.
+	// Drift that was corrected is not recorded here.
+	Drift []ResourceDrift `json:"drift,omitempty"`
+}
+
+// +kubebuilder:validation:Enum=Pending;Succeeded;Failed
+type RevisionOutcome string
+
+const (
+	RevisionPending   RevisionOutcome = "Pending"
+	RevisionSucceeded RevisionOutcome = "Succeeded"
+	RevisionFailed    RevisionOutcome = "Failed"
+)
+
+// PackageRevision is an entry in the revision history of a package.
+// A new revision is recorded every time the version or values of a package change.
+type PackageRevision struct {
+	// Revision is the number of this revision. Numbers are increasing and never reused for the same package.
+	Revision int64                         `json:"revision"`
+	Version  string                        `json:"version"`
+	Values   map[string]ValueConfiguration `json:"values,omitempty"`
+	// Timestamp is the time at which the revision was first reconciled.
+	Timestamp metav1.Time     `json:"timestamp"`
+	Outcome   RevisionOutcome `json:"outcome"`
+}
+
+// HookStatus is the state of the last run of a hook.
+type HookStatus struct {
+	Name  string    `json:"name"`
+	Phase HookPhase `json:"phase"`
+	// Version is the package version for which the hook was run.
+	Version string `json:"version"`
+	// Completed is true if all resources of the hook have completed successfully.
+	Completed bool `json:"completed,omitempty"`
+	// Failed is true if at least one resource of the hook has failed. A failed hook is not run again for the same
+	// version.
+	Failed bool `json:"failed,omitempty"`
+	// Message describes why the hook has failed.
+	Message string `json:"message,omitempty"`
+	// Resources are the resources that were applied for the hook.
+	Resources []OwnedResourceRef `json:"resources,omitempty"`
+}
+
+// ResourceDrift describes how an owned resource differs from its desired state.
+type ResourceDrift struct {
+	OwnedResourceRef `json:",inline"`
+	// Missing is true if the resource was deleted.
+	Missing bool         `json:"missing,omitempty"`
+	Fields  []FieldDrift `json:"fields,omitempty"`
+}
+
+// FieldDrift is a single field of a resource whose live value differs from its desired value.
+// Values are JSON encoded and may be truncated. An empty value means that the field is not set.
+// Values of Secrets are never recorded.
+type FieldDrift struct {
+	Path    string `json:"path"`
+	Desired string `json:"desired,omitempty"`
+	Live    string `json:"live,omitempty"`
 }
@@ -0,0 +1,104 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +kubebuilder:validation:Enum=Installed;Updated;Failed;UpdateAvailable
+type NotificationEventType string
+
+const (
+	// NotificationEventInstalled is sent when a package has been installed successfully.
+	NotificationEventInstalled NotificationEventType = "Installed"
+	// NotificationEventUpdated is sent when a package has been updated to a new version successfully.
+	NotificationEventUpdated NotificationEventType = "Updated"
+	// NotificationEventFailed is sent when a package has transitioned to the Failed state.
+	NotificationEventFailed NotificationEventType = "Failed"
+	// NotificationEventUpdateAvailable is sent when a new version of a package has become available.
+	NotificationEventUpdateAvailable NotificationEventType = "UpdateAvailable"
+)
+
+// NotificationChannelSpec defines the desired state of NotificationChannel
+type NotificationChannelSpec struct {
+	// Url is the HTTP endpoint that events are sent to. Each event is sent as a JSON object in a POST request.
+	Url string `json:"url"`
+	// SigningKeySecretRef is a reference to a key of a secret in the glasskube-system namespace.
+	// If it is set, every request has a "X-Glasskube-Signature" header with the value "sha256=" followed by the hex
+	// encoded HMAC-SHA256 of the request body, using the value of the secret key as key.
+	SigningKeySecretRef *corev1.SecretKeySelector `json:"signingKeySecretRef,omitempty"`
+	// Events is a list of event types that are sent. If it is empty, all events are sent.
+	Events []NotificationEventType `json:"events,omitempty"`
+	// Packages is a list of package names. An event matches if either the name of the Package or ClusterPackage or
+	// the name of the package it installs is in the list. If it is empty, events for all packages are sent.
+	Packages []string `json:"packages,omitempty"`
+	// Namespaces is a list of namespaces. If it is not empty, only events for Packages in one of these namespaces are
+	// sent, so events for ClusterPackages are not sent at all.
+	Namespaces []string `json:"namespaces,omitempty"`
+}
+
+// NotificationChannelStatus defines the observed state of NotificationChannel
+type NotificationChannelStatus struct {
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:scope=Cluster
+//+kubebuilder:printcolumn:name=Url,type=string,JSONPath=".spec.url"
+//+kubebuilder:printcolumn:name=Ready,type=string,JSONPath=".status.conditions[?(@.type==\"Ready\")].status"
+
+// NotificationChannel is the Schema for the notificationchannels API
+type NotificationChannel struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   NotificationChannelSpec   `json:"spec,omitempty"`
+	Status NotificationChannelStatus `json:"status,omitempty"`
+}
+
+// Matches returns true if an event of type eventType for the package with the given name, package name and namespace
+// should be sent to this channel. namespace is empty for ClusterPackages.
+func (ch *NotificationChannel) Matches(eventType NotificationEventType, name, packageName, namespace string) bool {
+	if len(ch.Spec.Events) > 0 && !slices.Contains(ch.Spec.Events, eventType) {
+		return false
+	} else if len(ch.Spec.Packages) > 0 &&
+		!slices.Contains(ch.Spec.Packages, name) && !slices.Contains(ch.Spec.Packages, packageName) {
+		return false
+	} else if len(ch.Spec.Namespaces) > 0 && !slices.Contains(ch.Spec.Namespaces, namespace) {
+		return false
+	} else {
+		return true
+	}
+}
+
+//+kubebuilder:object:root=true
+
+// NotificationChannelList contains a list of NotificationChannel
+type NotificationChannelList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []NotificationChannel `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&NotificationChannel{}, &NotificationChannelList{})
+}
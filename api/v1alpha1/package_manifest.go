@@ -52,7 +52,33 @@ func (obj HelmManifest) IsOCIRepository() bool {
 	return strings.HasPrefix(obj.RepositoryUrl, "oci://")
 }
 
+type KustomizeImage struct {
+	// Name is the image name as it appears in the base resources.
+	Name string `json:"name" jsonschema:"required"`
+	// NewName replaces the name of the image.
+	NewName string `json:"newName,omitempty"`
+	// NewTag replaces the tag of the image.
+	NewTag string `json:"newTag,omitempty"`
+	// Digest replaces the tag of the image with a digest.
+	Digest string `json:"digest,omitempty"`
+}
+
 type KustomizeManifest struct {
+	// Url is the location of the kustomize base.
+	// This can be any remote target supported by kustomize, e.g. a git repository with an optional path and ref
+	// ("https://github.com/example/repo//config/default?ref=v1.0.0") or the https URL of a single manifest file.
+	// If this field is set to a local path it will be resolved relative to the packages "package.yaml" file and
+	// fetched using the credentials of the package repository. Local paths can point to a kustomization directory
+	// (e.g. "./kustomize/overlay"), a kustomization file or a single manifest file. Local paths and all files they
+	// refer to must be inside the package directory.
+	// Remote git bases are cloned without credentials, so the repository must be public. The ref can be a branch,
+	// tag or commit.
+	Url string `json:"url" jsonschema:"required"`
+	// Namespace, if set to a non-empty string, overrides the namespace of all namespaced resources in the
+	// kustomization. It has no effect for namespaced packages.
+	Namespace string `json:"namespace,omitempty"`
+	// Images can be used to override the name, tag or digest of images used in the kustomization.
+	Images []KustomizeImage `json:"images,omitempty"`
 }
 
 // PackageEntrypoint defines a service port a user may use to access the package
@@ -75,6 +101,10 @@ type PlainManifest struct {
 	// If this field is set to a local path (e.g. a relative path like "./manifest.yaml" or just "manifest.yaml") it
 	// will be resolved relative to the packages "package.yaml" file.
 	Url string `json:"url" jsonschema:"required"`
+	// Digest, if set, is the SHA-256 digest of the manifest in the format "sha256:<hex>".
+	// The manifest is only applied if its contents match the digest.
+	// +kubebuilder:validation:Pattern=`^sha256:[a-fA-F0-9]{64}$`
+	Digest string `json:"digest,omitempty" jsonschema:"pattern=^sha256:[a-fA-F0-9]{64}$"`
 	// DefaultNamespace, if set to a non-empty string, is used for resources that are of a namespaced
 	// kind and do not have a namespace set.
 	// If at least one such a resource exists, the namespace is created implicitly.
@@ -144,6 +174,84 @@ type TransformationDefinition struct {
 	Targets []ValueDefinitionTarget `json:"targets" jsonschema:"required"`
 }
 
+// ReadinessCheck declares when a resource of the given kind is considered ready.
+// If at least one check exists for a resource, it replaces the builtin readiness evaluation for that resource.
+// Path is a JSONPath expression that is evaluated on the resource, e.g. "{.status.phase}".
+// If Equals is set, the check passes if the result equals this value.
+// Otherwise, it passes if the result is neither empty nor false.
+type ReadinessCheck struct {
+	APIVersion string `json:"apiVersion" jsonschema:"required"`
+	Kind       string `json:"kind" jsonschema:"required"`
+	// Name optionally restricts the check to the resource with this name.
+	Name   string  `json:"name,omitempty"`
+	Path   string  `json:"path" jsonschema:"required"`
+	Equals *string `json:"equals,omitempty"`
+}
+
+// +kubebuilder:validation:Enum=pre-install;post-install;pre-upgrade;post-upgrade;pre-delete
+type HookPhase string
+
+const (
+	// HookPreInstall hooks run before the resources of a package are applied for the first time.
+	HookPreInstall HookPhase = "pre-install"
+	// HookPostInstall hooks run after the resources of a package are ready for the first time.
+	HookPostInstall HookPhase = "post-install"
+	// HookPreUpgrade hooks run before the resources of a new version of a package are applied.
+	HookPreUpgrade HookPhase = "pre-upgrade"
+	// HookPostUpgrade hooks run after the resources of a new version of a package are ready.
+	HookPostUpgrade HookPhase = "post-upgrade"
+	// HookPreDelete hooks run before a package and its resources are deleted.
+	HookPreDelete HookPhase = "pre-delete"
+)
+
+func (HookPhase) JSONSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Enum: []any{
+			HookPreInstall,
+			HookPostInstall,
+			HookPreUpgrade,
+			HookPostUpgrade,
+			HookPreDelete,
+		},
+	}
+}
+
+// +kubebuilder:validation:Enum=BeforeHookCreation;HookSucceeded;HookFailed
+type HookDeletionPolicy string
+
+const (
+	// HookDeleteBeforeCreation keeps the resources of a hook until the hook runs again.
+	HookDeleteBeforeCreation HookDeletionPolicy = "BeforeHookCreation"
+	// HookDeleteOnSuccess deletes the resources of a hook as soon as it succeeded.
+	HookDeleteOnSuccess HookDeletionPolicy = "HookSucceeded"
+	// HookDeleteOnFailure deletes the resources of a hook as soon as it failed.
+	HookDeleteOnFailure HookDeletionPolicy = "HookFailed"
+)
+
+func (HookDeletionPolicy) JSONSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Enum: []any{
+			HookDeleteBeforeCreation,
+			HookDeleteOnSuccess,
+			HookDeleteOnFailure,
+		},
+	}
+}
+
+// PackageHook references a manifest that is applied at a specific phase of the lifecycle of a package.
+// The controller waits for all Jobs (and other resources with a status) of a hook to complete before it proceeds
+// with the next hook or the next phase. Hooks of the same phase run one after another in the order in which they are
+// declared. A hook runs once per phase and package version, even if it failed. A failed pre-delete hook does not
+// block the deletion of the package.
+// Resources of previous runs of a hook are always deleted before the hook runs again. The deletion policy
+// additionally allows to delete them as soon as the hook succeeded or failed (default is BeforeHookCreation).
+type PackageHook struct {
+	Name           string             `json:"name" jsonschema:"required"`
+	Phase          HookPhase          `json:"phase" jsonschema:"required"`
+	Manifest       PlainManifest      `json:"manifest" jsonschema:"required"`
+	DeletionPolicy HookDeletionPolicy `json:"deletionPolicy,omitempty"`
+}
+
 type PackageManifest struct {
 	// Scope is optional (default is Cluster)
 	Scope            *PackageScope      `json:"scope,omitempty"`
@@ -154,12 +262,14 @@ type PackageManifest struct {
 	IconUrl          string             `json:"iconUrl,omitempty" jsonschema:"format=uri"`
 	// Helm instructs the controller to create a helm release when installing this package.
 	Helm *HelmManifest `json:"helm,omitempty"`
-	// Kustomize instructs the controller to apply a kustomization when installing this package [PLACEHOLDER].
+	// Kustomize instructs the controller to apply a kustomization when installing this package.
 	Kustomize           *KustomizeManifest                 `json:"kustomize,omitempty"`
 	Manifests           []PlainManifest                    `json:"manifests,omitempty"`
 	ValueDefinitions    map[string]ValueDefinition         `json:"valueDefinitions,omitempty"`
 	Transformations     []TransformationDefinition         `json:"transformations,omitempty"`
 	TransitiveResources []corev1.TypedLocalObjectReference `json:"transitiveResources,omitempty"`
+	ReadinessChecks     []ReadinessCheck                   `json:"readinessChecks,omitempty"`
+	Hooks               []PackageHook                      `json:"hooks,omitempty"`
 	// DefaultNamespace to install the package. May be overridden.
 	DefaultNamespace string              `json:"defaultNamespace,omitempty" jsonschema:"required"`
 	Entrypoints      []PackageEntrypoint `json:"entrypoints,omitempty"`
@@ -8,7 +8,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 )
 
-// +kubebuilder:validation:Enum=boolean;text;number;options
+// +kubebuilder:validation:Enum=boolean;text;number;options;secret;list;map
 type ValueType string
 
 const (
@@ -16,6 +16,12 @@ const (
 	ValueTypeText    ValueType = "text"
 	ValueTypeNumber  ValueType = "number"
 	ValueTypeOptions ValueType = "options"
+	// ValueTypeSecret is a text value that is not displayed and is stored in a Secret instead of the package spec.
+	ValueTypeSecret ValueType = "secret"
+	// ValueTypeList is a list of items. Values of this type are stored as JSON array of strings.
+	ValueTypeList ValueType = "list"
+	// ValueTypeMap is a map of string keys to items. Values of this type are stored as JSON object of strings.
+	ValueTypeMap ValueType = "map"
 )
 
 func (ref *ValueType) parseString(data string) error {
@@ -28,6 +34,12 @@ func (ref *ValueType) parseString(data string) error {
 		*ref = ValueTypeNumber
 	case string(ValueTypeOptions):
 		*ref = ValueTypeOptions
+	case string(ValueTypeSecret):
+		*ref = ValueTypeSecret
+	case string(ValueTypeList):
+		*ref = ValueTypeList
+	case string(ValueTypeMap):
+		*ref = ValueTypeMap
 	default:
 		return fmt.Errorf("invalid ValueType: %v", data)
 	}
@@ -36,7 +48,8 @@ func (ref *ValueType) parseString(data string) error {
 func (ValueType) JSONSchema() *jsonschema.Schema {
 	return &jsonschema.Schema{
 		Type: "string",
-		Enum: []any{ValueTypeBoolean, ValueTypeText, ValueTypeNumber, ValueTypeOptions},
+		Enum: []any{ValueTypeBoolean, ValueTypeText, ValueTypeNumber, ValueTypeOptions, ValueTypeSecret,
+			ValueTypeList, ValueTypeMap},
 	}
 }
 
@@ -61,6 +74,19 @@ type ValueDefinitionConstraints struct {
 	MinLength *int    `json:"minLength,omitempty"`
 	MaxLength *int    `json:"maxLength,omitempty"`
 	Pattern   *string `json:"pattern,omitempty"`
+	// MinItems is the minimum number of items of a list or map value.
+	MinItems *int `json:"minItems,omitempty"`
+	// MaxItems is the maximum number of items of a list or map value.
+	MaxItems *int `json:"maxItems,omitempty"`
+}
+
+// ValueItemDefinition describes the items of a list or map value.
+// If a list or map value has no ValueItemDefinition, its items are text values without constraints.
+type ValueItemDefinition struct {
+	// Type must be one of boolean, text, number or options.
+	Type        ValueType                  `json:"type" jsonschema:"required"`
+	Options     []string                   `json:"options,omitempty"`
+	Constraints ValueDefinitionConstraints `json:"constraints,omitempty"`
 }
 
 type PartialJsonPatch struct {
@@ -68,6 +94,12 @@ type PartialJsonPatch struct {
 	Path string `json:"path" jsonschema:"required"`
 }
 
+// ValueDefinitionTarget describes a patch that is applied to a resource or helm chart.
+// If set, ValueTemplate is a Go text/template that is executed with the value and must produce valid JSON.
+// In addition to the builtin template functions, the following functions are available:
+// lower, upper, trim, trimPrefix, trimSuffix, replace, contains, hasPrefix, hasSuffix, quote, split, join,
+// default, empty, coalesce, ternary, base64, base64Decode, toJson, fromJson, toYaml, fromYaml, sha256sum,
+// add, sub, mul, div, mod, min, max and toInt.
 // +kubebuilder:validation:XValidation:message="ValueDefinitionTarget must have either resource or chartName but not both",rule="has(self.resource) != has(self.chartName)"
 type ValueDefinitionTarget struct {
 	Resource      *corev1.TypedObjectReference `json:"resource,omitempty" jsonschema:"oneof_required=WithResource"`
@@ -76,11 +108,22 @@ type ValueDefinitionTarget struct {
 	ValueTemplate string                       `json:"valueTemplate,omitempty"`
 }
 
+// ValueCondition is a condition on another value of the same package.
+// If neither Equals nor In is set, the condition holds if the other value is set and neither empty nor false.
+type ValueCondition struct {
+	// Value is the name of the value definition that the condition depends on.
+	Value  string   `json:"value" jsonschema:"required"`
+	Equals *string  `json:"equals,omitempty"`
+	In     []string `json:"in,omitempty"`
+}
+
 type ValueDefinition struct {
 	Type         ValueType                  `json:"type" jsonschema:"required"`
 	Metadata     ValueDefinitionMetadata    `json:"metadata,omitempty"`
 	DefaultValue string                     `json:"defaultValue,omitempty"`
 	Options      []string                   `json:"options,omitempty"`
 	Constraints  ValueDefinitionConstraints `json:"constraints,omitempty"`
+	Items        *ValueItemDefinition       `json:"items,omitempty"`
+	When         *ValueCondition            `json:"when,omitempty"`
 	Targets      []ValueDefinitionTarget    `json:"targets" jsonschema:"required"`
 }
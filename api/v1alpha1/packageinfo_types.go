@@ -29,6 +29,8 @@ type PackageInfoSpec struct {
 	Name           string `json:"name"`
 	Version        string `json:"version,omitempty"`
 	RepositoryName string `json:"repositoryUrl,omitempty"`
+	// SyncInterval overrides the sync interval of the repository for this PackageInfo.
+	SyncInterval *metav1.Duration `json:"syncInterval,omitempty"`
 }
 
 // PackageInfoStatus defines the observed state of PackageInfo
@@ -38,6 +40,9 @@ type PackageInfoStatus struct {
 	Conditions          []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
 	LastUpdateTimestamp *metav1.Time       `json:"lastUpdateTimestamp,omitempty"`
 	Version             string             `json:"version,omitempty"`
+	// SignatureKeyFingerprint is the fingerprint of the trusted public key that signed the manifest.
+	// It is empty if the repository does not require signed manifests.
+	SignatureKeyFingerprint string `json:"signatureKeyFingerprint,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -65,6 +70,12 @@ type PackageInfoList struct {
 	Items           []PackageInfo `json:"items"`
 }
 
+// IsSyncRequested returns true if a synchronization was requested using the SyncRequestedAnnotation.
+func (pi *PackageInfo) IsSyncRequested() bool {
+	_, ok := pi.Annotations[SyncRequestedAnnotation]
+	return ok
+}
+
 func init() {
 	SchemeBuilder.Register(&PackageInfo{}, &PackageInfoList{})
 }
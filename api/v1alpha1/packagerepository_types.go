@@ -44,13 +44,38 @@ type PackageRepositoryAuthSpec struct {
 	Bearer *PackageRepositoryBearerAuthSpec `json:"bearer,omitempty"`
 }
 
+type PackageRepositoryGitSpec struct {
+	// Ref is the name of a branch or tag or a commit hash that should be used.
+	// If it is empty, the default branch of the remote is used.
+	Ref string `json:"ref,omitempty"`
+	// Path is the directory inside the git repository that contains the "index.yaml" file.
+	// If it is empty, the root directory of the git repository is used.
+	Path string `json:"path,omitempty"`
+	// SecretRef is a reference to a secret in the glasskube-system namespace that contains credentials for the git
+	// repository. Supported keys are "username" and "password" for HTTP basic authentication or "identity" and
+	// "known_hosts" for SSH authentication.
+	// If it is not set, the auth configuration of the PackageRepository is used for HTTP requests.
+	SecretRef *corev1.LocalObjectReference `json:"secretRef,omitempty"`
+}
+
 // PackageRepositorySpec defines the desired state of PackageRepository
 type PackageRepositorySpec struct {
 	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
 	// Important: Run "make" to regenerate code after modifying this file
 
+	// Url is the base URL of the repository. URLs starting with "oci://" refer to an OCI registry and URLs starting
+	// with "file://" refer to a local directory.
 	Url  string                     `json:"url"`
 	Auth *PackageRepositoryAuthSpec `json:"auth,omitempty"`
+	// Git, if set, indicates that Url refers to a git repository instead of an HTTP server.
+	Git *PackageRepositoryGitSpec `json:"git,omitempty"`
+	// SyncInterval is the interval in which the repository and the manifests of packages installed from this
+	// repository are synchronized. PackageInfos can override this value.
+	SyncInterval *metav1.Duration `json:"syncInterval,omitempty"`
+	// TrustedPublicKeys is a list of PEM encoded public keys (Ed25519, ECDSA or RSA).
+	// If it is not empty, every package manifest from this repository must have a detached signature in a
+	// "package.yaml.sig" file next to it, which must be verifiable with one of these keys.
+	TrustedPublicKeys []string `json:"trustedPublicKeys,omitempty"`
 }
 
 // PackageRepositoryStatus defines the observed state of PackageRepository
@@ -75,6 +100,12 @@ type PackageRepository struct {
 
 const (
 	defaultRepositoryAnnotation = "packages.glasskube.dev/default-repository"
+	OCIRepoUrlPrefix            = "oci://"
+	FileRepoUrlPrefix           = "file://"
+	// SyncRequestedAnnotation can be set on a PackageRepository or PackageInfo to request a synchronization
+	// regardless of the sync interval. It is removed after the synchronization has completed.
+	// For a PackageRepository, the synchronization also includes all PackageInfos from that repository.
+	SyncRequestedAnnotation = "packages.glasskube.dev/sync-requested"
 )
 
 func (repo PackageRepository) IsDefaultRepository() bool {
@@ -96,6 +127,24 @@ func (repo *PackageRepository) SetDefaultRepositoryBool(value bool) {
 	}
 }
 
+func (repo *PackageRepository) IsGitRepo() bool {
+	return repo.Spec.Git != nil
+}
+
+func (repo *PackageRepository) IsOCIRepo() bool {
+	return !repo.IsGitRepo() && strings.HasPrefix(repo.Spec.Url, OCIRepoUrlPrefix)
+}
+
+func (repo *PackageRepository) IsFileRepo() bool {
+	return !repo.IsGitRepo() && strings.HasPrefix(repo.Spec.Url, FileRepoUrlPrefix)
+}
+
+// IsSyncRequested returns true if a synchronization was requested using the SyncRequestedAnnotation.
+func (repo *PackageRepository) IsSyncRequested() bool {
+	_, ok := repo.Annotations[SyncRequestedAnnotation]
+	return ok
+}
+
 func (repo *PackageRepository) IsGlasskubeRepo() bool {
 	return strings.HasPrefix(repo.Spec.Url, constants.DefaultRepoUrl)
 }
@@ -2,6 +2,9 @@ package v1alpha1
 
 const (
 	AnnotationAutoUpdate        = "packages.glasskube.dev/auto-update"
+	AnnotationAutoUpdateWindow  = "packages.glasskube.dev/auto-update-window"
+	AnnotationAutoUpdatePolicy  = "packages.glasskube.dev/auto-update-policy"
 	AnnotationInstalledAsDep    = "packages.glasskube.dev/installed-as-dependency"
 	AnnotationPackageSpecHashed = "packages.glasskube.dev/package-spec-hashed"
+	AnnotationDesiredStateHash  = "packages.glasskube.dev/desired-state-hash"
 )
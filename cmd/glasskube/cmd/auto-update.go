@@ -4,20 +4,27 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/glasskube/glasskube/api/v1alpha1"
+	"github.com/glasskube/glasskube/internal/clientutils"
 	"github.com/glasskube/glasskube/internal/cliutils"
 	"github.com/glasskube/glasskube/internal/config"
 	"github.com/glasskube/glasskube/internal/controller/ctrlpkg"
+	"github.com/glasskube/glasskube/internal/maintenancewindow"
+	"github.com/glasskube/glasskube/internal/updatepolicy"
 	"github.com/glasskube/glasskube/pkg/statuswriter"
 	"github.com/glasskube/glasskube/pkg/update"
 	"github.com/spf13/cobra"
 	"go.uber.org/multierr"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1 "k8s.io/client-go/applyconfigurations/core/v1"
 )
 
 var autoUpdateEnabledDisabledOptions = struct {
 	Yes, All bool
+	Window   string
+	Policy   string
 	KindOptions
 	NamespaceOptions
 }{
@@ -25,8 +32,15 @@ var autoUpdateEnabledDisabledOptions = struct {
 }
 
 var autoUpdateEnableCmd = &cobra.Command{
-	Use:    "enable [...package]",
-	Short:  "Enable automatic updates for packages:",
+	Use:   "enable [...package]",
+	Short: "Enable automatic updates for packages:",
+	Long: "Enable automatic updates for packages.\n" +
+		"Use --window to only allow updates during a maintenance window, e.g. \"Mon-Fri 22:00-04:00 Europe/Vienna\".\n" +
+		"Multiple windows can be separated by \";\". An empty window allows updates at any time, regardless of the " +
+		"cluster default (see \"glasskube auto-update default-window\").\n" +
+		"Use --policy to restrict the versions a package may be updated to: \"patch\" only allows updates within the " +
+		"installed minor version, \"minor\" only allows updates within the installed major version and \"major\" " +
+		"allows all updates. A semver constraint like \"~1.4\" can be used as well.",
 	PreRun: cliutils.SetupClientContext(true, &rootCmdOptions.SkipUpdateCheck),
 	ValidArgsFunction: installedPackagesCompletionFunc(
 		&autoUpdateEnabledDisabledOptions.NamespaceOptions,
@@ -52,6 +66,20 @@ func runAutoUpdateEnableOrDisable(enabled bool, confirmMsg, successMsg string) f
 	return func(cmd *cobra.Command, args []string) {
 		ctx := cmd.Context()
 		client := cliutils.PackageClient(ctx)
+		setWindow := enabled && cmd.Flags().Changed("window")
+		if setWindow {
+			if _, err := maintenancewindow.Parse(autoUpdateEnabledDisabledOptions.Window); err != nil {
+				fmt.Fprintf(os.Stderr, "%v\n", err)
+				cliutils.ExitWithError()
+			}
+		}
+		setPolicy := enabled && cmd.Flags().Changed("policy")
+		if setPolicy {
+			if _, err := updatepolicy.Parse(autoUpdateEnabledDisabledOptions.Policy); err != nil {
+				fmt.Fprintf(os.Stderr, "%v\n", err)
+				cliutils.ExitWithError()
+			}
+		}
 		var pkgs []ctrlpkg.Package
 		if autoUpdateEnabledDisabledOptions.All {
 			if len(args) > 0 {
@@ -120,7 +148,16 @@ func runAutoUpdateEnableOrDisable(enabled bool, confirmMsg, successMsg string) f
 
 		var err error
 		for _, pkg := range pkgs {
-			if pkg.AutoUpdatesEnabled() != enabled {
+			annotationsChanged := false
+			if setWindow {
+				annotationsChanged = setAnnotation(pkg, v1alpha1.AnnotationAutoUpdateWindow,
+					autoUpdateEnabledDisabledOptions.Window)
+			}
+			if setPolicy {
+				annotationsChanged = setAnnotation(pkg, v1alpha1.AnnotationAutoUpdatePolicy,
+					autoUpdateEnabledDisabledOptions.Policy) || annotationsChanged
+			}
+			if pkg.AutoUpdatesEnabled() != enabled || annotationsChanged {
 				pkg.SetAutoUpdatesEnabled(enabled)
 				opts := metav1.UpdateOptions{}
 				switch pkg := pkg.(type) {
@@ -142,10 +179,79 @@ func runAutoUpdateEnableOrDisable(enabled bool, confirmMsg, successMsg string) f
 	}
 }
 
+// setAnnotation sets the annotation key of pkg to value and returns true if it was changed.
+func setAnnotation(pkg ctrlpkg.Package, key, value string) bool {
+	annotations := pkg.GetAnnotations()
+	if current, ok := annotations[key]; ok && current == value {
+		return false
+	}
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[key] = value
+	pkg.SetAnnotations(annotations)
+	return true
+}
+
+var autoUpdateDefaultWindowOptions = struct {
+	Unset bool
+}{}
+
+var autoUpdateDefaultWindowCmd = &cobra.Command{
+	Use:   "default-window [<window>]",
+	Short: "Show or set the default maintenance window for automatic updates",
+	Long: "Show or set the default maintenance window for automatic updates.\n" +
+		"The default applies to all packages that do not have a maintenance window of their own.\n" +
+		"A window has the form \"<days> <start>-<end> [<timezone>]\", e.g. \"Sat,Sun 02:00-06:00 Europe/Vienna\". " +
+		"Multiple windows can be separated by \";\".",
+	Args:   cobra.MaximumNArgs(1),
+	PreRun: cliutils.SetupClientContext(true, &rootCmdOptions.SkipUpdateCheck),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		if len(args) == 0 && !autoUpdateDefaultWindowOptions.Unset {
+			if schedule, err := clientutils.GetDefaultMaintenanceWindow(ctx); err != nil {
+				fmt.Fprintf(os.Stderr, "❌ Could not get default maintenance window: %v\n", err)
+				cliutils.ExitWithError()
+			} else if len(schedule) == 0 {
+				fmt.Fprintln(os.Stderr, "No default maintenance window is configured. Updates may be applied at any time.")
+			} else {
+				fmt.Fprintf(os.Stderr, "Default maintenance window: %v (%v)\n", schedule, schedule.Describe(time.Now()))
+			}
+			cliutils.ExitSuccess()
+		} else if len(args) > 0 && autoUpdateDefaultWindowOptions.Unset {
+			fmt.Fprintln(os.Stderr, "❌ A window can not be given together with --unset")
+			cliutils.ExitWithError()
+		}
+
+		ns := corev1.Namespace("glasskube-system")
+		if len(args) > 0 {
+			if _, err := maintenancewindow.Parse(args[0]); err != nil {
+				fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+				cliutils.ExitWithError()
+			}
+			ns = ns.WithAnnotations(map[string]string{v1alpha1.AnnotationAutoUpdateWindow: args[0]})
+		}
+		// Applying the namespace without the annotation removes it, because it is owned by this field manager.
+		if _, err := cliutils.KubernetesClient(ctx).CoreV1().Namespaces().Apply(ctx, ns,
+			metav1.ApplyOptions{FieldManager: "glasskube-auto-update", Force: true}); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Could not update default maintenance window: %v\n", err)
+			cliutils.ExitWithError()
+		}
+		if len(args) > 0 {
+			fmt.Fprintf(os.Stderr, "✅ Default maintenance window set to %v\n", args[0])
+		} else {
+			fmt.Fprintln(os.Stderr, "✅ Default maintenance window removed")
+		}
+	},
+}
+
 var autoUpdateCmd = &cobra.Command{
 	Use:   "auto-update",
 	Short: "Update autopilot for packages where automatic updates are enabled",
-	Args:  cobra.NoArgs,
+	Long: "Update autopilot for packages where automatic updates are enabled.\n" +
+		"The package operator checks for updates periodically and applies them automatically, so this command is " +
+		"only needed to apply updates immediately or if the operator was started with --auto-update-interval=0.",
+	Args: cobra.NoArgs,
 	PreRun: cliutils.RunAll(
 		func(c *cobra.Command, s []string) { config.NonInteractive = true },
 		cliutils.SetupClientContext(false, &rootCmdOptions.SkipUpdateCheck),
@@ -156,8 +262,14 @@ var autoUpdateCmd = &cobra.Command{
 func runAutoUpdate(cmd *cobra.Command, args []string) {
 	ctx := cmd.Context()
 	client := cliutils.PackageClient(ctx)
+	defaultSchedule, err := clientutils.GetDefaultMaintenanceWindow(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error getting default maintenance window: %v\n", err)
+		cliutils.ExitWithError()
+	}
 	updater := update.NewUpdater(ctx).
-		WithStatusWriter(statuswriter.Stderr())
+		WithStatusWriter(statuswriter.Stderr()).
+		WithMaintenanceWindows(defaultSchedule)
 
 	var pkgs []ctrlpkg.Package
 
@@ -195,6 +307,15 @@ func runAutoUpdate(cmd *cobra.Command, args []string) {
 	}
 	printTransaction(*tx)
 
+	now := time.Now()
+	for _, item := range tx.Items {
+		if !item.UpdateRequired() {
+			continue
+		} else if err := updater.CheckMaintenanceWindow(item.Package, now); err != nil {
+			fmt.Fprintf(os.Stderr, "Skipping %v: %v\n", item.Package.GetName(), err)
+		}
+	}
+
 	if updated, err := updater.Apply(ctx, tx, update.ApplyUpdateOptions{Blocking: true, DryRun: false}); err != nil {
 		fmt.Fprintf(os.Stderr, "Error applying update: %v\n", err)
 		cliutils.ExitWithError()
@@ -215,9 +336,18 @@ func init() {
 			autoUpdateEnabledDisabledOptions.Yes, "Do not ask for confirmation")
 		cmd.Flags().BoolVar(&autoUpdateEnabledDisabledOptions.All, "all",
 			autoUpdateEnabledDisabledOptions.All, "Set for all packages")
+		if cmd == autoUpdateEnableCmd {
+			cmd.Flags().StringVar(&autoUpdateEnabledDisabledOptions.Window, "window", "",
+				"Maintenance window during which updates may be applied")
+			cmd.Flags().StringVar(&autoUpdateEnabledDisabledOptions.Policy, "policy", "",
+				"Versions updates are restricted to (patch, minor, major or a semver constraint)")
+		}
 		autoUpdateEnabledDisabledOptions.KindOptions.AddFlagsToCommand(cmd)
 		autoUpdateEnabledDisabledOptions.NamespaceOptions.AddFlagsToCommand(cmd)
 		autoUpdateCmd.AddCommand(cmd)
 	}
+	autoUpdateDefaultWindowCmd.Flags().BoolVar(&autoUpdateDefaultWindowOptions.Unset, "unset", false,
+		"Remove the default maintenance window")
+	autoUpdateCmd.AddCommand(autoUpdateDefaultWindowCmd)
 	RootCmd.AddCommand(autoUpdateCmd)
 }
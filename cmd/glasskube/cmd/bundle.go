@@ -0,0 +1,15 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var bundleCmd = &cobra.Command{
+	Use:   "bundle",
+	Short: "Export and import packages for air-gapped clusters",
+}
+
+func init() {
+	bundleCmd.AddCommand(bundleExportCmd, bundleImportCmd)
+	RootCmd.AddCommand(bundleCmd)
+}
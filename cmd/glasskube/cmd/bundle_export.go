@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/glasskube/glasskube/internal/bundle"
+	"github.com/glasskube/glasskube/internal/cliutils"
+	"github.com/spf13/cobra"
+)
+
+var bundleExportCmdOptions = struct {
+	Output     string
+	Repository string
+}{
+	Output: "glasskube-bundle.tar.gz",
+}
+
+var bundleExportCmd = &cobra.Command{
+	Use:   "export <package-name>[@<version>]...",
+	Short: "Export packages and their dependencies to a bundle",
+	Long: "Export packages and their dependencies to a self-contained bundle.\n" +
+		"The bundle contains the repository index, versions and package manifests together with all plain manifests\n" +
+		"that the packages reference. If no version is given, the latest version is exported.\n" +
+		"Use \"glasskube bundle import\" to load the bundle into a repository that is reachable from an air-gapped " +
+		"cluster.",
+	Args:              cobra.MinimumNArgs(1),
+	PreRun:            cliutils.SetupClientContext(true, &rootCmdOptions.SkipUpdateCheck),
+	ValidArgsFunction: completeAvailablePackageNames,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		refs := make([]bundle.PackageRef, len(args))
+		for i, arg := range args {
+			name, version, _ := strings.Cut(arg, "@")
+			refs[i] = bundle.PackageRef{Name: name, Version: version, RepositoryName: bundleExportCmdOptions.Repository}
+		}
+
+		exporter := bundle.NewExporter(cliutils.RepositoryClientset(ctx), cliutils.DependencyManager(ctx))
+		b, err := exporter.Export(refs...)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ error exporting packages: %v\n", err)
+			cliutils.ExitWithError()
+		}
+		for _, warning := range exporter.Warnings {
+			fmt.Fprintf(os.Stderr, "⚠️  %v\n", warning)
+		}
+
+		file, err := os.Create(bundleExportCmdOptions.Output)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ error creating bundle: %v\n", err)
+			cliutils.ExitWithError()
+		}
+		defer func() { _ = file.Close() }()
+		if err := b.WriteArchive(file); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ error writing bundle: %v\n", err)
+			cliutils.ExitWithError()
+		}
+
+		for _, item := range b.Index.Packages {
+			for _, version := range b.Packages[item.Name].Index.Versions {
+				fmt.Fprintf(os.Stderr, " * %v (%v)\n", item.Name, version.Version)
+			}
+		}
+		fmt.Fprintf(os.Stderr, "✅ bundle written to %v\n", bundleExportCmdOptions.Output)
+	},
+}
+
+func init() {
+	bundleExportCmd.Flags().StringVarP(&bundleExportCmdOptions.Output, "output", "o", bundleExportCmdOptions.Output,
+		"Path of the bundle file")
+	bundleExportCmd.Flags().StringVar(&bundleExportCmdOptions.Repository, "repository",
+		bundleExportCmdOptions.Repository, "Specify the name of the package repository to export the packages from")
+}
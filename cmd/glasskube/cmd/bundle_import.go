@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/glasskube/glasskube/api/v1alpha1"
+	"github.com/glasskube/glasskube/internal/bundle"
+	"github.com/glasskube/glasskube/internal/cliutils"
+	"github.com/glasskube/glasskube/internal/repo/client/auth"
+	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var bundleImportCmdOptions = struct {
+	Dir        string
+	OCI        string
+	Repository string
+	Username   string
+	Password   string
+	Token      string
+}{}
+
+var bundleImportCmd = &cobra.Command{
+	Use:   "import <bundle-file>",
+	Short: "Import a bundle into a package repository",
+	Long: "Import a bundle into a package repository.\n" +
+		"Packages can be imported into a local directory (--dir) or an OCI registry (--oci), " +
+		"for example a registry running inside the air-gapped cluster.\n" +
+		"Packages that already exist in the repository are kept. If --repository is set, a package repository " +
+		"with this name is added to the current cluster or updated to use the imported packages.\n" +
+		"Note that a directory must also be available to the package operator, e.g. by mounting a volume.",
+	Args: cobra.ExactArgs(1),
+	PreRun: func(cmd *cobra.Command, args []string) {
+		if len(bundleImportCmdOptions.Repository) > 0 {
+			cliutils.SetupClientContext(true, &rootCmdOptions.SkipUpdateCheck)(cmd, args)
+		}
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		file, err := os.Open(args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ error opening bundle: %v\n", err)
+			cliutils.ExitWithError()
+		}
+		defer func() { _ = file.Close() }()
+		b, err := bundle.ReadArchive(file)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ error reading bundle: %v\n", err)
+			cliutils.ExitWithError()
+		}
+
+		var url string
+		if len(bundleImportCmdOptions.Dir) > 0 {
+			if url, err = bundle.ImportDir(b, bundleImportCmdOptions.Dir); err != nil {
+				fmt.Fprintf(os.Stderr, "❌ error importing bundle: %v\n", err)
+				cliutils.ExitWithError()
+			}
+		} else {
+			url = bundleImportCmdOptions.OCI
+			if err := bundle.ImportOCI(b, url, bundleImportAuthenticator()); err != nil {
+				fmt.Fprintf(os.Stderr, "❌ error importing bundle: %v\n", err)
+				cliutils.ExitWithError()
+			}
+		}
+
+		for _, item := range b.Index.Packages {
+			for _, version := range b.Packages[item.Name].Index.Versions {
+				fmt.Fprintf(os.Stderr, " * %v (%v)\n", item.Name, version.Version)
+			}
+		}
+		fmt.Fprintf(os.Stderr, "✅ bundle imported into %v\n", url)
+
+		if len(bundleImportCmdOptions.Repository) > 0 {
+			if err := applyBundleRepository(cmd, bundleImportCmdOptions.Repository, url); err != nil {
+				fmt.Fprintf(os.Stderr, "❌ error applying package repository: %v\n", err)
+				cliutils.ExitWithError()
+			}
+			fmt.Fprintf(os.Stderr, "✅ package repository %v uses %v\n", bundleImportCmdOptions.Repository, url)
+		}
+	},
+}
+
+func bundleImportAuthenticator() auth.Authenticator {
+	if len(bundleImportCmdOptions.Token) > 0 {
+		return auth.Bearer(bundleImportCmdOptions.Token)
+	} else if len(bundleImportCmdOptions.Username) > 0 {
+		return auth.Basic(bundleImportCmdOptions.Username, bundleImportCmdOptions.Password)
+	}
+	return auth.Noop()
+}
+
+func bundleImportAuthSpec() *v1alpha1.PackageRepositoryAuthSpec {
+	if len(bundleImportCmdOptions.Token) > 0 {
+		return &v1alpha1.PackageRepositoryAuthSpec{
+			Bearer: &v1alpha1.PackageRepositoryBearerAuthSpec{Token: &bundleImportCmdOptions.Token},
+		}
+	} else if len(bundleImportCmdOptions.Username) > 0 {
+		return &v1alpha1.PackageRepositoryAuthSpec{
+			Basic: &v1alpha1.PackageRepositoryBasicAuthSpec{
+				Username: &bundleImportCmdOptions.Username,
+				Password: &bundleImportCmdOptions.Password,
+			},
+		}
+	}
+	return nil
+}
+
+// applyBundleRepository creates a package repository with the given name and URL or updates the URL of an
+// existing repository and requests a sync, so that the imported packages are available immediately.
+func applyBundleRepository(cmd *cobra.Command, name, url string) error {
+	ctx := cmd.Context()
+	client := cliutils.PackageClient(ctx)
+	var repo v1alpha1.PackageRepository
+	if err := client.PackageRepositories().Get(ctx, name, &repo); apierrors.IsNotFound(err) {
+		repo = v1alpha1.PackageRepository{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: v1alpha1.PackageRepositorySpec{
+				Url:  url,
+				Auth: bundleImportAuthSpec(),
+			},
+		}
+		return client.PackageRepositories().Create(ctx, &repo, metav1.CreateOptions{})
+	} else if err != nil {
+		return err
+	} else {
+		repo.Spec.Url = url
+		repo.Spec.Git = nil
+		if authSpec := bundleImportAuthSpec(); authSpec != nil {
+			repo.Spec.Auth = authSpec
+		}
+		if repo.Annotations == nil {
+			repo.Annotations = make(map[string]string)
+		}
+		repo.Annotations[v1alpha1.SyncRequestedAnnotation] = time.Now().Format(time.RFC3339)
+		return client.PackageRepositories().Update(ctx, &repo, metav1.UpdateOptions{})
+	}
+}
+
+func init() {
+	bundleImportCmd.Flags().StringVar(&bundleImportCmdOptions.Dir, "dir", bundleImportCmdOptions.Dir,
+		"Import the bundle into a package repository in this directory")
+	bundleImportCmd.Flags().StringVar(&bundleImportCmdOptions.OCI, "oci", bundleImportCmdOptions.OCI,
+		"Import the bundle into the OCI package repository with this URL (e.g. oci://registry.example.com/packages)")
+	bundleImportCmd.Flags().StringVar(&bundleImportCmdOptions.Repository, "repository",
+		bundleImportCmdOptions.Repository, "Name of a package repository in the current cluster that should serve "+
+			"the imported packages")
+	bundleImportCmd.Flags().StringVar(&bundleImportCmdOptions.Username, "username", bundleImportCmdOptions.Username,
+		"Username for basic authentication with the OCI registry")
+	bundleImportCmd.Flags().StringVar(&bundleImportCmdOptions.Password, "password", bundleImportCmdOptions.Password,
+		"Password for basic authentication with the OCI registry")
+	bundleImportCmd.Flags().StringVar(&bundleImportCmdOptions.Token, "token", bundleImportCmdOptions.Token,
+		"Token for bearer authentication with the OCI registry")
+	bundleImportCmd.MarkFlagsOneRequired("dir", "oci")
+	bundleImportCmd.MarkFlagsMutuallyExclusive("dir", "oci")
+	bundleImportCmd.MarkFlagsMutuallyExclusive("username", "token")
+	bundleImportCmd.MarkFlagsMutuallyExclusive("password", "token")
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/fatih/color"
 	"github.com/glasskube/glasskube/api/v1alpha1"
 	"github.com/glasskube/glasskube/internal/cliutils"
+	"github.com/glasskube/glasskube/internal/manifestvalues"
 	"github.com/glasskube/glasskube/internal/manifestvalues/cli"
 	"github.com/glasskube/glasskube/pkg/manifest"
 	"github.com/spf13/cobra"
@@ -89,8 +90,8 @@ func runConfigure(cmd *cobra.Command, args []string) {
 	}
 
 	fmt.Fprintln(os.Stderr, bold("Configuration:"))
-	printValueConfigurations(os.Stderr, pkg.GetSpec().Values)
-	if _, err := valueResolver.Resolve(ctx, pkg.GetSpec().Values); err != nil {
+	printValueConfigurations(os.Stderr, pkg.GetSpec().Values, pkgManifest)
+	if _, err := valueResolver.ResolvePackage(ctx, pkg); err != nil {
 		fmt.Fprintf(os.Stderr, "⚠️  Some values can not be resolved: %v\n", err)
 	}
 
@@ -100,6 +101,13 @@ func runConfigure(cmd *cobra.Command, args []string) {
 		}
 	}
 
+	if secret := manifestvalues.ExtractSecretValues(pkg, pkgManifest); secret != nil {
+		if err := manifestvalues.ApplySecretValues(ctx, cliutils.KubernetesClient(ctx), secret, pkg, opts.DryRun); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ error storing secret values: %v\n", err)
+			cliutils.ExitWithError()
+		}
+	}
+
 	switch pkg := pkg.(type) {
 	case *v1alpha1.ClusterPackage:
 		values := maps.Clone(pkg.Spec.Values)
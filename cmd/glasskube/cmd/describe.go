@@ -8,6 +8,7 @@ import (
 	"io"
 	"os"
 	"strings"
+	"text/tabwriter"
 
 	repoerror "github.com/glasskube/glasskube/internal/repo/error"
 
@@ -119,6 +120,14 @@ var describeCmd = &cobra.Command{
 			}
 		}
 
+		var signature describe.SignatureVerification
+		if pkg.IsNil() {
+			signature = describe.DescribeSignature(ctx, describeCmdOptions.repository, pkgName, latestVersion)
+		} else {
+			signature = describe.VerifySignature(repoClient.ForPackage(pkg),
+				pkg.GetSpec().PackageInfo.Name, pkg.GetSpec().PackageInfo.Version)
+		}
+
 		var repos []v1alpha1.PackageRepository
 		if pkg.IsNil() {
 			repos, err = repoClient.Meta().GetReposForPackage(pkgName)
@@ -132,9 +141,9 @@ var describeCmd = &cobra.Command{
 		bold := color.New(color.Bold).SprintFunc()
 
 		if describeCmdOptions.Output == outputFormatJSON {
-			printJSON(ctx, pkg, pkgs, manifest, latestVersion, repos)
+			printJSON(ctx, pkg, pkgs, manifest, latestVersion, repos, signature)
 		} else if describeCmdOptions.Output == outputFormatYAML {
-			printYAML(ctx, pkg, pkgs, manifest, latestVersion, repos)
+			printYAML(ctx, pkg, pkgs, manifest, latestVersion, repos, signature)
 		} else {
 			fmt.Println(bold("Package:"), nameAndDescription(manifest))
 
@@ -146,7 +155,12 @@ var describeCmd = &cobra.Command{
 				fmt.Println(bold("Message:    "), message(pkgStatus))
 				fmt.Println(bold("Auto-Update:"), clientutils.AutoUpdateString(pkg, "Disabled"))
 				fmt.Println(bold("Suspended:  "), boolYesNo(pkg.GetSpec().Suspend))
-			} else if len(pkgs) > 0 {
+				fmt.Println(bold("Signature:  "), signature)
+			} else {
+				fmt.Println(bold("Signature:"), signature)
+			}
+
+			if pkg.IsNil() && len(pkgs) > 0 {
 				fmt.Println()
 				fmt.Println(bold("Instances:"))
 				for i, pkg := range pkgs {
@@ -198,7 +212,13 @@ var describeCmd = &cobra.Command{
 			if !pkg.IsNil() && len(pkg.GetSpec().Values) > 0 {
 				fmt.Println()
 				fmt.Println(bold("Configuration:"))
-				printValueConfigurations(os.Stdout, pkg.GetSpec().Values)
+				printValueConfigurations(os.Stdout, pkg.GetSpec().Values, manifest)
+			}
+
+			if !pkg.IsNil() && len(pkg.GetStatus().Revisions) > 0 {
+				fmt.Println()
+				fmt.Println(bold("Revisions:"))
+				printRevisions(os.Stdout, pkg.GetStatus().Revisions)
 			}
 		}
 	},
@@ -320,12 +340,26 @@ func referencesAsMap(
 	return references
 }
 
-func printValueConfigurations(w io.Writer, values map[string]v1alpha1.ValueConfiguration) {
-	for name, value := range values {
+func printValueConfigurations(
+	w io.Writer,
+	values map[string]v1alpha1.ValueConfiguration,
+	manifest *v1alpha1.PackageManifest,
+) {
+	for name, value := range manifestvalues.Redact(values, manifest) {
 		util.Must(fmt.Fprintf(w, " * %v: %v\n", name, manifestvalues.ValueAsString(value)))
 	}
 }
 
+func printRevisions(w io.Writer, revisions []v1alpha1.PackageRevision) {
+	tw := tabwriter.NewWriter(w, 0, 0, 1, ' ', 0)
+	for i := len(revisions) - 1; i >= 0; i-- {
+		revision := revisions[i]
+		util.Must(fmt.Fprintf(tw, " * %v:\t%v\t%v\t%v\n", revision.Revision, revision.Version,
+			revision.Timestamp.Format("2006-01-02 15:04:05"), revision.Outcome))
+	}
+	_ = tw.Flush()
+}
+
 func printMarkdown(w io.Writer, text string) {
 	md := goldmark.New(
 		goldmark.WithExtensions(
@@ -413,6 +447,7 @@ func createOutputStructure(
 	manifest *v1alpha1.PackageManifest,
 	latestVersion string,
 	repos []v1alpha1.PackageRepository,
+	signature describe.SignatureVerification,
 ) map[string]interface{} {
 	data := map[string]interface{}{
 		"packageName":      manifest.Name,
@@ -425,10 +460,11 @@ func createOutputStructure(
 		"longDescription":  strings.TrimSpace(manifest.LongDescription),
 		"repositories":     repositoriesAsMap(pkg, repos),
 		"references":       referencesAsMap(ctx, pkg, manifest),
+		"signature":        signature.AsMap(),
 	}
 	if !pkg.IsNil() {
 		data["desiredVersion"] = pkg.GetSpec().PackageInfo.Version
-		data["configuration"] = pkg.GetSpec().Values
+		data["configuration"] = manifestvalues.Redact(pkg.GetSpec().Values, manifest)
 		data["version"] = pkg.GetStatus().Version
 		data["autoUpdate"] = pkg.AutoUpdatesEnabled()
 		data["isUpgradable"] = semver.IsUpgradable(pkg.GetSpec().PackageInfo.Version, latestVersion)
@@ -436,7 +472,12 @@ func createOutputStructure(
 		data["suspend"] = pkg.GetSpec().Suspend
 	}
 	if len(instances) > 0 {
-		data["instances"] = instances
+		redactedInstances := make([]v1alpha1.Package, len(instances))
+		for i, instance := range instances {
+			instance.Spec.Values = manifestvalues.Redact(instance.Spec.Values, manifest)
+			redactedInstances[i] = instance
+		}
+		data["instances"] = redactedInstances
 	}
 	return data
 }
@@ -446,8 +487,9 @@ func printJSON(ctx context.Context,
 	pkgs []v1alpha1.Package,
 	manifest *v1alpha1.PackageManifest,
 	latestVersion string,
-	repos []v1alpha1.PackageRepository) {
-	output := createOutputStructure(ctx, pkg, pkgs, manifest, latestVersion, repos)
+	repos []v1alpha1.PackageRepository,
+	signature describe.SignatureVerification) {
+	output := createOutputStructure(ctx, pkg, pkgs, manifest, latestVersion, repos, signature)
 	jsonOutput, err := json.MarshalIndent(output, "", "  ")
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "❌ Could not marshal JSON output: %v\n", err)
@@ -461,8 +503,9 @@ func printYAML(ctx context.Context,
 	pkgs []v1alpha1.Package,
 	manifest *v1alpha1.PackageManifest,
 	latestVersion string,
-	repos []v1alpha1.PackageRepository) {
-	output := createOutputStructure(ctx, pkg, pkgs, manifest, latestVersion, repos)
+	repos []v1alpha1.PackageRepository,
+	signature describe.SignatureVerification) {
+	output := createOutputStructure(ctx, pkg, pkgs, manifest, latestVersion, repos, signature)
 	yamlOutput, err := yaml.Marshal(output)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "❌ Could not marshal YAML output: %v\n", err)
@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/glasskube/glasskube/api/v1alpha1"
+	"github.com/glasskube/glasskube/internal/cliutils"
+	"github.com/glasskube/glasskube/internal/controller/ctrlpkg"
+	"github.com/glasskube/glasskube/pkg/condition"
+	"github.com/glasskube/glasskube/pkg/update"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/api/meta"
+)
+
+var driftCmdOptions = struct {
+	KindOptions
+	NamespaceOptions
+}{
+	KindOptions: DefaultKindOptions(),
+}
+
+var driftCmd = &cobra.Command{
+	Use:   "drift [package-name]",
+	Short: "Show resources that differ from their desired state",
+	Long: "Shows the resources of a package that were changed outside of Glasskube, as found by the last drift " +
+		"detection.\n" +
+		"Drift detection must be enabled for a package by setting spec.driftPolicy to \"Correct\" or \"Report\".\n" +
+		"If no package is given, all packages with drift detection enabled are checked.\n" +
+		"Exits with a non-zero exit code if drift was found.",
+	Args:   cobra.MaximumNArgs(1),
+	PreRun: cliutils.SetupClientContext(true, &rootCmdOptions.SkipUpdateCheck),
+	ValidArgsFunction: installedPackagesCompletionFunc(
+		&driftCmdOptions.NamespaceOptions,
+		&driftCmdOptions.KindOptions,
+	),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		var pkgs []ctrlpkg.Package
+		if len(args) > 0 {
+			if pkg, err := getPackageOrClusterPackage(ctx, args[0],
+				driftCmdOptions.KindOptions, driftCmdOptions.NamespaceOptions); err != nil {
+				fmt.Fprintf(os.Stderr, "❌ Could not get %v: %v\n", args[0], err)
+				cliutils.ExitWithError()
+			} else if pkg.GetSpec().DriftPolicy == "" {
+				fmt.Fprintf(os.Stderr, "❌ Drift detection is not enabled for %v\n", pkg.GetName())
+				cliutils.ExitWithError()
+			} else {
+				pkgs = append(pkgs, pkg)
+			}
+		} else {
+			var getters []update.PackagesGetter
+			if driftCmdOptions.Namespace != "" {
+				getters = append(getters, update.GetAllPackages(driftCmdOptions.Namespace))
+			} else {
+				switch driftCmdOptions.Kind {
+				case KindClusterPackage:
+					getters = append(getters, update.GetAllClusterPackages())
+				case KindPackage:
+					getters = append(getters, update.GetAllPackages(""))
+				default:
+					getters = append(getters, update.GetAllClusterPackages(), update.GetAllPackages(""))
+				}
+			}
+			for _, getter := range getters {
+				if result, err := getter.Get(ctx); err != nil {
+					fmt.Fprintf(os.Stderr, "❌ Could not list packages: %v\n", err)
+					cliutils.ExitWithError()
+				} else {
+					for _, pkg := range result {
+						if pkg.GetSpec().DriftPolicy != "" {
+							pkgs = append(pkgs, pkg)
+						}
+					}
+				}
+			}
+			if len(pkgs) == 0 {
+				fmt.Fprintln(os.Stderr, "Drift detection is not enabled for any package")
+				cliutils.ExitSuccess()
+			}
+		}
+
+		var drifted bool
+		for _, pkg := range pkgs {
+			drifted = printDrift(os.Stdout, pkg) || drifted
+		}
+		if drifted {
+			cliutils.ExitWithError()
+		}
+	},
+}
+
+// printDrift prints the drift of pkg as recorded in its status and returns true if it has drifted.
+func printDrift(w io.Writer, pkg ctrlpkg.Package) bool {
+	status := pkg.GetStatus()
+	name := pkg.GetName()
+	if pkg.IsNamespaceScoped() {
+		name = pkg.GetNamespace() + "/" + name
+	}
+
+	if len(status.Drift) == 0 {
+		fmt.Fprintf(w, "✅ %v has not drifted\n", name)
+		if c := meta.FindStatusCondition(status.Conditions, string(condition.Drifted)); c != nil &&
+			c.Reason == string(condition.DriftCorrected) {
+			fmt.Fprintf(w, "   Drift was corrected at %v: %v\n", c.LastTransitionTime, c.Message)
+		}
+		return false
+	}
+
+	fmt.Fprintf(w, "⚠️  %v has drifted (policy: %v)\n", name, pkg.GetSpec().DriftPolicy)
+	for _, resourceDrift := range status.Drift {
+		printResourceDrift(w, resourceDrift)
+	}
+	return true
+}
+
+func printResourceDrift(w io.Writer, resourceDrift v1alpha1.ResourceDrift) {
+	name := resourceDrift.Name
+	if resourceDrift.Namespace != "" {
+		name = resourceDrift.Namespace + "/" + name
+	}
+	if resourceDrift.Missing {
+		fmt.Fprintf(w, "--- %v %v (deleted)\n", resourceDrift.Kind, name)
+		return
+	}
+	fmt.Fprintf(w, "--- %v %v (desired)\n", resourceDrift.Kind, name)
+	fmt.Fprintf(w, "+++ %v %v (live)\n", resourceDrift.Kind, name)
+	for _, field := range resourceDrift.Fields {
+		fmt.Fprintf(w, "@@ %v @@\n", field.Path)
+		if field.Desired != "" {
+			fmt.Fprintf(w, "-%v\n", field.Desired)
+		}
+		if field.Live != "" {
+			fmt.Fprintf(w, "+%v\n", field.Live)
+		}
+	}
+}
+
+func init() {
+	driftCmdOptions.KindOptions.AddFlagsToCommand(driftCmd)
+	driftCmdOptions.NamespaceOptions.AddFlagsToCommand(driftCmd)
+	RootCmd.AddCommand(driftCmd)
+}
@@ -0,0 +1,200 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/glasskube/glasskube/api/v1alpha1"
+	"github.com/glasskube/glasskube/internal/cliutils"
+	"github.com/glasskube/glasskube/internal/controller/ctrlpkg"
+	"github.com/glasskube/glasskube/internal/dependency/graph"
+	"github.com/glasskube/glasskube/internal/repo"
+	repoclient "github.com/glasskube/glasskube/internal/repo/client"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/api/errors"
+)
+
+type graphFormat graph.Format
+
+func (f *graphFormat) String() string {
+	return string(*f)
+}
+
+func (f *graphFormat) Set(value string) error {
+	if !slices.Contains(graph.Formats, graph.Format(value)) {
+		return fmt.Errorf("invalid graph format: %s", value)
+	}
+	*f = graphFormat(value)
+	return nil
+}
+
+func (f *graphFormat) Type() string {
+	formats := make([]string, len(graph.Formats))
+	for i, format := range graph.Formats {
+		formats[i] = string(format)
+	}
+	return fmt.Sprintf("(%v)", strings.Join(formats, "|"))
+}
+
+var graphCmdOptions = struct {
+	Format     graphFormat
+	Version    string
+	Repository string
+	KindOptions
+	NamespaceOptions
+}{
+	Format:      graphFormat(graph.FormatTree),
+	KindOptions: DefaultKindOptions(),
+}
+
+var graphCmd = &cobra.Command{
+	Use:   "graph [<package-name>]",
+	Short: "Show the dependency graph",
+	Long: "Shows the dependency graph of all installed packages.\n" +
+		"If a package is given, only this package and its dependencies are shown. If the package is not installed " +
+		"or --version differs from the installed version, the graph shows the state after installing or updating " +
+		"the package, including all dependencies that would be installed.\n" +
+		"Dependencies are annotated with their version constraints. Manually installed packages and unmet " +
+		"dependencies are highlighted.",
+	Args:              cobra.MaximumNArgs(1),
+	PreRun:            cliutils.SetupClientContext(true, &rootCmdOptions.SkipUpdateCheck),
+	ValidArgsFunction: completeAvailablePackageNames,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		dm := cliutils.DependencyManager(ctx)
+		if graphCmdOptions.Version != "" && !strings.HasPrefix(graphCmdOptions.Version, "v") {
+			graphCmdOptions.Version = "v" + graphCmdOptions.Version
+		}
+
+		var g *graph.DependencyGraph
+		var roots []graph.PackageRef
+		if len(args) == 0 {
+			if result, err := dm.NewGraph(ctx); err != nil {
+				fmt.Fprintf(os.Stderr, "❌ Could not build dependency graph: %v\n", err)
+				cliutils.ExitWithError()
+			} else {
+				g = result
+			}
+		} else {
+			pkg, err := getPackageOrClusterPackage(ctx, args[0],
+				graphCmdOptions.KindOptions, graphCmdOptions.NamespaceOptions)
+			if err != nil && !errors.IsNotFound(err) {
+				fmt.Fprintf(os.Stderr, "❌ Could not get %v: %v\n", args[0], err)
+				cliutils.ExitWithError()
+			}
+			if pkg != nil && (graphCmdOptions.Version == "" ||
+				graphCmdOptions.Version == pkg.GetSpec().PackageInfo.Version) {
+				if result, err := dm.NewGraph(ctx); err != nil {
+					fmt.Fprintf(os.Stderr, "❌ Could not build dependency graph: %v\n", err)
+					cliutils.ExitWithError()
+				} else {
+					g = result
+					roots = append(roots, graphRootForPackage(pkg))
+				}
+			} else {
+				g, roots = simulateInstallGraph(cmd, args[0], pkg)
+			}
+		}
+
+		if err := g.Render(os.Stdout, graph.Format(graphCmdOptions.Format), roots...); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Could not render dependency graph: %v\n", err)
+			cliutils.ExitWithError()
+		}
+		if slices.ContainsFunc(g.Nodes(), func(n graph.Node) bool { return n.Conflict }) {
+			fmt.Fprintln(os.Stderr, "⚠️  The dependency graph contains unmet dependencies")
+		}
+	},
+}
+
+func graphRootForPackage(pkg ctrlpkg.Package) graph.PackageRef {
+	if pkg.IsNamespaceScoped() {
+		return graph.PackageRef{Name: pkg.GetName(), Namespace: pkg.GetNamespace()}
+	} else {
+		return graph.PackageRef{Name: pkg.GetSpec().PackageInfo.Name}
+	}
+}
+
+// simulateInstallGraph returns the dependency graph after installing the package with the given name. If pkg is not
+// nil, it is updated instead.
+func simulateInstallGraph(
+	cmd *cobra.Command,
+	name string,
+	pkg ctrlpkg.Package,
+) (*graph.DependencyGraph, []graph.PackageRef) {
+	ctx := cmd.Context()
+	repoClientset := cliutils.RepositoryClientset(ctx)
+	packageName, repositoryName := name, graphCmdOptions.Repository
+	if pkg != nil {
+		packageName = pkg.GetSpec().PackageInfo.Name
+		if repositoryName == "" {
+			repositoryName = pkg.GetSpec().PackageInfo.RepositoryName
+		}
+	}
+
+	var repoClient repoclient.RepoClient
+	if repositoryName != "" {
+		repoClient = repoClientset.ForRepoWithName(repositoryName)
+	} else if repos, err := repoClientset.Meta().GetReposForPackage(packageName); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Could not collect repository list: %v\n", err)
+		cliutils.ExitWithError()
+	} else if len(repos) == 0 {
+		fmt.Fprintf(os.Stderr, "❌ %v is neither installed nor available\n", packageName)
+		cliutils.ExitWithError()
+	} else if len(repos) > 1 {
+		fmt.Fprintf(os.Stderr, "❌ %v is available from %v repositories. Please specify one with --repository\n",
+			packageName, len(repos))
+		cliutils.ExitWithError()
+	} else {
+		repoClient = repoClientset.ForRepo(repos[0])
+	}
+
+	version := graphCmdOptions.Version
+	if version == "" {
+		var packageIndex repo.PackageIndex
+		if err := repoClient.FetchPackageIndex(packageName, &packageIndex); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Could not fetch package metadata: %v\n", err)
+			cliutils.ExitWithError()
+		}
+		version = packageIndex.LatestVersion
+	}
+
+	var manifest v1alpha1.PackageManifest
+	if err := repoClient.FetchPackageManifest(packageName, version, &manifest); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Could not fetch package manifest: %v\n", err)
+		cliutils.ExitWithError()
+	}
+
+	var root graph.PackageRef
+	if pkg != nil {
+		root = graphRootForPackage(pkg)
+	} else if manifest.Scope.IsCluster() {
+		root = graph.PackageRef{Name: manifest.Name}
+	} else {
+		root = graph.PackageRef{Name: name, Namespace: graphCmdOptions.GetActualNamespace(ctx)}
+	}
+
+	if pkg != nil {
+		fmt.Fprintf(os.Stderr, "Showing the dependency graph after updating %v to %v\n", root, version)
+	} else {
+		fmt.Fprintf(os.Stderr, "Showing the dependency graph after installing %v %v\n", root, version)
+	}
+	g, err := cliutils.DependencyManager(ctx).NewGraphWithPackage(ctx, root.Name, root.Namespace, &manifest, version)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Could not build dependency graph: %v\n", err)
+		cliutils.ExitWithError()
+	}
+	return g, []graph.PackageRef{root}
+}
+
+func init() {
+	graphCmd.Flags().VarP(&graphCmdOptions.Format, "format", "f", "Output format of the graph")
+	graphCmd.Flags().StringVarP(&graphCmdOptions.Version, "version", "v", "",
+		"Show the graph after installing or updating to this version of the package")
+	graphCmd.Flags().StringVar(&graphCmdOptions.Repository, "repository", "",
+		"Specify the repository of the package if it is not installed")
+	graphCmdOptions.KindOptions.AddFlagsToCommand(graphCmd)
+	graphCmdOptions.NamespaceOptions.AddFlagsToCommand(graphCmd)
+	RootCmd.AddCommand(graphCmd)
+}
@@ -24,6 +24,7 @@ import (
 	"github.com/glasskube/glasskube/internal/util"
 	"github.com/glasskube/glasskube/pkg/client"
 	"github.com/glasskube/glasskube/pkg/condition"
+	"github.com/glasskube/glasskube/pkg/describe"
 	"github.com/glasskube/glasskube/pkg/install"
 	"github.com/glasskube/glasskube/pkg/statuswriter"
 	"github.com/spf13/cobra"
@@ -132,6 +133,7 @@ var installCmd = &cobra.Command{
 			fmt.Fprintf(os.Stderr, "❗ Error: Could not fetch package manifest: %v\n", err)
 			cliutils.ExitWithError()
 		}
+		signature := describe.VerifySignature(repoClient, packageName, installCmdOptions.Version)
 
 		installationPlan := []dependency.Requirement{}
 		if manifest.Scope.IsCluster() {
@@ -193,19 +195,10 @@ var installCmd = &cobra.Command{
 				installCmdOptions.EnableAutoUpdates = true
 			}
 		}
-		if installCmdOptions.EnableAutoUpdates {
-			ok, err := clientutils.IsAutoUpdaterInstalled(ctx)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "! Error: Could not check whether glasskube-autoupdater is installed: %v\n", err)
-			}
-			if !ok {
-				fmt.Fprintf(os.Stderr, "Please install glasskube-autoupdater for automatic updates to be applied.\n")
-			}
-		}
-
 		pkgBuilder.WithAutoUpdates(installCmdOptions.EnableAutoUpdates)
 
 		pkg := pkgBuilder.Build(manifest.Scope)
+		installer.WithSecretValues(cs, &manifest)
 
 		if validationResult, err :=
 			dm.Validate(ctx, pkg.GetName(), pkg.GetNamespace(), &manifest, installCmdOptions.Version); err != nil {
@@ -229,6 +222,7 @@ var installCmd = &cobra.Command{
 		} else {
 			fmt.Fprintln(os.Stderr, " * Automatic updates will be", bold("not enabled"))
 		}
+		fmt.Fprintln(os.Stderr, " * Package manifest signature:", signature)
 
 		createNamespace := false
 		if installCmdOptions.NamespaceOptions.Namespace != "" {
@@ -244,8 +238,8 @@ var installCmd = &cobra.Command{
 
 		if len(pkg.GetSpec().Values) > 0 {
 			fmt.Fprintln(os.Stderr, bold("Configuration:"))
-			printValueConfigurations(os.Stderr, pkg.GetSpec().Values)
-			if _, err := valueResolver.Resolve(ctx, pkg.GetSpec().Values); err != nil {
+			printValueConfigurations(os.Stderr, pkg.GetSpec().Values, &manifest)
+			if _, err := valueResolver.ResolvePackage(ctx, pkg); err != nil {
 				fmt.Fprintf(os.Stderr, "⚠️  Some values can not be resolved: %v\n", err)
 			}
 		}
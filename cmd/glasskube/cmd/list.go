@@ -5,11 +5,14 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/glasskube/glasskube/internal/controller/ctrlpkg"
 
 	"github.com/glasskube/glasskube/internal/clientutils"
 	"github.com/glasskube/glasskube/internal/cliutils"
+	"github.com/glasskube/glasskube/internal/maintenancewindow"
+	"github.com/glasskube/glasskube/internal/manifestvalues"
 	"github.com/glasskube/glasskube/internal/semver"
 	"github.com/glasskube/glasskube/pkg/list"
 	"github.com/spf13/cobra"
@@ -83,6 +86,10 @@ var listCmd = &cobra.Command{
 		noPkgs := len(pkgs) == 0 && listCmdOptions.Kind != KindClusterPackage
 		noClPkgs := len(clPkgs) == 0 && listCmdOptions.Kind != KindPackage &&
 			listCmdOptions.packageName == "" && listCmdOptions.Namespace == ""
+		defaultSchedule, err := clientutils.GetDefaultMaintenanceWindow(ctx)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  Could not get default maintenance window: %v\n", err)
+		}
 		if listCmdOptions.Output == outputFormatJSON {
 			printPackageJSON(allPkgs(clPkgs, pkgs))
 		} else if listCmdOptions.Output == outputFormatYAML {
@@ -91,7 +98,7 @@ var listCmd = &cobra.Command{
 			if noPkgs {
 				handleEmptyList("packages")
 			} else if len(pkgs) > 0 {
-				printPackageTable(pkgs)
+				printPackageTable(pkgs, defaultSchedule)
 			}
 			if noClPkgs {
 				handleEmptyList("clusterpackages")
@@ -99,7 +106,7 @@ var listCmd = &cobra.Command{
 				if len(pkgs) > 0 {
 					fmt.Fprintln(os.Stderr, "")
 				}
-				printClusterPackageTable(clPkgs)
+				printClusterPackageTable(clPkgs, defaultSchedule)
 			}
 		}
 	},
@@ -161,7 +168,8 @@ func allPkgs(clpkgs []*list.PackageWithStatus, pkgs []*list.PackagesWithStatus)
 	return result
 }
 
-func printClusterPackageTable(packages []*list.PackageWithStatus) {
+func printClusterPackageTable(packages []*list.PackageWithStatus, defaultSchedule maintenancewindow.Schedule) {
+	now := time.Now()
 	header := []string{"NAME", "VERSION", "AUTO-UPDATE", "SUSPENDED"}
 	if listCmdOptions.ShowLatestVersion {
 		header = append(header, "LATEST VERSION")
@@ -180,7 +188,7 @@ func printClusterPackageTable(packages []*list.PackageWithStatus) {
 		header,
 		func(pkg *list.PackageWithStatus) []string {
 			row := []string{pkg.Name, versionString(*pkg),
-				clientutils.AutoUpdateString(pkg.ClusterPackage, "")}
+				clientutils.AutoUpdateWindowString(pkg.ClusterPackage, defaultSchedule, now, "")}
 			if pkg.ClusterPackage != nil {
 				row = append(row, boolYesNo(pkg.ClusterPackage.Spec.Suspend))
 			} else {
@@ -217,7 +225,8 @@ func printClusterPackageTable(packages []*list.PackageWithStatus) {
 	}
 }
 
-func printPackageTable(packages []*list.PackagesWithStatus) {
+func printPackageTable(packages []*list.PackagesWithStatus, defaultSchedule maintenancewindow.Schedule) {
+	now := time.Now()
 	header := []string{"PACKAGENAME", "NAMESPACE", "NAME", "VERSION", "AUTO-UPDATE", "SUSPENDED"}
 	if listCmdOptions.ShowLatestVersion {
 		header = append(header, "LATEST VERSION")
@@ -247,7 +256,7 @@ func printPackageTable(packages []*list.PackagesWithStatus) {
 		header,
 		func(pkg *list.PackageWithStatus) []string {
 			row := []string{pkg.Name, pkgNamespaceString(*pkg), pkgNameString(*pkg), versionString(*pkg),
-				clientutils.AutoUpdateString(pkg.Package, "")}
+				clientutils.AutoUpdateWindowString(pkg.Package, defaultSchedule, now, "")}
 			if pkg.Package != nil {
 				row = append(row, boolYesNo(pkg.Package.Spec.Suspend))
 			} else {
@@ -284,7 +293,23 @@ func printPackageTable(packages []*list.PackagesWithStatus) {
 	}
 }
 
+// redactValues hides the inline secret values of all installed packages.
+func redactValues(packages []*list.PackageWithStatus) {
+	for _, pkg := range packages {
+		if pkg.InstalledManifest == nil {
+			continue
+		}
+		if pkg.Package != nil {
+			pkg.Package.Spec.Values = manifestvalues.Redact(pkg.Package.Spec.Values, pkg.InstalledManifest)
+		}
+		if pkg.ClusterPackage != nil {
+			pkg.ClusterPackage.Spec.Values = manifestvalues.Redact(pkg.ClusterPackage.Spec.Values, pkg.InstalledManifest)
+		}
+	}
+}
+
 func printPackageJSON(packages []*list.PackageWithStatus) {
+	redactValues(packages)
 	enc := json.NewEncoder(os.Stdout)
 	enc.SetIndent("", "    ")
 	err := enc.Encode(packages)
@@ -295,6 +320,7 @@ func printPackageJSON(packages []*list.PackageWithStatus) {
 }
 
 func printPackageYAML(packages []*list.PackageWithStatus) {
+	redactValues(packages)
 	for i, pkg := range packages {
 		yamlData, err := yaml.Marshal(pkg)
 		if err != nil {
@@ -38,12 +38,21 @@ var repoAddCmd = &cobra.Command{
 				Name: repoName,
 			},
 			Spec: v1alpha1.PackageRepositorySpec{
-				Url: repoAddCmdOptions.Url,
+				Url:          repoAddCmdOptions.Url,
+				Git:          repoAddCmdOptions.GitSpec(),
+				SyncInterval: repoAddCmdOptions.SyncIntervalSpec(),
 			},
 		}
 
 		repo.Spec.Auth = repoAddCmdOptions.SetAuth()
 
+		if keys, err := repoAddCmdOptions.TrustedPublicKeys(); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			cliutils.ExitWithError()
+		} else {
+			repo.Spec.TrustedPublicKeys = keys
+		}
+
 		if repoAddCmdOptions.Default {
 			defaultRepo, err = cliutils.GetDefaultRepo(ctx)
 
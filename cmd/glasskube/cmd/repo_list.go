@@ -36,7 +36,9 @@ var repoListCmd = &cobra.Command{
 			func(repo v1alpha1.PackageRepository) []string {
 				condition := meta.FindStatusCondition(repo.Status.Conditions, string(condition.Ready))
 				authType := "None"
-				if repo.Spec.Auth != nil {
+				if repo.IsGitRepo() && repo.Spec.Git.SecretRef != nil {
+					authType = "Git secret " + repo.Spec.Git.SecretRef.Name
+				} else if repo.Spec.Auth != nil {
 					if repo.Spec.Auth.Basic != nil {
 						authType = "Basic"
 					} else if repo.Spec.Auth.Bearer != nil {
@@ -59,9 +61,20 @@ var repoListCmd = &cobra.Command{
 					isDefRepo = "Yes"
 				}
 
+				url := repo.Spec.Url
+				if repo.IsGitRepo() {
+					url = "git: " + url
+					if repo.Spec.Git.Ref != "" {
+						url += "@" + repo.Spec.Git.Ref
+					}
+					if repo.Spec.Git.Path != "" {
+						url += " (" + repo.Spec.Git.Path + ")"
+					}
+				}
+
 				return []string{
 					repo.Name,
-					repo.Spec.Url,
+					url,
 					isDefRepo,
 					authType,
 					status,
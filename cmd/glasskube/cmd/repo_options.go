@@ -5,10 +5,13 @@ import (
 	"fmt"
 	"net/url"
 	"os"
+	"time"
 
 	"github.com/glasskube/glasskube/api/v1alpha1"
 	"github.com/glasskube/glasskube/internal/cliutils"
 	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 type repoAuthType string
@@ -38,12 +41,18 @@ const (
 )
 
 type repoOptions struct {
-	Default  bool
-	Auth     repoAuthType
-	Username string
-	Password string
-	Token    string
-	Url      string
+	Default      bool
+	Auth         repoAuthType
+	Username     string
+	Password     string
+	Token        string
+	Url          string
+	Git          bool
+	GitRef       string
+	GitPath      string
+	GitSecret    string
+	SyncInterval time.Duration
+	TrustedKeys  []string
 }
 
 func (opts *repoOptions) BindToCmdFlags(cmd *cobra.Command, update bool) {
@@ -55,12 +64,28 @@ func (opts *repoOptions) BindToCmdFlags(cmd *cobra.Command, update bool) {
 	cmd.Flags().StringVar(&opts.Username, "username", opts.Username, "Username for basic authentication")
 	cmd.Flags().StringVar(&opts.Password, "password", opts.Password, "Password for basic authentication")
 	cmd.Flags().StringVar(&opts.Token, "token", opts.Token, "Token for bearer authentication")
+	cmd.Flags().BoolVar(&opts.Git, "git", opts.Git, "The url refers to a git repository")
+	cmd.Flags().StringVar(&opts.GitRef, "git-ref", opts.GitRef,
+		"Branch, tag or commit of the git repository (default is the default branch)")
+	cmd.Flags().StringVar(&opts.GitPath, "git-path", opts.GitPath,
+		"Directory in the git repository that contains the index.yaml file")
+	cmd.Flags().StringVar(&opts.GitSecret, "git-secret", opts.GitSecret,
+		"Name of a secret in the glasskube-system namespace that contains git credentials")
+	cmd.Flags().DurationVar(&opts.SyncInterval, "sync-interval", opts.SyncInterval,
+		"Interval in which the repository and its packages are synchronized (default is 5m for packages)")
+	cmd.Flags().StringArrayVar(&opts.TrustedKeys, "trusted-key", opts.TrustedKeys,
+		"Path to a PEM encoded public key. If set, package manifests must be signed by one of the trusted keys\n"+
+			"(can be specified multiple times)")
 	cmd.MarkFlagsMutuallyExclusive("username", "token")
 	cmd.MarkFlagsMutuallyExclusive("password", "token")
 }
 
 func (opts *repoOptions) Normalize() error {
-	if len(opts.Url) > 0 {
+	if len(opts.GitRef) > 0 || len(opts.GitPath) > 0 || len(opts.GitSecret) > 0 {
+		opts.Git = true
+	}
+
+	if len(opts.Url) > 0 && !opts.Git {
 		if _, err := url.ParseRequestURI(opts.Url); err != nil {
 			return fmt.Errorf("use a valid URL for the package repository (got %v)", opts.Url)
 		}
@@ -82,6 +107,42 @@ func (opts *repoOptions) Normalize() error {
 	return nil
 }
 
+// GitSpec returns the git configuration for the repository or nil if the repository is not a git repository.
+func (opts *repoOptions) GitSpec() *v1alpha1.PackageRepositoryGitSpec {
+	if !opts.Git {
+		return nil
+	}
+	spec := v1alpha1.PackageRepositoryGitSpec{
+		Ref:  opts.GitRef,
+		Path: opts.GitPath,
+	}
+	if len(opts.GitSecret) > 0 {
+		spec.SecretRef = &corev1.LocalObjectReference{Name: opts.GitSecret}
+	}
+	return &spec
+}
+
+// SyncIntervalSpec returns the sync interval of the repository or nil if the default should be used.
+func (opts *repoOptions) SyncIntervalSpec() *metav1.Duration {
+	if opts.SyncInterval <= 0 {
+		return nil
+	}
+	return &metav1.Duration{Duration: opts.SyncInterval}
+}
+
+// TrustedPublicKeys returns the contents of the trusted key files.
+func (opts *repoOptions) TrustedPublicKeys() ([]string, error) {
+	var keys []string
+	for _, file := range opts.TrustedKeys {
+		if data, err := os.ReadFile(file); err != nil {
+			return nil, fmt.Errorf("could not read trusted key: %w", err)
+		} else {
+			keys = append(keys, string(data))
+		}
+	}
+	return keys, nil
+}
+
 func (opts *repoOptions) SetAuth() *v1alpha1.PackageRepositoryAuthSpec {
 	switch opts.Auth {
 	case repoBasicAuth:
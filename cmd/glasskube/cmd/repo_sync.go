@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/glasskube/glasskube/api/v1alpha1"
+	"github.com/glasskube/glasskube/internal/cliutils"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var repoSyncCmd = &cobra.Command{
+	Use:   "sync <name>",
+	Short: "Synchronize a package repository and the packages installed from it now",
+	Long: "Synchronize a package repository and the packages installed from it now.\n" +
+		"This is useful to pick up packages that were published recently, without waiting for the sync interval.",
+	Args:   cobra.ExactArgs(1),
+	PreRun: cliutils.SetupClientContext(true, &rootCmdOptions.SkipUpdateCheck),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		client := cliutils.PackageClient(ctx)
+		repoName := args[0]
+
+		var repo v1alpha1.PackageRepository
+		if err := client.PackageRepositories().Get(ctx, repoName, &repo); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ error getting the package repository: %v\n", err)
+			cliutils.ExitWithError()
+		}
+
+		if repo.Annotations == nil {
+			repo.Annotations = make(map[string]string)
+		}
+		repo.Annotations[v1alpha1.SyncRequestedAnnotation] = time.Now().Format(time.RFC3339)
+
+		if err := client.PackageRepositories().Update(ctx, &repo, metav1.UpdateOptions{}); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ error requesting sync of the package repository: %v\n", err)
+			cliutils.ExitWithError()
+		}
+
+		fmt.Fprintf(os.Stderr, "✅ sync of package repository %v requested\n", repoName)
+		cliutils.ExitSuccess()
+	},
+}
+
+func init() {
+	repoCmd.AddCommand(repoSyncCmd)
+}
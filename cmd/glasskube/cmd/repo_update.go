@@ -48,6 +48,23 @@ var repoUpdateCmd = &cobra.Command{
 			repo.Spec.Url = repoUpdateCmdOptions.Url
 		}
 
+		if repoUpdateCmdOptions.Git {
+			repo.Spec.Git = repoUpdateCmdOptions.GitSpec()
+		}
+
+		if cmd.Flags().Changed("trusted-key") {
+			if keys, err := repoUpdateCmdOptions.TrustedPublicKeys(); err != nil {
+				fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+				cliutils.ExitWithError()
+			} else {
+				repo.Spec.TrustedPublicKeys = keys
+			}
+		}
+
+		if cmd.Flags().Changed("sync-interval") {
+			repo.Spec.SyncInterval = repoUpdateCmdOptions.SyncIntervalSpec()
+		}
+
 		if repoUpdateCmdOptions.Default {
 			defaultRepo, err = cliutils.GetDefaultRepo(ctx)
 
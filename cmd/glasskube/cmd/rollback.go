@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/glasskube/glasskube/api/v1alpha1"
+	"github.com/glasskube/glasskube/internal/cliutils"
+	"github.com/glasskube/glasskube/internal/revisions"
+	"github.com/glasskube/glasskube/pkg/manifest"
+	"github.com/glasskube/glasskube/pkg/statuswriter"
+	"github.com/glasskube/glasskube/pkg/update"
+	"github.com/spf13/cobra"
+)
+
+var rollbackCmdOptions = struct {
+	Revision int64
+	Yes      bool
+	DryRunOptions
+	KindOptions
+	NamespaceOptions
+}{
+	KindOptions: DefaultKindOptions(),
+}
+
+var rollbackCmd = &cobra.Command{
+	Use:   "rollback <package-name>",
+	Short: "Restore a previous revision of a package",
+	Long: "Restores the version and values of a previous revision of a package.\n" +
+		"If no revision is given, the most recent successful revision that differs from the current one is restored.\n" +
+		"Use \"glasskube describe\" to show the revision history of a package.",
+	Args:   cobra.ExactArgs(1),
+	PreRun: cliutils.SetupClientContext(true, &rootCmdOptions.SkipUpdateCheck),
+	ValidArgsFunction: installedPackagesCompletionFunc(
+		&rollbackCmdOptions.NamespaceOptions,
+		&rollbackCmdOptions.KindOptions,
+	),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		pkg, err := getPackageOrClusterPackage(ctx, args[0],
+			rollbackCmdOptions.KindOptions, rollbackCmdOptions.NamespaceOptions)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Could not get %v: %v\n", args[0], err)
+			cliutils.ExitWithError()
+		}
+
+		var revision *v1alpha1.PackageRevision
+		if rollbackCmdOptions.Revision > 0 {
+			revision, err = revisions.Find(pkg.GetStatus().Revisions, rollbackCmdOptions.Revision)
+		} else {
+			revision, err = revisions.Previous(pkg.GetStatus().Revisions, *pkg.GetSpec())
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Cannot roll back %v: %v\n", pkg.GetName(), err)
+			cliutils.ExitWithError()
+		} else if revisions.Matches(*revision, *pkg.GetSpec()) {
+			fmt.Fprintf(os.Stderr, "☑️  %v already matches revision %v\n", pkg.GetName(), revision.Revision)
+			cliutils.ExitSuccess()
+		}
+
+		updater := update.NewUpdater(ctx)
+		if !rootCmdOptions.NoProgress {
+			updater.WithStatusWriter(statuswriter.Spinner())
+		}
+
+		tx, err := updater.PrepareRollback(ctx, pkg, *revision)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ rollback preparation failed: %v\n", err)
+			cliutils.ExitWithError()
+		} else if len(tx.ConflictItems) > 0 {
+			for _, conflictItem := range tx.ConflictItems {
+				for _, conflict := range conflictItem.Conflicts {
+					fmt.Fprintf(os.Stderr, "❌ Cannot roll back %s due to dependency conflicts: %s\n",
+						conflictItem.Package.GetName(), conflict)
+				}
+			}
+			cliutils.ExitWithError()
+		}
+
+		fmt.Fprintf(os.Stderr, "Rolling back to revision %v (%v, %v):\n",
+			revision.Revision, revision.Timestamp.Format("2006-01-02 15:04:05"), revision.Outcome)
+		printTransaction(*tx)
+		if len(revision.Values) > 0 {
+			if revisionManifest, err := manifest.GetManifestForPackage(ctx, pkg, revision.Version); err != nil {
+				fmt.Fprintf(os.Stderr, "❌ Could not get manifest for %v: %v\n", revision.Version, err)
+				cliutils.ExitWithError()
+			} else {
+				fmt.Fprintln(os.Stderr, "The configuration will be restored to:")
+				printValueConfigurations(os.Stderr, revision.Values, revisionManifest)
+			}
+		}
+		if !rollbackCmdOptions.Yes && !cliutils.YesNoPrompt("Do you want to apply these changes?", false) {
+			fmt.Fprintf(os.Stderr, "⛔ Rollback cancelled. No changes were made.\n")
+			cliutils.ExitSuccess()
+		}
+
+		if _, err := updater.Apply(ctx, tx, update.ApplyUpdateOptions{
+			Blocking: true,
+			DryRun:   rollbackCmdOptions.DryRun,
+		}); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ rollback failed: %v\n", err)
+			cliutils.ExitWithError()
+		}
+
+		fmt.Fprintf(os.Stderr, "✅ %v was rolled back to revision %v\n", pkg.GetName(), revision.Revision)
+		cliutils.ExitSuccess()
+	},
+}
+
+func init() {
+	rollbackCmd.Flags().Int64Var(&rollbackCmdOptions.Revision, "revision", 0,
+		"Number of the revision to restore (default: the previous successful revision)")
+	rollbackCmd.Flags().BoolVarP(&rollbackCmdOptions.Yes, "yes", "y", false, "Do not ask for any confirmation")
+	rollbackCmdOptions.DryRunOptions.AddFlagsToCommand(rollbackCmd)
+	rollbackCmdOptions.KindOptions.AddFlagsToCommand(rollbackCmd)
+	rollbackCmdOptions.NamespaceOptions.AddFlagsToCommand(rollbackCmd)
+	RootCmd.AddCommand(rollbackCmd)
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/glasskube/glasskube/internal/cliutils"
 	"github.com/glasskube/glasskube/internal/config"
 	"github.com/glasskube/glasskube/internal/controller/ctrlpkg"
+	"github.com/glasskube/glasskube/internal/manifestvalues"
 	"github.com/glasskube/glasskube/internal/manifestvalues/cli"
 	"github.com/glasskube/glasskube/internal/repo"
 	"github.com/glasskube/glasskube/internal/semver"
@@ -24,6 +25,7 @@ import (
 	"github.com/glasskube/glasskube/pkg/statuswriter"
 	"github.com/glasskube/glasskube/pkg/update"
 	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/tools/cache"
 )
 
@@ -114,9 +116,8 @@ var updateCmd = &cobra.Command{
 			if len(tx.ConflictItems) > 0 {
 				for _, conflictItem := range tx.ConflictItems {
 					for _, conflict := range conflictItem.Conflicts {
-						fmt.Fprintf(os.Stderr, "❌ Cannot Update %s due to dependency conflicts: %s\n"+
-							" (required: %s, actual: %s)\n",
-							conflictItem.Package.GetName(), conflict.Actual.Name, conflict.Required.Version, conflict.Actual.Version)
+						fmt.Fprintf(os.Stderr, "❌ Cannot Update %s due to dependency conflicts: %s\n",
+							conflictItem.Package.GetName(), conflict)
 					}
 				}
 				cliutils.ExitWithError()
@@ -194,6 +195,19 @@ func printTransaction(tx update.UpdateTransaction) {
 	for _, req := range tx.Pruned {
 		fmt.Fprintf(os.Stderr, " * %v (no longer needed)\n", req.Name)
 	}
+	if len(tx.HeldBackItems) > 0 {
+		fmt.Fprintf(os.Stderr, "The following packages are held back by their update policy:\n")
+	}
+	for _, item := range tx.HeldBackItems {
+		util.Must(fmt.Fprintf(w, " * %v\t%v:\t%v\t(policy %q, latest %v)\n",
+			item.Package.GetSpec().PackageInfo.Name,
+			cache.MetaObjectToName(item.Package),
+			item.Package.GetSpec().PackageInfo.Version,
+			item.Policy,
+			item.Version,
+		))
+	}
+	_ = w.Flush()
 }
 
 func installedPackagesCompletionFunc(
@@ -321,6 +335,16 @@ func updateConfigurationIfNeeded(ctx context.Context, pkg ctrlpkg.Package, newVe
 		}
 	}
 
+	if secret := manifestvalues.ExtractSecretValues(pkg, newManifest); secret != nil {
+		var dryRun []string
+		if updateCmdOptions.DryRun {
+			dryRun = []string{metav1.DryRunAll}
+		}
+		if err := manifestvalues.ApplySecretValues(ctx, cliutils.KubernetesClient(ctx), secret, pkg, dryRun); err != nil {
+			return fmt.Errorf("error storing secret values: %w", err)
+		}
+	}
+
 	return nil
 }
 
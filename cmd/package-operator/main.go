@@ -20,13 +20,16 @@ import (
 	"context"
 	"flag"
 	"os"
+	"time"
 
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 
 	ctrladapter "github.com/glasskube/glasskube/internal/adapter/controllerruntime"
 	"github.com/glasskube/glasskube/internal/dependency"
+	"github.com/glasskube/glasskube/internal/notification"
 	repoclient "github.com/glasskube/glasskube/internal/repo/client"
 	"github.com/glasskube/glasskube/internal/telemetry"
+	"github.com/glasskube/glasskube/internal/tracing"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
@@ -42,11 +45,15 @@ import (
 	packagesv1alpha1 "github.com/glasskube/glasskube/api/v1alpha1"
 	"github.com/glasskube/glasskube/internal/controller"
 	"github.com/glasskube/glasskube/internal/manifest/helm/flux"
+	"github.com/glasskube/glasskube/internal/manifest/kustomize"
 	"github.com/glasskube/glasskube/internal/manifest/plain"
 	"github.com/glasskube/glasskube/internal/webhook"
 	//+kubebuilder:scaffold:imports
 )
 
+// defaultUpdateCheckInterval is the default interval of automatic updates and of update checks.
+const defaultUpdateCheckInterval = time.Hour
+
 var (
 	scheme   = runtime.NewScheme()
 	setupLog = ctrl.Log.WithName("setup")
@@ -63,11 +70,21 @@ func main() {
 	var metricsAddr string
 	var enableLeaderElection bool
 	var probeAddr string
+	var autoUpdateInterval time.Duration
+	var outdatedCheckInterval time.Duration
+	var gitCacheDir string
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.DurationVar(&autoUpdateInterval, "auto-update-interval", defaultUpdateCheckInterval,
+		"The interval at which packages are checked for updates. Set to 0 to disable automatic updates.")
+	flag.DurationVar(&outdatedCheckInterval, "outdated-check-interval", defaultUpdateCheckInterval,
+		"The interval at which packages are checked for updates without updating them, if automatic updates are "+
+			"disabled. The result is exposed as the package_outdated metric. Set to 0 to disable these checks.")
+	flag.StringVar(&gitCacheDir, "git-cache-dir", repoclient.DefaultGitCacheDir(),
+		"The directory in which clones of git package repositories are cached.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -76,6 +93,14 @@ func main() {
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
+	shutdownTracing, err := tracing.Init(context.Background())
+	if err != nil {
+		setupLog.Error(err, "unable to set up tracing")
+		os.Exit(1)
+	} else if tracing.Enabled() {
+		setupLog.Info("tracing enabled")
+	}
+
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		Scheme:                 scheme,
 		Metrics:                metricsserver.Options{BindAddress: metricsAddr},
@@ -99,9 +124,10 @@ func main() {
 		os.Exit(1)
 	}
 
-	repoClient := repoclient.NewClientset(
+	repoClient := repoclient.NewClientsetWithGitCacheDir(
 		ctrladapter.NewPackageClientAdapter(mgr.GetClient()),
 		ctrladapter.NewKubernetesClientAdapter(mgr.GetClient()),
+		gitCacheDir,
 	)
 	dependencyManager := dependency.NewDependencyManager(
 		ctrladapter.NewPackageClientAdapter(mgr.GetClient()),
@@ -109,11 +135,13 @@ func main() {
 	)
 
 	telemetry.InitWithManager(mgr)
+	notification.InitWithManager(mgr)
 	commonReconciler := controller.PackageReconcilerCommon{
 		Client:            mgr.GetClient(),
 		EventRecorder:     mgr.GetEventRecorderFor("package-controller"),
 		Scheme:            mgr.GetScheme(),
 		HelmAdapter:       flux.NewAdapter(),
+		KustomizeAdapter:  kustomize.NewAdapter(),
 		ManifestAdapter:   plain.NewAdapter(),
 		RepoClientset:     repoClient,
 		DependencyManager: dependencyManager,
@@ -147,6 +175,23 @@ func main() {
 		setupLog.Error(err, "unable to create controller", "controller", "PackageRepository")
 		os.Exit(1)
 	}
+	if autoUpdateInterval > 0 || outdatedCheckInterval > 0 {
+		updateCheckInterval := autoUpdateInterval
+		if updateCheckInterval <= 0 {
+			updateCheckInterval = outdatedCheckInterval
+		}
+		if err = (&controller.AutoUpdateReconciler{
+			Client:            mgr.GetClient(),
+			EventRecorder:     mgr.GetEventRecorderFor("auto-update-controller"),
+			RepoClientset:     repoClient,
+			DependencyManager: dependencyManager,
+			Interval:          updateCheckInterval,
+			CheckOnly:         autoUpdateInterval <= 0,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "AutoUpdate")
+			os.Exit(1)
+		}
+	}
 	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
 		if err = (&webhook.PackageValidatingWebhook{
 			Client:             mgr.GetClient(),
@@ -181,7 +226,11 @@ func main() {
 	}))
 
 	setupLog.Info("starting manager")
-	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
+	err = mgr.Start(ctrl.SetupSignalHandler())
+	if err := shutdownTracing(context.Background()); err != nil {
+		setupLog.Error(err, "problem flushing traces")
+	}
+	if err != nil {
 		setupLog.Error(err, "problem running manager")
 		os.Exit(1)
 	}
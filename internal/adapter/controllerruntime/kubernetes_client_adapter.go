@@ -5,6 +5,8 @@ import (
 
 	"github.com/glasskube/glasskube/internal/adapter"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -38,6 +40,22 @@ func (c *ctrlKubernetsClientAdapter) GetSecret(ctx context.Context, name string,
 	}
 }
 
+// GetResource implements adapter.KubernetesClientAdapter.
+func (c *ctrlKubernetsClientAdapter) GetResource(
+	ctx context.Context,
+	gvk schema.GroupVersionKind,
+	name string,
+	namespace string,
+) (*unstructured.Unstructured, error) {
+	var obj unstructured.Unstructured
+	obj.SetGroupVersionKind(gvk)
+	if err := c.client.Get(ctx, ctrlclient.ObjectKey{Name: name, Namespace: namespace}, &obj); err != nil {
+		return nil, err
+	} else {
+		return &obj, nil
+	}
+}
+
 func NewKubernetesClientAdapter(client ctrlclient.Client) adapter.KubernetesClientAdapter {
 	return &ctrlKubernetsClientAdapter{client: client}
 }
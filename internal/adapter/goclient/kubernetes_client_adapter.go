@@ -2,15 +2,24 @@ package goclient
 
 import (
 	"context"
+	"encoding/json"
+	"sync"
 
 	"github.com/glasskube/glasskube/internal/adapter"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery/cached/memory"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/restmapper"
 )
 
 type clientSetKubernetesClientAdapter struct {
-	clientset *kubernetes.Clientset
+	clientset  *kubernetes.Clientset
+	mapper     meta.RESTMapper
+	mapperOnce sync.Once
 }
 
 // GetConfigMap implements adapter.KubernetesClientAdapter.
@@ -29,6 +38,38 @@ func (c *clientSetKubernetesClientAdapter) GetSecret(ctx context.Context, name s
 	return c.clientset.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
 }
 
+// GetResource implements adapter.KubernetesClientAdapter.
+func (c *clientSetKubernetesClientAdapter) GetResource(
+	ctx context.Context,
+	gvk schema.GroupVersionKind,
+	name string,
+	namespace string,
+) (*unstructured.Unstructured, error) {
+	c.mapperOnce.Do(func() {
+		c.mapper = restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(c.clientset.Discovery()))
+	})
+	mapping, err := c.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return nil, err
+	}
+	path := []string{"/apis", gvk.Group, gvk.Version}
+	if gvk.Group == "" {
+		path = []string{"/api", gvk.Version}
+	}
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		path = append(path, "namespaces", namespace)
+	}
+	path = append(path, mapping.Resource.Resource, name)
+	var obj unstructured.Unstructured
+	if data, err := c.clientset.Discovery().RESTClient().Get().AbsPath(path...).DoRaw(ctx); err != nil {
+		return nil, err
+	} else if err := json.Unmarshal(data, &obj); err != nil {
+		return nil, err
+	} else {
+		return &obj, nil
+	}
+}
+
 func NewKubernetesClientAdapter(clientset *kubernetes.Clientset) adapter.KubernetesClientAdapter {
 	return &clientSetKubernetesClientAdapter{clientset: clientset}
 }
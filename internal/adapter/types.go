@@ -5,6 +5,8 @@ import (
 
 	"github.com/glasskube/glasskube/api/v1alpha1"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
 type PackageClientAdapter interface {
@@ -19,6 +21,10 @@ type PackageClientAdapter interface {
 type KubernetesClientAdapter interface {
 	GetSecret(ctx context.Context, name, namespace string) (*v1.Secret, error)
 	GetConfigMap(ctx context.Context, name, namespace string) (*v1.ConfigMap, error)
+	GetResource(ctx context.Context, gvk schema.GroupVersionKind, name, namespace string) (
+		*unstructured.Unstructured,
+		error,
+	)
 }
 
 type RepoAdapter interface {
@@ -0,0 +1,287 @@
+// Package bundle implements self-contained archives of package repository contents, that can be used to install
+// packages in air-gapped clusters.
+//
+// A bundle contains the same files as a package repository, i.e. "index.yaml", "<name>/versions.yaml" and
+// "<name>/<version>/package.yaml" together with all files that are referenced by the package manifest.
+package bundle
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/glasskube/glasskube/internal/repo/types"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	sigsyaml "sigs.k8s.io/yaml"
+)
+
+const (
+	repoIndexFile    = "index.yaml"
+	packageIndexFile = "versions.yaml"
+	manifestFile     = "package.yaml"
+	// maxFileSize limits the size of a single file that is read from an archive.
+	maxFileSize = 64 << 20
+)
+
+type Bundle struct {
+	Index    types.PackageRepoIndex
+	Packages map[string]*Package
+}
+
+type Package struct {
+	Index types.PackageIndex
+	// Versions contains the files of each version of the package, by path relative to the version directory.
+	Versions map[string]map[string][]byte
+}
+
+func New() *Bundle {
+	return &Bundle{Packages: make(map[string]*Package)}
+}
+
+// AddFile adds a file of a package version to the bundle. Indexes are not updated, see UpdateIndexes.
+func (b *Bundle) AddFile(name, version, filePath string, data []byte) {
+	pkg, ok := b.Packages[name]
+	if !ok {
+		pkg = &Package{Versions: make(map[string]map[string][]byte)}
+		b.Packages[name] = pkg
+	}
+	files, ok := pkg.Versions[version]
+	if !ok {
+		files = make(map[string][]byte)
+		pkg.Versions[version] = files
+	}
+	files[filePath] = data
+}
+
+// Merge adds all package versions of other to b. Versions that exist in both bundles are replaced.
+func (b *Bundle) Merge(other *Bundle) {
+	for name, pkg := range other.Packages {
+		for version, files := range pkg.Versions {
+			for filePath, data := range files {
+				b.AddFile(name, version, filePath, data)
+			}
+		}
+	}
+	for _, item := range other.Index.Packages {
+		if i := slices.IndexFunc(b.Index.Packages, func(it types.PackageRepoIndexItem) bool {
+			return it.Name == item.Name
+		}); i >= 0 {
+			b.Index.Packages[i] = item
+		} else {
+			b.Index.Packages = append(b.Index.Packages, item)
+		}
+	}
+	b.UpdateIndexes()
+}
+
+// UpdateIndexes updates the package indexes and the latest versions in the repository index to match the package
+// versions contained in the bundle.
+func (b *Bundle) UpdateIndexes() {
+	for name, pkg := range b.Packages {
+		var versions []*semver.Version
+		var invalid []string
+		for version := range pkg.Versions {
+			if v, err := semver.NewVersion(version); err != nil {
+				invalid = append(invalid, version)
+			} else {
+				versions = append(versions, v)
+			}
+		}
+		slices.SortFunc(versions, func(a, b *semver.Version) int { return a.Compare(b) })
+		slices.Sort(invalid)
+		pkg.Index.Versions = nil
+		for _, version := range invalid {
+			pkg.Index.Versions = append(pkg.Index.Versions, types.PackageIndexItem{Version: version})
+		}
+		for _, version := range versions {
+			pkg.Index.Versions = append(pkg.Index.Versions, types.PackageIndexItem{Version: version.Original()})
+		}
+		if len(versions) > 0 {
+			pkg.Index.LatestVersion = versions[len(versions)-1].Original()
+		} else if len(invalid) > 0 {
+			pkg.Index.LatestVersion = invalid[len(invalid)-1]
+		}
+
+		if i := slices.IndexFunc(b.Index.Packages, func(it types.PackageRepoIndexItem) bool {
+			return it.Name == name
+		}); i >= 0 {
+			b.Index.Packages[i].LatestVersion = pkg.Index.LatestVersion
+		} else {
+			b.Index.Packages = append(b.Index.Packages,
+				types.PackageRepoIndexItem{Name: name, LatestVersion: pkg.Index.LatestVersion})
+		}
+	}
+	slices.SortFunc(b.Index.Packages, func(a, b types.PackageRepoIndexItem) int { return strings.Compare(a.Name, b.Name) })
+}
+
+// Files returns all files of the bundle, by path relative to the repository root.
+func (b *Bundle) Files() (map[string][]byte, error) {
+	files := make(map[string][]byte)
+	if data, err := sigsyaml.Marshal(b.Index); err != nil {
+		return nil, err
+	} else {
+		files[repoIndexFile] = data
+	}
+	for name, pkg := range b.Packages {
+		if data, err := sigsyaml.Marshal(pkg.Index); err != nil {
+			return nil, err
+		} else {
+			files[path.Join(name, packageIndexFile)] = data
+		}
+		for version, versionFiles := range pkg.Versions {
+			for filePath, data := range versionFiles {
+				files[path.Join(name, version, filePath)] = data
+			}
+		}
+	}
+	return files, nil
+}
+
+// WriteArchive writes the bundle as gzip compressed tar archive to w.
+func (b *Bundle) WriteArchive(w io.Writer) error {
+	files, err := b.Files()
+	if err != nil {
+		return err
+	}
+	gzipWriter := gzip.NewWriter(w)
+	tarWriter := tar.NewWriter(gzipWriter)
+	for _, filePath := range sortedKeys(files) {
+		header := &tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     filePath,
+			Size:     int64(len(files[filePath])),
+			Mode:     0644,
+		}
+		if err := tarWriter.WriteHeader(header); err != nil {
+			return err
+		} else if _, err := tarWriter.Write(files[filePath]); err != nil {
+			return err
+		}
+	}
+	if err := tarWriter.Close(); err != nil {
+		return err
+	}
+	return gzipWriter.Close()
+}
+
+// WriteDir writes all files of the bundle to dir, using the layout of a package repository.
+// Existing files are overwritten, but files that are not part of the bundle are not removed.
+func (b *Bundle) WriteDir(dir string) error {
+	files, err := b.Files()
+	if err != nil {
+		return err
+	}
+	for _, filePath := range sortedKeys(files) {
+		target := filepath.Join(dir, filepath.FromSlash(filePath))
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		} else if err := os.WriteFile(target, files[filePath], 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadArchive reads a bundle from a gzip compressed tar archive, as written by WriteArchive.
+func ReadArchive(r io.Reader) (*Bundle, error) {
+	gzipReader, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("invalid bundle: %w", err)
+	}
+	defer func() { _ = gzipReader.Close() }()
+	tarReader := tar.NewReader(gzipReader)
+	files := make(map[string][]byte)
+	for {
+		header, err := tarReader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("invalid bundle: %w", err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		if header.Size > maxFileSize {
+			return nil, fmt.Errorf("invalid bundle: %v exceeds maximum file size", header.Name)
+		}
+		if data, err := io.ReadAll(io.LimitReader(tarReader, maxFileSize)); err != nil {
+			return nil, fmt.Errorf("invalid bundle: %w", err)
+		} else {
+			files[header.Name] = data
+		}
+	}
+	return fromFiles(files)
+}
+
+// ReadDir reads a bundle from a directory with the layout of a package repository.
+func ReadDir(dir string) (*Bundle, error) {
+	files := make(map[string][]byte)
+	err := filepath.WalkDir(dir, func(filePath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		} else if d.IsDir() && filePath != dir && strings.HasPrefix(d.Name(), ".") {
+			return filepath.SkipDir
+		} else if !d.Type().IsRegular() {
+			return nil
+		}
+		if rel, err := filepath.Rel(dir, filePath); err != nil {
+			return err
+		} else if data, err := os.ReadFile(filePath); err != nil {
+			return err
+		} else {
+			files[filepath.ToSlash(rel)] = data
+			return nil
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return fromFiles(files)
+}
+
+func fromFiles(files map[string][]byte) (*Bundle, error) {
+	b := New()
+	if data, ok := files[repoIndexFile]; !ok {
+		return nil, fmt.Errorf("invalid bundle: %v not found", repoIndexFile)
+	} else if err := yaml.Unmarshal(data, &b.Index); err != nil {
+		return nil, fmt.Errorf("invalid bundle: %v: %w", repoIndexFile, err)
+	}
+	for filePath, data := range files {
+		cleanPath := path.Clean(filePath)
+		if path.IsAbs(cleanPath) || cleanPath == ".." || strings.HasPrefix(cleanPath, "../") {
+			return nil, fmt.Errorf("invalid bundle: invalid path %v", filePath)
+		}
+		parts := strings.SplitN(cleanPath, "/", 3)
+		if len(parts) == 3 {
+			b.AddFile(parts[0], parts[1], parts[2], data)
+		}
+		// All other files, including the package indexes, are ignored. Indexes are recreated from the versions.
+	}
+	for name, pkg := range b.Packages {
+		for version, files := range pkg.Versions {
+			if _, ok := files[manifestFile]; !ok {
+				return nil, fmt.Errorf("invalid bundle: %v not found for %v (%v)", manifestFile, name, version)
+			}
+		}
+	}
+	b.UpdateIndexes()
+	return b, nil
+}
+
+func sortedKeys(files map[string][]byte) []string {
+	keys := make([]string, 0, len(files))
+	for key := range files {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
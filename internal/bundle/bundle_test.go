@@ -0,0 +1,248 @@
+package bundle
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/glasskube/glasskube/api/v1alpha1"
+	"github.com/glasskube/glasskube/internal/clientutils"
+	"github.com/glasskube/glasskube/internal/contenttype"
+	"github.com/glasskube/glasskube/internal/dependency"
+	repoclient "github.com/glasskube/glasskube/internal/repo/client"
+	"github.com/glasskube/glasskube/internal/repo/client/auth"
+	"github.com/glasskube/glasskube/internal/repo/client/fake"
+	"github.com/glasskube/glasskube/internal/repo/types"
+	"github.com/google/go-containerregistry/pkg/registry"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+const configMapManifest = "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: test\n"
+
+type emptyClusterAdapter struct{}
+
+func (emptyClusterAdapter) GetPackageInfo(ctx context.Context, name string) (*v1alpha1.PackageInfo, error) {
+	panic("unimplemented")
+}
+
+func (emptyClusterAdapter) ListPackages(ctx context.Context, namespace string) (*v1alpha1.PackageList, error) {
+	return &v1alpha1.PackageList{}, nil
+}
+
+func (emptyClusterAdapter) ListClusterPackages(ctx context.Context) (*v1alpha1.ClusterPackageList, error) {
+	return &v1alpha1.ClusterPackageList{}, nil
+}
+
+func (emptyClusterAdapter) GetClusterPackage(ctx context.Context, name string) (*v1alpha1.ClusterPackage, error) {
+	panic("unimplemented")
+}
+
+func (emptyClusterAdapter) GetPackageRepository(ctx context.Context, name string) (
+	*v1alpha1.PackageRepository, error) {
+	panic("unimplemented")
+}
+
+func (emptyClusterAdapter) ListPackageRepositories(ctx context.Context) (*v1alpha1.PackageRepositoryList, error) {
+	panic("unimplemented")
+}
+
+func testBundle() *Bundle {
+	b := New()
+	b.AddFile("foo", "v1.0.0", "package.yaml", []byte("name: foo\n"))
+	b.AddFile("foo", "v1.1.0", "package.yaml", []byte("name: foo\nmanifests:\n  - url: ./manifests.yaml\n"))
+	b.AddFile("foo", "v1.1.0", "manifests.yaml", []byte(configMapManifest))
+	b.UpdateIndexes()
+	return b
+}
+
+var _ = Describe("Bundle", func() {
+	It("should create indexes for all versions", func() {
+		b := testBundle()
+		Expect(b.Index.Packages).To(Equal([]types.PackageRepoIndexItem{{Name: "foo", LatestVersion: "v1.1.0"}}))
+		Expect(b.Packages["foo"].Index).To(Equal(types.PackageIndex{
+			Versions:      []types.PackageIndexItem{{Version: "v1.0.0"}, {Version: "v1.1.0"}},
+			LatestVersion: "v1.1.0",
+		}))
+	})
+
+	It("should write and read archives", func() {
+		var buf bytes.Buffer
+		Expect(testBundle().WriteArchive(&buf)).To(Succeed())
+		b, err := ReadArchive(&buf)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(b).To(Equal(testBundle()))
+	})
+
+	It("should reject archives with invalid paths", func() {
+		var buf bytes.Buffer
+		gzipWriter := gzip.NewWriter(&buf)
+		tarWriter := tar.NewWriter(gzipWriter)
+		for _, name := range []string{"index.yaml", "../foo/v1.0.0/package.yaml"} {
+			Expect(tarWriter.WriteHeader(&tar.Header{Typeflag: tar.TypeReg, Name: name, Mode: 0644})).To(Succeed())
+		}
+		Expect(tarWriter.Close()).To(Succeed())
+		Expect(gzipWriter.Close()).To(Succeed())
+		_, err := ReadArchive(&buf)
+		Expect(err).To(MatchError(ContainSubstring("invalid path")))
+	})
+
+	It("should import into a directory that can be used as repository", func() {
+		dir := GinkgoT().TempDir()
+		existing := New()
+		existing.AddFile("bar", "v0.1.0", "package.yaml", []byte("name: bar\n"))
+		existing.AddFile("foo", "v0.9.0", "package.yaml", []byte("name: foo\n"))
+		existing.UpdateIndexes()
+		_, err := ImportDir(existing, dir)
+		Expect(err).NotTo(HaveOccurred())
+		url, err := ImportDir(testBundle(), dir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(url).To(HavePrefix(v1alpha1.FileRepoUrlPrefix))
+
+		client := repoclient.NewFile(url)
+		var repoIndex types.PackageRepoIndex
+		Expect(client.FetchPackageRepoIndex(&repoIndex)).To(Succeed())
+		Expect(repoIndex.Packages).To(HaveLen(2))
+		var index types.PackageIndex
+		Expect(client.FetchPackageIndex("foo", &index)).To(Succeed())
+		Expect(index.Versions).To(HaveLen(3))
+		var manifest v1alpha1.PackageManifest
+		version, err := client.FetchLatestPackageManifest("foo", &manifest)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(version).To(Equal("v1.1.0"))
+		data, err := client.FetchPackageFile("foo", version, manifest.Manifests[0].Url)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(data)).To(Equal(configMapManifest))
+	})
+
+	It("should import into an OCI registry", func() {
+		server := httptest.NewServer(registry.New())
+		DeferCleanup(server.Close)
+		url := v1alpha1.OCIRepoUrlPrefix + strings.TrimPrefix(server.URL, "http://") + "/packages"
+		existing := New()
+		existing.AddFile("foo", "v0.9.0+1", "package.yaml", []byte("name: foo\n"))
+		existing.UpdateIndexes()
+		Expect(ImportOCI(existing, url, auth.Noop())).To(Succeed())
+		Expect(ImportOCI(testBundle(), url, auth.Noop())).To(Succeed())
+
+		client := repoclient.NewOCI(url, auth.Noop(), time.Minute)
+		var index types.PackageIndex
+		Expect(client.FetchPackageIndex("foo", &index)).To(Succeed())
+		Expect(index.LatestVersion).To(Equal("v1.1.0"))
+		Expect(index.Versions).To(HaveLen(3))
+		data, err := client.FetchPackageFile("foo", "v1.1.0", "./manifests.yaml")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(data)).To(Equal(configMapManifest))
+	})
+})
+
+var _ = Describe("ImportOCI", func() {
+	It("should fail if the existing index can not be fetched", func() {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+		}))
+		DeferCleanup(server.Close)
+		url := v1alpha1.OCIRepoUrlPrefix + strings.TrimPrefix(server.URL, "http://") + "/packages"
+		Expect(ImportOCI(testBundle(), url, auth.Noop())).To(MatchError(ContainSubstring("repository index")))
+	})
+})
+
+var _ = Describe("Exporter", func() {
+	var server *httptest.Server
+	var repo = fake.EmptyClient()
+
+	BeforeEach(func() {
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", contenttype.MediaTypeYAML)
+			_, _ = w.Write([]byte(configMapManifest))
+		}))
+		DeferCleanup(server.Close)
+		repo.Clear()
+	})
+
+	newExporter := func() *Exporter {
+		clientset := fake.ClientsetWithClient(repo)
+		return NewExporter(clientset, dependency.NewDependencyManager(emptyClusterAdapter{}, clientset))
+	}
+
+	It("should export packages with their dependencies and manifests", func() {
+		digest := sha256.Sum256([]byte(configMapManifest))
+		repo.AddPackage("foo", "v1.0.0", &v1alpha1.PackageManifest{
+			Name:         "foo",
+			Dependencies: []v1alpha1.Dependency{{Name: "bar"}},
+			Manifests: []v1alpha1.PlainManifest{{
+				Url:    server.URL + "/manifests/configmap.yaml",
+				Digest: "sha256:" + hex.EncodeToString(digest[:]),
+			}},
+		})
+		repo.AddPackage("bar", "v2.0.0", &v1alpha1.PackageManifest{
+			Name: "bar",
+			Helm: &v1alpha1.HelmManifest{RepositoryUrl: "https://charts.example.com", ChartName: "bar"},
+		})
+		exporter := newExporter()
+		b, err := exporter.Export(PackageRef{Name: "foo"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(b.Index.Packages).To(HaveLen(2))
+		Expect(b.Packages).To(HaveKey("bar"))
+		Expect(exporter.Warnings).To(ConsistOf(ContainSubstring("helm")))
+
+		files := b.Packages["foo"].Versions["v1.0.0"]
+		var manifest v1alpha1.PackageManifest
+		Expect(yaml.Unmarshal(files["package.yaml"], &manifest)).To(Succeed())
+		Expect(manifest.Manifests[0].Url).To(HavePrefix("./bundled/"))
+		Expect(manifest.Manifests[0].Url).To(HaveSuffix("-configmap.yaml"))
+		Expect(string(files[strings.TrimPrefix(manifest.Manifests[0].Url, "./")])).To(Equal(configMapManifest))
+	})
+
+	It("should export manifests without digest", func() {
+		repo.AddPackage("foo", "v1.0.0", &v1alpha1.PackageManifest{
+			Name:      "foo",
+			Manifests: []v1alpha1.PlainManifest{{Url: server.URL + "/manifests/configmap.yaml"}},
+		})
+		b, err := newExporter().Export(PackageRef{Name: "foo", Version: "v1.0.0"})
+		Expect(err).NotTo(HaveOccurred())
+		files := b.Packages["foo"].Versions["v1.0.0"]
+		var manifest v1alpha1.PackageManifest
+		Expect(yaml.Unmarshal(files["package.yaml"], &manifest)).To(Succeed())
+		Expect(manifest.Manifests[0].Digest).To(BeEmpty())
+		Expect(string(files[strings.TrimPrefix(manifest.Manifests[0].Url, "./")])).To(Equal(configMapManifest))
+	})
+
+	It("should export hook manifests", func() {
+		repo.AddPackage("foo", "v1.0.0", &v1alpha1.PackageManifest{
+			Name: "foo",
+			Hooks: []v1alpha1.PackageHook{{
+				Name:     "migrate",
+				Phase:    v1alpha1.HookPreUpgrade,
+				Manifest: v1alpha1.PlainManifest{Url: server.URL + "/hooks/migrate.yaml"},
+			}},
+		})
+		b, err := newExporter().Export(PackageRef{Name: "foo", Version: "v1.0.0"})
+		Expect(err).NotTo(HaveOccurred())
+		files := b.Packages["foo"].Versions["v1.0.0"]
+		var manifest v1alpha1.PackageManifest
+		Expect(yaml.Unmarshal(files["package.yaml"], &manifest)).To(Succeed())
+		Expect(manifest.Hooks[0].Manifest.Url).To(HaveSuffix("-migrate.yaml"))
+		Expect(string(files[strings.TrimPrefix(manifest.Hooks[0].Manifest.Url, "./")])).To(Equal(configMapManifest))
+	})
+
+	It("should fail if a manifest does not match its digest", func() {
+		repo.AddPackage("foo", "v1.0.0", &v1alpha1.PackageManifest{
+			Name: "foo",
+			Manifests: []v1alpha1.PlainManifest{{
+				Url:    server.URL + "/manifests/configmap.yaml",
+				Digest: "sha256:" + strings.Repeat("0", 64),
+			}},
+		})
+		_, err := newExporter().Export(PackageRef{Name: "foo", Version: "v1.0.0"})
+		Expect(err).To(MatchError(clientutils.ErrDigestMismatch))
+	})
+})
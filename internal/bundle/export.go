@@ -0,0 +1,277 @@
+package bundle
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/glasskube/glasskube/api/v1alpha1"
+	"github.com/glasskube/glasskube/internal/clientutils"
+	"github.com/glasskube/glasskube/internal/dependency"
+	repoclient "github.com/glasskube/glasskube/internal/repo/client"
+	"github.com/glasskube/glasskube/internal/repo/types"
+	"github.com/glasskube/glasskube/internal/signature"
+	"go.uber.org/multierr"
+	sigsyaml "sigs.k8s.io/yaml"
+)
+
+// bundledDir is the directory, relative to a package version, where manifests are stored that are not referenced by
+// a path inside the package version directory.
+const bundledDir = "bundled"
+
+type PackageRef struct {
+	Name string
+	// Version is optional. If it is empty, the latest version is used.
+	Version string
+	// RepositoryName is optional. If it is empty, the repository is looked up by the package name.
+	RepositoryName string
+}
+
+type Exporter struct {
+	repo repoclient.RepoClientset
+	dm   *dependency.DependendcyManager
+	// Warnings contains messages about package contents that could not be included in the bundle.
+	Warnings []string
+}
+
+func NewExporter(repo repoclient.RepoClientset, dm *dependency.DependendcyManager) *Exporter {
+	return &Exporter{repo: repo, dm: dm}
+}
+
+// Export creates a bundle containing the referenced packages and all their dependencies.
+func (e *Exporter) Export(refs ...PackageRef) (*Bundle, error) {
+	b := New()
+	var repoIndexItems []types.PackageRepoIndexItem
+	queue := slices.Clone(refs)
+	for len(queue) > 0 {
+		ref := queue[0]
+		queue = queue[1:]
+
+		client, err := e.clientFor(ref)
+		if err != nil {
+			return nil, err
+		}
+		if ref.Version == "" {
+			var idx types.PackageIndex
+			if err := client.FetchPackageIndex(ref.Name, &idx); err != nil {
+				return nil, fmt.Errorf("failed to fetch versions of %v: %w", ref.Name, err)
+			} else {
+				ref.Version = idx.LatestVersion
+			}
+		}
+		if pkg, ok := b.Packages[ref.Name]; ok {
+			if _, ok := pkg.Versions[ref.Version]; ok {
+				continue
+			}
+		}
+
+		var manifest v1alpha1.PackageManifest
+		if err := client.FetchPackageManifest(ref.Name, ref.Version, &manifest); err != nil {
+			return nil, fmt.Errorf("failed to fetch manifest of %v (%v): %w", ref.Name, ref.Version, err)
+		} else if err := e.exportVersion(b, client, ref, &manifest); err != nil {
+			return nil, err
+		}
+
+		if item, err := findRepoIndexItem(client, ref.Name); err != nil {
+			return nil, err
+		} else if item != nil && !slices.ContainsFunc(repoIndexItems, func(it types.PackageRepoIndexItem) bool {
+			return it.Name == item.Name
+		}) {
+			repoIndexItems = append(repoIndexItems, *item)
+		}
+
+		if requirements, err := e.dm.Resolve(&manifest, ref.Version); err != nil {
+			return nil, fmt.Errorf("failed to resolve dependencies of %v (%v): %w", ref.Name, ref.Version, err)
+		} else {
+			for _, req := range requirements {
+				queue = append(queue, PackageRef{Name: req.Name, Version: req.Version})
+			}
+		}
+	}
+	b.Index.Packages = repoIndexItems
+	b.UpdateIndexes()
+	return b, nil
+}
+
+func (e *Exporter) exportVersion(
+	b *Bundle,
+	client repoclient.RepoClient,
+	ref PackageRef,
+	manifest *v1alpha1.PackageManifest,
+) error {
+	rewritten := false
+	for i := range manifest.Manifests {
+		if r, err := e.exportPlainManifest(b, client, ref, &manifest.Manifests[i]); err != nil {
+			return err
+		} else {
+			rewritten = rewritten || r
+		}
+	}
+	for i := range manifest.Hooks {
+		if r, err := e.exportPlainManifest(b, client, ref, &manifest.Hooks[i].Manifest); err != nil {
+			return err
+		} else {
+			rewritten = rewritten || r
+		}
+	}
+	if manifest.Helm != nil {
+		e.warnf("%v (%v): helm charts from %v are not included in the bundle",
+			ref.Name, ref.Version, manifest.Helm.RepositoryUrl)
+	}
+	if manifest.Kustomize != nil {
+		if filePath, ok := versionRelativePath(manifest.Kustomize.Url); !ok {
+			e.warnf("%v (%v): kustomization %v is not included in the bundle",
+				ref.Name, ref.Version, manifest.Kustomize.Url)
+		} else if data, err := e.fetchPlainManifest(client, ref, manifest.Kustomize.Url); err != nil {
+			return fmt.Errorf("failed to fetch kustomization %v of %v (%v): %w",
+				manifest.Kustomize.Url, ref.Name, ref.Version, err)
+		} else {
+			b.AddFile(ref.Name, ref.Version, filePath, data)
+		}
+	}
+
+	if !rewritten {
+		if fetcher, ok := client.(repoclient.SignedPackageManifestFetcher); ok {
+			if data, sig, err := fetcher.FetchSignedPackageManifest(ref.Name, ref.Version); err == nil {
+				// keep the original manifest, so that its signature stays valid
+				b.AddFile(ref.Name, ref.Version, manifestFile, data)
+				b.AddFile(ref.Name, ref.Version, manifestFile+signature.FileExtension, sig)
+				return nil
+			}
+		}
+	} else if _, ok := client.(repoclient.PackageManifestVerifier); ok {
+		e.warnf("%v (%v): the package manifest was modified, so its signature is not included in the bundle",
+			ref.Name, ref.Version)
+	}
+
+	if data, err := sigsyaml.Marshal(manifest); err != nil {
+		return err
+	} else {
+		b.AddFile(ref.Name, ref.Version, manifestFile, data)
+		return nil
+	}
+}
+
+// exportPlainManifest adds the contents of plainManifest to the bundle. If its URL does not point to a file in the
+// package version directory, the URL is rewritten to point to the bundled file and the returned bool is true.
+func (e *Exporter) exportPlainManifest(
+	b *Bundle,
+	client repoclient.RepoClient,
+	ref PackageRef,
+	plainManifest *v1alpha1.PlainManifest,
+) (bool, error) {
+	data, err := e.fetchPlainManifest(client, ref, plainManifest.Url)
+	if err != nil {
+		return false, fmt.Errorf("failed to fetch manifest %v of %v (%v): %w",
+			plainManifest.Url, ref.Name, ref.Version, err)
+	}
+	if plainManifest.Digest != "" {
+		if err := clientutils.VerifyDigest(data, plainManifest.Digest); err != nil {
+			return false, fmt.Errorf("manifest %v of %v (%v): %w", plainManifest.Url, ref.Name, ref.Version, err)
+		}
+	}
+	if filePath, ok := versionRelativePath(plainManifest.Url); ok {
+		b.AddFile(ref.Name, ref.Version, filePath, data)
+		return false, nil
+	} else {
+		filePath := bundledPath(plainManifest.Url, data)
+		b.AddFile(ref.Name, ref.Version, filePath, data)
+		plainManifest.Url = "./" + filePath
+		return true, nil
+	}
+}
+
+// fetchPlainManifest fetches the manifest at urlOrPath in the same way as the plain manifest adapter.
+func (e *Exporter) fetchPlainManifest(client repoclient.RepoClient, ref PackageRef, urlOrPath string) ([]byte, error) {
+	parsedUrl, err := url.Parse(urlOrPath)
+	if err != nil {
+		return nil, err
+	}
+	relative := parsedUrl.Scheme == "" && parsedUrl.Host == ""
+	if relative {
+		if fetcher, ok := client.(repoclient.PackageFileFetcher); ok {
+			return fetcher.FetchPackageFile(ref.Name, ref.Version, urlOrPath)
+		}
+		if manifestUrl, err := client.GetPackageManifestURL(ref.Name, ref.Version); err != nil {
+			return nil, err
+		} else if parsedBase, err := url.Parse(manifestUrl); err != nil {
+			return nil, err
+		} else {
+			parsedUrl = parsedBase.ResolveReference(parsedUrl)
+		}
+	}
+	if request, err := clientutils.NewResourcesRequest(parsedUrl.String()); err != nil {
+		return nil, err
+	} else {
+		if relative {
+			// only relative URLs point to the repository, so only those requests need to be authenticated
+			client.Authenticate(request)
+		}
+		return clientutils.FetchManifest(request)
+	}
+}
+
+func (e *Exporter) clientFor(ref PackageRef) (repoclient.RepoClient, error) {
+	if ref.RepositoryName != "" {
+		return e.repo.ForRepoWithName(ref.RepositoryName), nil
+	}
+	repos, repoErr := e.repo.Meta().GetReposForPackage(ref.Name)
+	if len(repos) == 0 {
+		return nil, multierr.Append(fmt.Errorf("no repository found for package %v", ref.Name), repoErr)
+	}
+	repo := repos[0]
+	for _, r := range repos {
+		if r.IsDefaultRepository() {
+			repo = r
+		}
+	}
+	return e.repo.ForRepo(repo), nil
+}
+
+func (e *Exporter) warnf(format string, args ...any) {
+	e.Warnings = append(e.Warnings, fmt.Sprintf(format, args...))
+}
+
+func findRepoIndexItem(client repoclient.RepoClient, name string) (*types.PackageRepoIndexItem, error) {
+	var idx types.PackageRepoIndex
+	if err := client.FetchPackageRepoIndex(&idx); err != nil {
+		return nil, fmt.Errorf("failed to fetch repository index: %w", err)
+	}
+	for _, item := range idx.Packages {
+		if item.Name == name {
+			return &item, nil
+		}
+	}
+	return nil, nil
+}
+
+// versionRelativePath returns the path of urlOrPath relative to the package version directory, if urlOrPath is a
+// relative path that does not leave this directory.
+func versionRelativePath(urlOrPath string) (string, bool) {
+	if parsedUrl, err := url.Parse(urlOrPath); err != nil || parsedUrl.Scheme != "" || parsedUrl.Host != "" ||
+		parsedUrl.RawQuery != "" || path.IsAbs(parsedUrl.Path) {
+		return "", false
+	} else if cleanPath := path.Clean(parsedUrl.Path); cleanPath == ".." || cleanPath == manifestFile ||
+		strings.HasPrefix(cleanPath, "../") {
+		return "", false
+	} else {
+		return cleanPath, true
+	}
+}
+
+// bundledPath returns a unique path for a manifest that is stored in the bundledDir.
+func bundledPath(urlOrPath string, data []byte) string {
+	digest := sha256.Sum256(data)
+	name := path.Base(urlOrPath)
+	if parsedUrl, err := url.Parse(urlOrPath); err == nil {
+		name = path.Base(parsedUrl.Path)
+	}
+	if name == "." || name == "/" || name == "" {
+		name = "manifest.yaml"
+	}
+	return path.Join(bundledDir, hex.EncodeToString(digest[:8])+"-"+name)
+}
@@ -0,0 +1,120 @@
+package bundle
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/glasskube/glasskube/api/v1alpha1"
+	repoclient "github.com/glasskube/glasskube/internal/repo/client"
+	"github.com/glasskube/glasskube/internal/repo/client/auth"
+	"github.com/glasskube/glasskube/internal/repo/types"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	ocitypes "github.com/google/go-containerregistry/pkg/v1/types"
+)
+
+const ociFileMediaType ocitypes.MediaType = "application/yaml"
+
+// ImportDir adds all packages of b to the package repository in dir. The directory is created if it does not exist
+// and packages that are already contained in the repository are kept.
+// It returns the URL of a PackageRepository that serves the directory.
+func ImportDir(b *Bundle, dir string) (string, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	target := New()
+	if _, err := os.Stat(filepath.Join(absDir, repoIndexFile)); err == nil {
+		if existing, err := ReadDir(absDir); err != nil {
+			return "", fmt.Errorf("failed to read existing repository: %w", err)
+		} else {
+			target = existing
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return "", err
+	}
+	target.Merge(b)
+	if err := target.WriteDir(absDir); err != nil {
+		return "", err
+	}
+	return v1alpha1.FileRepoUrlPrefix + filepath.ToSlash(absDir), nil
+}
+
+// ImportOCI pushes all packages of b to the OCI package repository with the given URL (e.g. "oci://registry/path"),
+// using the layout that is expected by the OCI repository client. Packages and versions that already exist in the
+// registry are kept in the indexes.
+func ImportOCI(b *Bundle, url string, authenticator auth.Authenticator) error {
+	if !strings.HasPrefix(url, v1alpha1.OCIRepoUrlPrefix) {
+		return fmt.Errorf("not an OCI repository URL: %v", url)
+	}
+	repository := strings.TrimSuffix(strings.TrimPrefix(url, v1alpha1.OCIRepoUrlPrefix), "/")
+	client := repoclient.NewOCI(url, authenticator, 0)
+	options := []remote.Option{remote.WithAuth(auth.OCI(authenticator))}
+
+	// Existing indexes are merged with the bundle. If they do not exist, the repository is assumed to be empty.
+	target := New()
+	if err := client.FetchPackageRepoIndex(&target.Index); err != nil && !repoclient.IsOCINotFound(err) {
+		return fmt.Errorf("failed to fetch repository index: %w", err)
+	}
+	for pkgName := range b.Packages {
+		var idx types.PackageIndex
+		if err := client.FetchPackageIndex(pkgName, &idx); err != nil && !repoclient.IsOCINotFound(err) {
+			return fmt.Errorf("failed to fetch versions of %v: %w", pkgName, err)
+		}
+		for _, item := range idx.Versions {
+			// only the version is needed to update the indexes, the files already exist in the registry
+			target.AddFile(pkgName, item.Version, manifestFile, nil)
+		}
+	}
+	target.Merge(b)
+
+	for pkgName, pkg := range b.Packages {
+		for version, files := range pkg.Versions {
+			ref := path.Join(repository, pkgName) + ":" + repoclient.OCIPackageVersionTag(version)
+			if err := pushFiles(ref, files, options...); err != nil {
+				return err
+			}
+		}
+	}
+	files, err := target.Files()
+	if err != nil {
+		return err
+	}
+	for pkgName := range b.Packages {
+		ref := path.Join(repository, pkgName) + ":" + repoclient.OCIPackageIndexTag
+		data := files[path.Join(pkgName, packageIndexFile)]
+		if err := pushFiles(ref, map[string][]byte{packageIndexFile: data}, options...); err != nil {
+			return err
+		}
+	}
+	ref := repository + ":" + repoclient.OCIRepoIndexTag
+	return pushFiles(ref, map[string][]byte{repoIndexFile: files[repoIndexFile]}, options...)
+}
+
+func pushFiles(ref string, files map[string][]byte, options ...remote.Option) error {
+	var image v1.Image = empty.Image
+	for _, fileName := range sortedKeys(files) {
+		var err error
+		image, err = mutate.Append(image, mutate.Addendum{
+			Layer:       static.NewLayer(files[fileName], ociFileMediaType),
+			Annotations: map[string]string{repoclient.OCIFileTitleAnnotation: fileName},
+		})
+		if err != nil {
+			return err
+		}
+	}
+	if parsedRef, err := name.ParseReference(ref); err != nil {
+		return err
+	} else if err := remote.Write(parsedRef, image, options...); err != nil {
+		return fmt.Errorf("failed to push %v: %w", ref, err)
+	}
+	return nil
+}
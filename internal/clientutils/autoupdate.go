@@ -2,11 +2,15 @@ package clientutils
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/glasskube/glasskube/api/v1alpha1"
 	"github.com/glasskube/glasskube/internal/clicontext"
 	"github.com/glasskube/glasskube/internal/controller/ctrlpkg"
+	"github.com/glasskube/glasskube/internal/maintenancewindow"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func AutoUpdateString(pkg ctrlpkg.Package, disabledStr string) string {
@@ -19,16 +23,43 @@ func AutoUpdateString(pkg ctrlpkg.Package, disabledStr string) string {
 	return ""
 }
 
-func IsAutoUpdaterInstalled(ctx context.Context) (bool, error) {
-	client := clicontext.PackageClientFromContext(ctx)
-	var pkg v1alpha1.ClusterPackage
-	if err := client.ClusterPackages().Get(ctx, "glasskube-autoupdater", &pkg); err != nil {
+// AutoUpdateWindowString returns the same as AutoUpdateString, followed by a description of the next maintenance
+// window of pkg, if automatic updates are enabled and a maintenance window is configured.
+func AutoUpdateWindowString(
+	pkg ctrlpkg.Package, defaultSchedule maintenancewindow.Schedule, now time.Time, disabledStr string,
+) string {
+	result := AutoUpdateString(pkg, disabledStr)
+	if pkg.IsNil() || !pkg.AutoUpdatesEnabled() {
+		return result
+	} else if description := AutoUpdateWindowDescription(pkg, defaultSchedule, now); description != "" {
+		return fmt.Sprintf("%v (%v)", result, description)
+	} else {
+		return result
+	}
+}
+
+// AutoUpdateWindowDescription describes the maintenance window of pkg that is open at now or starts next.
+// It returns an empty string if no maintenance window is configured.
+func AutoUpdateWindowDescription(
+	pkg ctrlpkg.Package, defaultSchedule maintenancewindow.Schedule, now time.Time,
+) string {
+	if schedule, err := maintenancewindow.ForPackage(pkg, defaultSchedule); err != nil {
+		return "invalid maintenance window"
+	} else {
+		return schedule.Describe(now)
+	}
+}
+
+// GetDefaultMaintenanceWindow returns the default maintenance window for automatic updates of all packages.
+// It is configured as an annotation of the glasskube-system namespace.
+func GetDefaultMaintenanceWindow(ctx context.Context) (maintenancewindow.Schedule, error) {
+	clientset := clicontext.KubernetesClientFromContext(ctx)
+	if ns, err := clientset.CoreV1().Namespaces().Get(ctx, "glasskube-system", metav1.GetOptions{}); err != nil {
 		if errors.IsNotFound(err) {
-			return false, nil
-		} else {
-			return false, err
+			return nil, nil
 		}
+		return nil, err
 	} else {
-		return true, nil
+		return maintenancewindow.Parse(ns.Annotations[v1alpha1.AnnotationAutoUpdateWindow])
 	}
 }
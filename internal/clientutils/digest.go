@@ -0,0 +1,26 @@
+package clientutils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const sha256DigestPrefix = "sha256:"
+
+var ErrDigestMismatch = errors.New("digest mismatch")
+
+// VerifyDigest checks that data matches digest, which must have the format "sha256:<hex>".
+func VerifyDigest(data []byte, digest string) error {
+	expected, ok := strings.CutPrefix(digest, sha256DigestPrefix)
+	if !ok {
+		return fmt.Errorf("unsupported digest %v: only sha256 is supported", digest)
+	}
+	sum := sha256.Sum256(data)
+	if actual := hex.EncodeToString(sum[:]); !strings.EqualFold(actual, expected) {
+		return fmt.Errorf("%w: expected %v but got %v%v", ErrDigestMismatch, digest, sha256DigestPrefix, actual)
+	}
+	return nil
+}
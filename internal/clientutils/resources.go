@@ -1,6 +1,7 @@
 package clientutils
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"net/http"
@@ -30,29 +31,77 @@ func NewResourcesRequest(url string) (*http.Request, error) {
 }
 
 func FetchResources(request *http.Request) ([]unstructured.Unstructured, error) {
+	return FetchResourcesWithDigest(request, "")
+}
+
+// FetchResourcesWithDigest is like FetchResources, but if digest is not empty, the response body must match it.
+// See VerifyDigest for the supported digest format.
+func FetchResourcesWithDigest(request *http.Request, digest string) ([]unstructured.Unstructured, error) {
+	if data, err := FetchManifest(request); err != nil {
+		return nil, err
+	} else {
+		return DecodeResourcesWithDigest(data, digest, request.URL.Redacted())
+	}
+}
+
+// FetchManifest returns the contents of the JSON or YAML manifest requested by request.
+func FetchManifest(request *http.Request) ([]byte, error) {
+	return fetch(request, "manifest", contenttype.IsJsonOrYaml)
+}
+
+// FetchFile returns the contents of the file requested by request. In contrast to FetchManifest, the content type of
+// the response is not checked.
+func FetchFile(request *http.Request) ([]byte, error) {
+	return fetch(request, "file", nil)
+}
+
+func fetch(request *http.Request, what string, checkContentType func(*http.Response) error) ([]byte, error) {
 	url := request.URL.Redacted()
 	response, err := httperror.CheckResponse(http.DefaultClient.Do(request))
 	if err != nil {
 		switch {
 		case httperror.IsNotFound(err):
-			return nil, fmt.Errorf("manifest not found at %v: %v", url, err)
+			return nil, fmt.Errorf("%v not found at %v: %v", what, url, err)
 		case httperror.Is(err, http.StatusForbidden):
-			return nil, fmt.Errorf("access denied to manifest at %v: %v", url, err)
+			return nil, fmt.Errorf("access denied to %v at %v: %v", what, url, err)
 		case httperror.Is(err, http.StatusUnauthorized):
-			return nil, fmt.Errorf("unauthorized to access manifest at %v: %v", url, err)
+			return nil, fmt.Errorf("unauthorized to access %v at %v: %v", what, url, err)
 		default:
-			return nil, fmt.Errorf("failed to download manifest from %v: %v", url, err)
+			return nil, fmt.Errorf("failed to download %v from %v: %v", what, url, err)
 		}
 	}
 	defer func(Body io.ReadCloser) {
 		_ = Body.Close()
 	}(response.Body)
 
-	if err := contenttype.IsJsonOrYaml(response); err != nil {
-		return nil, fmt.Errorf("could not decode manifest %v: %w", url, err)
+	if checkContentType != nil {
+		if err := checkContentType(response); err != nil {
+			return nil, fmt.Errorf("could not decode %v %v: %w", what, url, err)
+		}
+	}
+
+	if data, err := io.ReadAll(response.Body); err != nil {
+		return nil, fmt.Errorf("failed to download %v from %v: %w", what, url, err)
+	} else {
+		return data, nil
+	}
+}
+
+// DecodeResourcesWithDigest verifies data against digest and decodes all resources from it.
+// source is only used for error messages.
+func DecodeResourcesWithDigest(data []byte, digest string, source string) ([]unstructured.Unstructured, error) {
+	if digest != "" {
+		if err := VerifyDigest(data, digest); err != nil {
+			return nil, fmt.Errorf("could not verify manifest %v: %w", source, err)
+		}
 	}
+	return DecodeResources(bytes.NewReader(data), source)
+}
 
-	decoder := yaml.NewYAMLOrJSONDecoder(response.Body, 4096)
+// DecodeResources decodes all resources from a multi-document YAML or JSON stream.
+// source is only used for error messages.
+func DecodeResources(r io.Reader, source string) ([]unstructured.Unstructured, error) {
+	decoder := yaml.NewYAMLOrJSONDecoder(r, 4096)
 	resources := make([]unstructured.Unstructured, 0)
 	for {
 		object := unstructured.Unstructured{}
@@ -60,7 +109,7 @@ func FetchResources(request *http.Request) ([]unstructured.Unstructured, error)
 			if err == io.EOF {
 				break
 			}
-			return nil, fmt.Errorf("could not decode manifest %v: %w", url, err)
+			return nil, fmt.Errorf("could not decode manifest %v: %w", source, err)
 		}
 		if len(object.Object) == 0 {
 			continue
@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/glasskube/glasskube/internal/config"
+	"golang.org/x/term"
 )
 
 // InteractivityEnabledOrFail checks whether config.NonInteractive is set and immediately aborts if it is not.
@@ -52,6 +53,20 @@ func GetInputStr(label string) (input string) {
 	return
 }
 
+// GetSecretInputStr reads a line from stdin without echoing it, if stdin is a terminal.
+func GetSecretInputStr(label string) string {
+	fmt.Fprintf(os.Stderr, "%v> ", label)
+	InteractivityEnabledOrFail()
+	if fd := int(os.Stdin.Fd()); term.IsTerminal(fd) {
+		data, _ := term.ReadPassword(fd)
+		fmt.Fprintln(os.Stderr)
+		return string(data)
+	}
+	var input string
+	_, _ = fmt.Scanln(&input)
+	return input
+}
+
 func GetOption(label string, options []string) (string, error) {
 	return GetOptionWithDefault(label, options, nil)
 }
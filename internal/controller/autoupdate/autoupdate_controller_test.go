@@ -0,0 +1,165 @@
+package autoupdate_test
+
+import (
+	"context"
+	"time"
+
+	packagesv1alpha1 "github.com/glasskube/glasskube/api/v1alpha1"
+	ctrladapter "github.com/glasskube/glasskube/internal/adapter/controllerruntime"
+	"github.com/glasskube/glasskube/internal/controller"
+	"github.com/glasskube/glasskube/internal/dependency"
+	"github.com/glasskube/glasskube/internal/repo/client/fake"
+	"github.com/glasskube/glasskube/pkg/condition"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("AutoUpdateReconciler", func() {
+	var repo = fake.EmptyClient()
+	var foo *packagesv1alpha1.ClusterPackage
+	var objects []client.Object
+	var c client.Client
+	var checkOnly bool
+
+	BeforeEach(func() {
+		checkOnly = false
+		repo.Clear()
+		repo.AddPackage("foo", "1.0.0", &packagesv1alpha1.PackageManifest{Name: "foo"})
+		repo.AddPackage("foo", "2.0.0", &packagesv1alpha1.PackageManifest{Name: "foo"})
+		foo = &packagesv1alpha1.ClusterPackage{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+			Spec: packagesv1alpha1.PackageSpec{
+				PackageInfo: packagesv1alpha1.PackageInfoTemplate{Name: "foo", Version: "1.0.0"},
+			},
+			Status: packagesv1alpha1.PackageStatus{Version: "1.0.0"},
+		}
+		foo.SetAutoUpdatesEnabled(true)
+		objects = []client.Object{&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "glasskube-system"}}}
+	})
+
+	runOnce := func() {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(packagesv1alpha1.AddToScheme(scheme)).To(Succeed())
+		c = ctrlfake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(append(objects, foo)...).
+			WithStatusSubresource(&packagesv1alpha1.ClusterPackage{}).
+			Build()
+		clientset := fake.ClientsetWithClient(repo)
+		r := &controller.AutoUpdateReconciler{
+			Client:            c,
+			EventRecorder:     record.NewFakeRecorder(100),
+			RepoClientset:     clientset,
+			DependencyManager: dependency.NewDependencyManager(ctrladapter.NewPackageClientAdapter(c), clientset),
+			Interval:          time.Hour,
+			CheckOnly:         checkOnly,
+		}
+		Expect(r.RunOnce(context.Background())).To(Succeed())
+	}
+
+	getFoo := func() *packagesv1alpha1.ClusterPackage {
+		var pkg packagesv1alpha1.ClusterPackage
+		Expect(c.Get(context.Background(), client.ObjectKey{Name: "foo"}, &pkg)).To(Succeed())
+		return &pkg
+	}
+
+	findCondition := func(pkg *packagesv1alpha1.ClusterPackage, conditionType condition.Type) *metav1.Condition {
+		return meta.FindStatusCondition(pkg.Status.Conditions, string(conditionType))
+	}
+
+	It("should update packages with automatic updates enabled", func() {
+		runOnce()
+		pkg := getFoo()
+		Expect(pkg.Spec.PackageInfo.Version).To(Equal("2.0.0"))
+		Expect(findCondition(pkg, condition.AutoUpdated)).To(And(
+			HaveField("Status", metav1.ConditionTrue),
+			HaveField("Reason", string(condition.AutoUpdateSucceeded))))
+		Expect(findCondition(pkg, condition.UpdateAvailable)).To(
+			HaveField("Reason", string(condition.UpToDate)))
+	})
+
+	It("should not update packages outside of their maintenance window", func() {
+		day := time.Now().UTC().AddDate(0, 0, 2).Weekday().String()[:3]
+		foo.Annotations[packagesv1alpha1.AnnotationAutoUpdateWindow] = day + " 00:00-01:00 UTC"
+		runOnce()
+		pkg := getFoo()
+		Expect(pkg.Spec.PackageInfo.Version).To(Equal("1.0.0"))
+		Expect(findCondition(pkg, condition.AutoUpdated)).To(And(
+			HaveField("Status", metav1.ConditionFalse),
+			HaveField("Reason", string(condition.OutsideMaintenanceWindow))))
+		Expect(findCondition(pkg, condition.UpdateAvailable)).To(And(
+			HaveField("Status", metav1.ConditionTrue),
+			HaveField("Reason", string(condition.NewVersionAvailable))))
+	})
+
+	It("should not update packages with dependency conflicts", func() {
+		repo.AddPackage("foo", "2.0.0", &packagesv1alpha1.PackageManifest{
+			Name:         "foo",
+			Dependencies: []packagesv1alpha1.Dependency{{Name: "bar", Version: "^2.0.0"}},
+		})
+		repo.AddPackage("bar", "1.0.0", &packagesv1alpha1.PackageManifest{Name: "bar"})
+		bar := &packagesv1alpha1.ClusterPackage{
+			ObjectMeta: metav1.ObjectMeta{Name: "bar"},
+			Spec: packagesv1alpha1.PackageSpec{
+				PackageInfo: packagesv1alpha1.PackageInfoTemplate{Name: "bar", Version: "1.0.0"},
+			},
+			Status: packagesv1alpha1.PackageStatus{Version: "1.0.0"},
+		}
+		objects = append(objects, bar)
+		runOnce()
+		pkg := getFoo()
+		Expect(pkg.Spec.PackageInfo.Version).To(Equal("1.0.0"))
+		Expect(findCondition(pkg, condition.AutoUpdated)).To(And(
+			HaveField("Status", metav1.ConditionFalse),
+			HaveField("Reason", string(condition.UpdateConflict))))
+	})
+
+	It("should hold back updates that are not allowed by the update policy", func() {
+		repo.AddPackage("foo", "1.0.1", &packagesv1alpha1.PackageManifest{Name: "foo"})
+		repo.AddPackage("foo", "2.0.0", &packagesv1alpha1.PackageManifest{Name: "foo"})
+		foo.Annotations[packagesv1alpha1.AnnotationAutoUpdatePolicy] = "patch"
+		foo.Spec.PackageInfo.Version = "1.0.1"
+		foo.Status.Version = "1.0.1"
+		runOnce()
+		pkg := getFoo()
+		Expect(pkg.Spec.PackageInfo.Version).To(Equal("1.0.1"))
+		Expect(findCondition(pkg, condition.UpdateAvailable)).To(And(
+			HaveField("Status", metav1.ConditionTrue),
+			HaveField("Reason", string(condition.HeldBackByPolicy))))
+	})
+
+	It("should remove the AutoUpdated condition if automatic updates are disabled", func() {
+		foo.SetAutoUpdatesEnabled(false)
+		meta.SetStatusCondition(&foo.Status.Conditions, metav1.Condition{
+			Type:   string(condition.AutoUpdated),
+			Status: metav1.ConditionTrue,
+			Reason: string(condition.AutoUpdateSucceeded),
+		})
+		runOnce()
+		pkg := getFoo()
+		Expect(pkg.Spec.PackageInfo.Version).To(Equal("1.0.0"))
+		Expect(findCondition(pkg, condition.AutoUpdated)).To(BeNil())
+		Expect(findCondition(pkg, condition.UpdateAvailable)).To(
+			HaveField("Reason", string(condition.NewVersionAvailable)))
+	})
+
+	It("should only check for updates if automatic updates are disabled for the operator", func() {
+		checkOnly = true
+		runOnce()
+		pkg := getFoo()
+		Expect(pkg.Spec.PackageInfo.Version).To(Equal("1.0.0"))
+		Expect(findCondition(pkg, condition.AutoUpdated)).To(BeNil())
+		Expect(findCondition(pkg, condition.UpdateAvailable)).To(And(
+			HaveField("Status", metav1.ConditionTrue),
+			HaveField("Reason", string(condition.NewVersionAvailable))))
+	})
+})
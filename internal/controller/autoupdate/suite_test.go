@@ -0,0 +1,15 @@
+// Package autoupdate_test contains the specs of the AutoUpdateReconciler. They use a fake client, so unlike the
+// other controller specs, they do not need the envtest binaries.
+package autoupdate_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestAutoUpdate(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "AutoUpdate Suite")
+}
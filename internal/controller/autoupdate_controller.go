@@ -0,0 +1,224 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	packagesv1alpha1 "github.com/glasskube/glasskube/api/v1alpha1"
+	"github.com/glasskube/glasskube/internal/controller/conditions"
+	"github.com/glasskube/glasskube/internal/controller/ctrlpkg"
+	"github.com/glasskube/glasskube/internal/dependency"
+	"github.com/glasskube/glasskube/internal/maintenancewindow"
+	"github.com/glasskube/glasskube/internal/metrics"
+	repoclient "github.com/glasskube/glasskube/internal/repo/client"
+	"github.com/glasskube/glasskube/pkg/condition"
+	"github.com/glasskube/glasskube/pkg/update"
+	"go.uber.org/multierr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// AutoUpdateReconciler periodically checks all packages for updates.
+// It sets the UpdateAvailable condition and the outdated metric of every package and updates packages that have
+// automatic updates enabled, respecting their maintenance window and update policy. The result of an automatic update
+// is reported in the AutoUpdated condition.
+type AutoUpdateReconciler struct {
+	client.Client
+	EventRecorder     record.EventRecorder
+	RepoClientset     repoclient.RepoClientset
+	DependencyManager *dependency.DependendcyManager
+	Interval          time.Duration
+	// CheckOnly disables automatic updates for all packages. Packages are still checked for updates.
+	CheckOnly bool
+}
+
+// Start checks for updates every Interval until ctx is cancelled.
+func (r *AutoUpdateReconciler) Start(ctx context.Context) error {
+	logger := ctrl.Log.WithName("auto-update")
+	ctx = log.IntoContext(ctx, logger)
+	ticker := time.NewTicker(r.Interval)
+	defer ticker.Stop()
+	for {
+		if err := r.RunOnce(ctx); err != nil {
+			logger.Error(err, "error during auto-update")
+		} else {
+			logger.V(1).Info("auto-update finished")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce checks all packages for updates once.
+func (r *AutoUpdateReconciler) RunOnce(ctx context.Context) error {
+	defaultSchedule, err := r.getDefaultSchedule(ctx)
+	if err != nil {
+		return err
+	}
+
+	var pkgs []ctrlpkg.Package
+	var clusterPackages packagesv1alpha1.ClusterPackageList
+	if err := r.List(ctx, &clusterPackages); err != nil {
+		return fmt.Errorf("could not list ClusterPackages: %w", err)
+	}
+	for i := range clusterPackages.Items {
+		pkgs = append(pkgs, &clusterPackages.Items[i])
+	}
+	var packages packagesv1alpha1.PackageList
+	if err := r.List(ctx, &packages); err != nil {
+		return fmt.Errorf("could not list Packages: %w", err)
+	}
+	for i := range packages.Items {
+		pkgs = append(pkgs, &packages.Items[i])
+	}
+
+	for _, pkg := range pkgs {
+		if !pkg.GetDeletionTimestamp().IsZero() ||
+			pkg.GetStatus().Version != pkg.GetSpec().PackageInfo.Version {
+			// Packages that are being deleted, installed or updated are checked in the next run.
+			continue
+		}
+		if pkgErr := r.reconcilePackage(ctx, pkg, defaultSchedule); pkgErr != nil {
+			multierr.AppendInto(&err, fmt.Errorf("%v: %w", client.ObjectKeyFromObject(pkg), pkgErr))
+		}
+	}
+	return err
+}
+
+func (r *AutoUpdateReconciler) reconcilePackage(
+	ctx context.Context,
+	pkg ctrlpkg.Package,
+	defaultSchedule maintenancewindow.Schedule,
+) error {
+	updater := update.NewPreparer(r.Client, r.RepoClientset, r.DependencyManager).
+		WithMaintenanceWindows(defaultSchedule)
+	tx, err := updater.Prepare(ctx, update.GetExact([]ctrlpkg.Package{pkg}))
+	if err != nil {
+		if conditions.SetUpdateAvailable(ctx, r.EventRecorder, pkg, &pkg.GetStatus().Conditions,
+			metav1.ConditionUnknown, condition.UpdateCheckFailed, err.Error()) {
+			return multierr.Append(err, r.Status().Update(ctx, pkg))
+		}
+		return err
+	}
+
+	installedVersion := pkg.GetSpec().PackageInfo.Version
+	var targetVersion string
+	var conflicts dependency.Conflicts
+	if len(tx.ConflictItems) > 0 {
+		targetVersion = tx.ConflictItems[0].Version
+		conflicts = tx.ConflictItems[0].Conflicts
+	} else if len(tx.Items) > 0 && tx.Items[0].UpdateRequired() {
+		targetVersion = tx.Items[0].Version
+	}
+
+	type autoUpdateResult struct {
+		status  metav1.ConditionStatus
+		reason  condition.Reason
+		message string
+	}
+	var result *autoUpdateResult
+	autoUpdatesEnabled := pkg.AutoUpdatesEnabled() && !r.CheckOnly
+	if autoUpdatesEnabled && targetVersion != "" {
+		if len(conflicts) > 0 {
+			result = &autoUpdateResult{metav1.ConditionFalse, condition.UpdateConflict,
+				fmt.Sprintf("update to %v is not possible due to dependency conflicts: %v", targetVersion, conflicts)}
+		} else if updated, err := updater.Apply(ctx, tx, update.ApplyUpdateOptions{}); err != nil {
+			pkg.GetSpec().PackageInfo.Version = installedVersion
+			result = &autoUpdateResult{metav1.ConditionFalse, condition.AutoUpdateFailed,
+				fmt.Sprintf("update to %v failed: %v", targetVersion, err)}
+		} else if len(updated) > 0 {
+			log.FromContext(ctx).Info("package updated", "name", pkg.GetName(), "namespace", pkg.GetNamespace(),
+				"from", installedVersion, "to", targetVersion)
+			result = &autoUpdateResult{metav1.ConditionTrue, condition.AutoUpdateSucceeded,
+				fmt.Sprintf("updated from %v to %v", installedVersion, targetVersion)}
+			// The update has been applied, so targetVersion is no longer available as an update.
+			installedVersion, targetVersion = targetVersion, ""
+		} else if err := updater.CheckMaintenanceWindow(pkg, time.Now()); errors.Is(err, update.ErrOutsideMaintenanceWindow) {
+			result = &autoUpdateResult{metav1.ConditionFalse, condition.OutsideMaintenanceWindow,
+				fmt.Sprintf("update to %v is pending: %v", targetVersion, err)}
+		} else if err != nil {
+			result = &autoUpdateResult{metav1.ConditionFalse, condition.AutoUpdateFailed, err.Error()}
+		}
+	}
+
+	latestVersion := targetVersion
+	if len(tx.HeldBackItems) > 0 {
+		latestVersion = tx.HeldBackItems[0].Version
+	}
+	metrics.SetPackageOutdated(pkg, latestVersion)
+
+	conds := &pkg.GetStatus().Conditions
+	var changed bool
+	if len(tx.HeldBackItems) > 0 && targetVersion == "" {
+		changed = conditions.SetUpdateAvailable(ctx, r.EventRecorder, pkg, conds, metav1.ConditionTrue,
+			condition.HeldBackByPolicy, fmt.Sprintf("version %v is available but held back by update policy %q",
+				tx.HeldBackItems[0].Version, tx.HeldBackItems[0].Policy))
+	} else if targetVersion != "" {
+		message := fmt.Sprintf("version %v is available", targetVersion)
+		if len(tx.HeldBackItems) > 0 {
+			message += fmt.Sprintf(" (version %v is held back by update policy %q)",
+				tx.HeldBackItems[0].Version, tx.HeldBackItems[0].Policy)
+		}
+		changed = conditions.SetUpdateAvailable(ctx, r.EventRecorder, pkg, conds, metav1.ConditionTrue,
+			condition.NewVersionAvailable, message)
+	} else {
+		changed = conditions.SetUpdateAvailable(ctx, r.EventRecorder, pkg, conds, metav1.ConditionFalse,
+			condition.UpToDate, fmt.Sprintf("version %v is the latest version", installedVersion))
+	}
+	if result != nil {
+		changed = conditions.SetAutoUpdated(ctx, r.EventRecorder, pkg, conds,
+			result.status, result.reason, result.message) || changed
+	} else if !autoUpdatesEnabled {
+		changed = meta.RemoveStatusCondition(conds, string(condition.AutoUpdated)) || changed
+	}
+
+	if changed {
+		return r.Status().Update(ctx, pkg)
+	}
+	return nil
+}
+
+// getDefaultSchedule returns the default maintenance window, which is stored as an annotation of the glasskube-system
+// namespace.
+func (r *AutoUpdateReconciler) getDefaultSchedule(ctx context.Context) (maintenancewindow.Schedule, error) {
+	var ns corev1.Namespace
+	if err := r.Get(ctx, client.ObjectKey{Name: "glasskube-system"}, &ns); err != nil {
+		return nil, fmt.Errorf("could not get namespace glasskube-system: %w", err)
+	} else if schedule, err := maintenancewindow.Parse(
+		ns.Annotations[packagesv1alpha1.AnnotationAutoUpdateWindow]); err != nil {
+		return nil, fmt.Errorf("default maintenance window: %w", err)
+	} else {
+		return schedule, nil
+	}
+}
+
+// SetupWithManager adds the reconciler to the Manager. It only runs on the leader.
+func (r *AutoUpdateReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return mgr.Add(r)
+}
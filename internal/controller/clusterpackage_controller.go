@@ -58,13 +58,13 @@ func (r *ClusterPackageReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	if bld, err := r.baseSetup(mgr, &v1alpha1.ClusterPackage{}, r); err != nil {
 		return err
 	} else {
-		return bld.Complete(r)
+		return r.completeSetup(mgr, bld, r, r)
 	}
 }
 
-func (r *ClusterPackageReconciler) ListPackages(ctx context.Context) ([]ctrlpkg.Package, error) {
+func (r *ClusterPackageReconciler) ListPackages(ctx context.Context, opts ...client.ListOption) ([]ctrlpkg.Package, error) {
 	var l v1alpha1.ClusterPackageList
-	if err := r.Client.List(ctx, &l); err != nil {
+	if err := r.Client.List(ctx, &l, opts...); err != nil {
 		return nil, err
 	}
 	res := make([]ctrlpkg.Package, len(l.Items))
@@ -2,10 +2,12 @@ package controller
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"reflect"
 	"slices"
 	"strings"
+	"time"
 
 	"github.com/glasskube/glasskube/api/v1alpha1"
 	packagesv1alpha1 "github.com/glasskube/glasskube/api/v1alpha1"
@@ -20,17 +22,24 @@ import (
 	"github.com/glasskube/glasskube/internal/dependency"
 	deputil "github.com/glasskube/glasskube/internal/dependency/util"
 	"github.com/glasskube/glasskube/internal/manifest"
+	"github.com/glasskube/glasskube/internal/manifest/drift"
+	"github.com/glasskube/glasskube/internal/manifest/hooks"
 	"github.com/glasskube/glasskube/internal/manifest/result"
 	"github.com/glasskube/glasskube/internal/manifesttransformations"
 	"github.com/glasskube/glasskube/internal/manifestvalues"
+	"github.com/glasskube/glasskube/internal/metrics"
 	"github.com/glasskube/glasskube/internal/names"
+	"github.com/glasskube/glasskube/internal/notification"
 	repoclient "github.com/glasskube/glasskube/internal/repo/client"
 	"github.com/glasskube/glasskube/internal/resourcepatch"
+	"github.com/glasskube/glasskube/internal/revisions"
 	"github.com/glasskube/glasskube/internal/telemetry"
+	"github.com/glasskube/glasskube/internal/tracing"
 	"github.com/glasskube/glasskube/internal/util"
 	"github.com/glasskube/glasskube/pkg/condition"
 	"go.uber.org/multierr"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -42,10 +51,12 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
 const (
 	packageDeletionFinalizer = "packages.glasskube.dev/packageDeletion"
+	valuesChangedReason      = "ValuesChanged"
 )
 
 type PackageReconcilerCommon struct {
@@ -59,6 +70,9 @@ type PackageReconcilerCommon struct {
 	HelmAdapter       manifest.ManifestAdapter
 	KustomizeAdapter  manifest.ManifestAdapter
 	DependencyManager *dependency.DependendcyManager
+	HookRunner        *hooks.Runner
+	// ResourceRefWatcher is set up by completeSetup and starts watches for resources referenced by resourceRef values.
+	ResourceRefWatcher *watch.ResourceRefWatcher
 }
 
 func (r *PackageReconcilerCommon) baseSetup(
@@ -67,6 +81,9 @@ func (r *PackageReconcilerCommon) baseSetup(
 	if r.OwnerManager == nil {
 		r.OwnerManager = owners.NewOwnerManager(r.Scheme)
 	}
+	if r.HookRunner == nil {
+		r.HookRunner = hooks.NewRunner()
+	}
 	if r.ValueResolver == nil {
 		r.ValueResolver = manifestvalues.NewResolver(
 			ctrladapter.NewPackageClientAdapter(r.Client),
@@ -74,21 +91,45 @@ func (r *PackageReconcilerCommon) baseSetup(
 		)
 	}
 
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), object,
+		watch.ValueReferenceIndexKey, watch.IndexValueReferences); err != nil {
+		return nil, err
+	}
+
 	controllerBuilder := ctrl.NewControllerManagedBy(mgr).
 		For(object).
 		Watches(&v1alpha1.PackageInfo{},
 			watch.EnqueueRequestsFromOwnedResource(r.Scheme, lister, watch.OwnedPackageInfos)).
 		Watches(&v1alpha1.ClusterPackage{},
-			watch.EnqueueRequestsFromOwnedResource(r.Scheme, lister, watch.OwnedPackages)).
+			watch.EnqueueRequestsFromMapFuncs(
+				watch.MapOwnedResource(r.Scheme, lister, watch.OwnedPackages),
+				watch.MapValueReference(lister, watch.ClusterPackageGroupKind))).
 		Watches(&v1alpha1.Package{},
-			watch.EnqueueRequestsFromOwnedResource(r.Scheme, lister, watch.OwnedPackages))
+			watch.EnqueueRequestsFromOwnedResource(r.Scheme, lister, watch.OwnedPackages)).
+		Watches(&v1.ConfigMap{},
+			watch.EnqueueRequestsFromValueReference(lister, watch.ConfigMapGroupKind)).
+		Watches(&v1.Secret{},
+			watch.EnqueueRequestsFromValueReference(lister, watch.SecretGroupKind))
 
 	if err := r.InitAdapters(controllerBuilder); err != nil {
 		return nil, err
+	} else if err := r.HookRunner.ControllerInit(controllerBuilder, r.Client, r.RepoClientset, r.Scheme); err != nil {
+		return nil, err
 	}
 	return controllerBuilder, nil
 }
 
+// completeSetup builds the controller and creates the ResourceRefWatcher, which requires the built controller.
+func (r *PackageReconcilerCommon) completeSetup(
+	mgr ctrl.Manager, bld *builder.Builder, rec reconcile.Reconciler, lister watch.PackageLister) error {
+	if c, err := bld.Build(rec); err != nil {
+		return err
+	} else {
+		r.ResourceRefWatcher = watch.NewResourceRefWatcher(c, mgr.GetCache(), mgr.GetRESTMapper(), lister)
+		return nil
+	}
+}
+
 func (r *PackageReconcilerCommon) InitAdapters(builder *builder.Builder) error {
 	for _, adapter := range []manifest.ManifestAdapter{r.HelmAdapter, r.KustomizeAdapter, r.ManifestAdapter} {
 		if adapter != nil {
@@ -100,7 +141,16 @@ func (r *PackageReconcilerCommon) InitAdapters(builder *builder.Builder) error {
 	return nil
 }
 
-func (r *PackageReconcilerCommon) reconcile(ctx context.Context, pkg ctrlpkg.Package) (ctrl.Result, error) {
+// namedAdapter is a manifest adapter together with the name of the manifest type it handles.
+type namedAdapter struct {
+	name string
+	manifest.ManifestAdapter
+}
+
+func (r *PackageReconcilerCommon) reconcile(ctx context.Context, pkg ctrlpkg.Package) (_ ctrl.Result, err error) {
+	ctx, span := tracing.Start(ctx, "reconcile package", tracing.PackageAttributes(pkg)...)
+	defer func() { tracing.End(span, err) }()
+
 	prc := &PackageReconcilationContext{PackageReconcilerCommon: r, pkg: pkg}
 	log := ctrl.LoggerFrom(ctx)
 
@@ -144,6 +194,7 @@ type PackageReconcilationContext struct {
 	shouldUpdateResource  bool
 	currentOwnedResources []v1alpha1.OwnedResourceRef
 	currentOwnedPackages  []v1alpha1.OwnedResourceRef
+	resolvedValuesHash    string
 }
 
 func (r *PackageReconcilationContext) setShouldUpdate(value bool) {
@@ -163,8 +214,14 @@ func (r *PackageReconcilationContext) reconcilePackageInfoReady(ctx context.Cont
 		return r.finalize(ctx)
 	}
 
+	if r.ResourceRefWatcher != nil {
+		if err := r.ResourceRefWatcher.Watch(r.pkg.GetSpec().Values); err != nil {
+			ctrl.LoggerFrom(ctx).Error(err, "could not watch referenced resources")
+		}
+	}
+
 	var patches []resourcepatch.TargetPatch
-	if resolvedValues, err := r.ValueResolver.Resolve(ctx, r.pkg.GetSpec().Values); err != nil {
+	if resolvedValues, err := r.resolveValues(ctx); err != nil {
 		r.setShouldUpdate(
 			conditions.SetFailed(ctx, r.EventRecorder, r.pkg, &r.pkg.GetStatus().Conditions,
 				condition.ValueConfigurationInvalid, err.Error()))
@@ -174,19 +231,25 @@ func (r *PackageReconcilationContext) reconcilePackageInfoReady(ctx context.Cont
 			conditions.SetFailed(ctx, r.EventRecorder, r.pkg, &r.pkg.GetStatus().Conditions,
 				condition.ValueConfigurationInvalid, err.Error()))
 		return r.finalizeWithError(ctx, err)
-	} else if p, err := resourcepatch.GeneratePatches(*piManifest, resolvedValues); err != nil {
+	} else if p, err := resourcepatch.GeneratePatches(
+		*piManifest, manifestvalues.ApplicableValues(*piManifest, resolvedValues)); err != nil {
 		r.setShouldUpdate(
 			conditions.SetFailed(ctx, r.EventRecorder, r.pkg, &r.pkg.GetStatus().Conditions,
-				condition.InstallationFailed, err.Error()))
+				patchErrorReason(err), err.Error()))
 		return r.finalizeWithError(ctx, err)
 	} else {
 		patches = p
+		if hash, err := manifestvalues.Hash(piManifest, r.pkg.GetSpec().Values, resolvedValues); err != nil {
+			ctrl.LoggerFrom(ctx).Error(err, "could not hash resolved values")
+		} else {
+			r.resolvedValuesHash = hash
+		}
 	}
 
 	if p, err := manifesttransformations.ResolveAndGeneratePatches(ctx, r.Client, r.pkg, piManifest); err != nil {
 		r.setShouldUpdate(
 			conditions.SetFailed(ctx, r.EventRecorder, r.pkg, &r.pkg.GetStatus().Conditions,
-				condition.InstallationFailed, err.Error()))
+				patchErrorReason(err), err.Error()))
 		return r.finalizeWithError(ctx, err)
 	} else {
 		patches = append(patches, p...)
@@ -195,7 +258,7 @@ func (r *PackageReconcilationContext) reconcilePackageInfoReady(ctx context.Cont
 	// First, collect the adapters for all included manifests and ensure that they are supported.
 	// If one manifest type is not supported, no action must be performed!
 
-	var adaptersToRun []manifest.ManifestAdapter
+	var adaptersToRun []namedAdapter
 	if len(piManifest.Manifests) > 0 {
 		if r.ManifestAdapter == nil {
 			r.setShouldUpdate(
@@ -203,7 +266,7 @@ func (r *PackageReconcilationContext) reconcilePackageInfoReady(ctx context.Cont
 					condition.UnsupportedFormat, "manifests not supported"))
 			return r.finalizeNoRequeue(ctx)
 		}
-		adaptersToRun = append(adaptersToRun, r.ManifestAdapter)
+		adaptersToRun = append(adaptersToRun, namedAdapter{"manifests", r.ManifestAdapter})
 	}
 	if piManifest.Kustomize != nil {
 		if r.KustomizeAdapter == nil {
@@ -212,7 +275,7 @@ func (r *PackageReconcilationContext) reconcilePackageInfoReady(ctx context.Cont
 					condition.UnsupportedFormat, "kustomize not supported"))
 			return r.finalizeNoRequeue(ctx)
 		}
-		adaptersToRun = append(adaptersToRun, r.KustomizeAdapter)
+		adaptersToRun = append(adaptersToRun, namedAdapter{"kustomize", r.KustomizeAdapter})
 	}
 	if piManifest.Helm != nil {
 		if r.HelmAdapter == nil {
@@ -221,13 +284,18 @@ func (r *PackageReconcilationContext) reconcilePackageInfoReady(ctx context.Cont
 					condition.UnsupportedFormat, "helm not supported"))
 			return r.finalizeNoRequeue(ctx)
 		}
-		adaptersToRun = append(adaptersToRun, r.HelmAdapter)
+		adaptersToRun = append(adaptersToRun, namedAdapter{"helm", r.HelmAdapter})
+	}
+
+	preHookPhase, postHookPhase, runHooks := hooks.ApplyPhases(r.pkg.GetStatus().Version, r.pi.Status.Version)
+	if runHooks && !r.runHooks(ctx, preHookPhase, r.pi.Status.Version, patches) {
+		return r.finalize(ctx)
 	}
 
 	results := make([]result.ReconcileResult, 0, len(adaptersToRun))
 	var errs error
 	for _, adapter := range adaptersToRun {
-		if result, err := adapter.Reconcile(ctx, r.pkg, r.pi, patches); err != nil {
+		if result, err := r.reconcileAdapter(ctx, adapter, patches); err != nil {
 			errs = multierr.Append(errs, err)
 		} else {
 			results = append(results, *result)
@@ -240,7 +308,12 @@ func (r *PackageReconcilationContext) reconcilePackageInfoReady(ctx context.Cont
 			conditions.SetFailed(ctx, r.EventRecorder, r.pkg, &r.pkg.GetStatus().Conditions,
 				condition.InstallationFailed, errs.Error()))
 		return r.finalizeWithError(ctx, errs)
-	} else if !r.handleAdapterResults(ctx, results) {
+	}
+
+	r.updateDrift(ctx, results)
+	if !r.handleAdapterResults(ctx, results) {
+		return r.finalize(ctx)
+	} else if runHooks && !r.runHooks(ctx, postHookPhase, r.pi.Status.Version, patches) {
 		return r.finalize(ctx)
 	} else {
 		r.afterSuccess(ctx, results)
@@ -248,6 +321,111 @@ func (r *PackageReconcilationContext) reconcilePackageInfoReady(ctx context.Cont
 	}
 }
 
+func (r *PackageReconcilationContext) resolveValues(ctx context.Context) (_ map[string]string, err error) {
+	ctx, span := tracing.Start(ctx, "resolve values")
+	defer func() { tracing.End(span, err) }()
+	return r.ValueResolver.ResolvePackage(ctx, r.pkg)
+}
+
+func (r *PackageReconcilationContext) reconcileAdapter(
+	ctx context.Context,
+	adapter namedAdapter,
+	patches resourcepatch.TargetPatches,
+) (_ *result.ReconcileResult, err error) {
+	ctx, span := tracing.Start(ctx, "reconcile adapter", tracing.AttributeAdapter.String(adapter.name))
+	defer func() { tracing.End(span, err) }()
+	start := time.Now()
+	defer func() {
+		metrics.AdapterReconcileDuration.WithLabelValues(adapter.name).Observe(time.Since(start).Seconds())
+	}()
+	return adapter.Reconcile(ctx, r.pkg, r.pi, patches)
+}
+
+// runHooks runs the hooks of the given phase and returns true if all of them have completed.
+// Otherwise, the package conditions are updated accordingly.
+func (r *PackageReconcilationContext) runHooks(
+	ctx context.Context,
+	phase v1alpha1.HookPhase,
+	version string,
+	patches resourcepatch.TargetPatches,
+) bool {
+	res, changed, err := r.HookRunner.Run(ctx, r.pkg, r.pi, phase, version, patches)
+	return r.handleHookResult(ctx, res, changed, err)
+}
+
+// runPreDeleteHooks runs the pre-delete hooks of the installed version and returns true if all of them have
+// completed or failed or if they can not be run, because the package was never installed, its manifest is no longer
+// available or its namespace is already being deleted.
+func (r *PackageReconcilationContext) runPreDeleteHooks(ctx context.Context) bool {
+	log := ctrl.LoggerFrom(ctx)
+	version := r.pkg.GetStatus().Version
+	if version == "" {
+		return true
+	}
+
+	var pi v1alpha1.PackageInfo
+	if err := r.Get(ctx, types.NamespacedName{Name: names.PackageInfoName(r.pkg)}, &pi); err != nil {
+		if !apierrors.IsNotFound(err) {
+			log.Error(err, "could not get PackageInfo for pre-delete hooks")
+		}
+		return true
+	} else if pi.Status.Manifest == nil || len(hooks.ForPhase(pi.Status.Manifest, v1alpha1.HookPreDelete)) == 0 {
+		return true
+	}
+	r.pi = &pi
+
+	var patches resourcepatch.TargetPatches
+	if resolvedValues, err := r.ValueResolver.ResolvePackage(ctx, r.pkg); err != nil {
+		log.Error(err, "could not resolve values for pre-delete hooks")
+	} else if p, err := resourcepatch.GeneratePatches(
+		*pi.Status.Manifest, manifestvalues.ApplicableValues(*pi.Status.Manifest, resolvedValues)); err != nil {
+		log.Error(err, "could not generate patches for pre-delete hooks")
+	} else {
+		patches = p
+	}
+
+	res, changed, err := r.HookRunner.Run(ctx, r.pkg, r.pi, v1alpha1.HookPreDelete, version, patches)
+	if apierrors.HasStatusCause(err, v1.NamespaceTerminatingCause) {
+		// Hook resources can not be created anymore. Waiting would block the deletion of the namespace forever.
+		log.Info("skipping pre-delete hooks because the namespace is terminating")
+		return true
+	} else if err == nil && res.IsFailed() {
+		// Pre-delete hooks fail open. Otherwise, a failed hook would block the deletion of the package forever.
+		r.setShouldUpdate(changed)
+		r.Event(r.pkg, "Warning", string(condition.HookFailed), res.Message+", continuing with deletion")
+		return true
+	}
+	return r.handleHookResult(ctx, res, changed, err)
+}
+
+// handleHookResult updates the package conditions according to the result of a hook run and returns true if all
+// hooks have completed.
+func (r *PackageReconcilationContext) handleHookResult(
+	ctx context.Context,
+	res *result.ReconcileResult,
+	statusChanged bool,
+	err error,
+) bool {
+	r.setShouldUpdate(statusChanged)
+	if err != nil {
+		r.setShouldUpdate(
+			conditions.SetFailed(ctx, r.EventRecorder, r.pkg, &r.pkg.GetStatus().Conditions,
+				condition.HookFailed, err.Error()))
+		return false
+	} else if res.IsFailed() {
+		r.setShouldUpdate(
+			conditions.SetFailed(ctx, r.EventRecorder, r.pkg, &r.pkg.GetStatus().Conditions,
+				condition.HookFailed, res.Message))
+		return false
+	} else if res.IsWaiting() {
+		r.setShouldUpdate(
+			conditions.SetUnknown(ctx, &r.pkg.GetStatus().Conditions, condition.Pending, res.Message))
+		return false
+	} else {
+		return true
+	}
+}
+
 func (r *PackageReconcilationContext) reconcileSuspended(ctx context.Context) (ctrl.Result, error) {
 	log := ctrl.LoggerFrom(ctx)
 	log.Info("skipping reconciliation for suspended package")
@@ -265,6 +443,11 @@ func (r *PackageReconcilationContext) reconcileAfterDeletion(ctx context.Context
 	}
 
 	if slices.Contains(r.pkg.GetFinalizers(), "packages.glasskube.dev/packageDeletion") {
+		if !r.runPreDeleteHooks(ctx) {
+			log.Info("waiting for pre-delete hooks")
+			return r.finalize(ctx)
+		}
+
 		var err error
 		if len(r.pkg.GetStatus().OwnedPackages) != 0 {
 			multierr.AppendInto(&err, r.pruneOwnedPackages(ctx, true))
@@ -405,8 +588,12 @@ func (r *PackageReconcilationContext) ensureDependencies(ctx context.Context) bo
 	} else if result.Status == dependency.ValidationResultStatusConflict {
 		var parts []string
 		for _, c := range result.Conflicts {
-			parts = append(parts, fmt.Sprintf("need version %v of %v but found %v",
-				c.Required.Version, c.Actual.Name, c.Actual.Version))
+			if c.Explanation != nil {
+				parts = append(parts, c.Explanation.String())
+			} else {
+				parts = append(parts, fmt.Sprintf("need version %v of %v but found %v",
+					c.Required.Version, c.Actual.Name, c.Actual.Version))
+			}
 		}
 		r.setShouldUpdate(
 			conditions.SetFailed(ctx, r.EventRecorder, r.pkg, &r.pkg.GetStatus().Conditions,
@@ -513,6 +700,7 @@ func (r *PackageReconcilationContext) ensurePackageInfo(ctx context.Context) err
 			Name:           r.pkg.GetSpec().PackageInfo.Name,
 			Version:        r.pkg.GetSpec().PackageInfo.Version,
 			RepositoryName: r.pkg.GetSpec().PackageInfo.RepositoryName,
+			SyncInterval:   r.pkg.GetSpec().PackageInfo.SyncInterval,
 		}
 		return nil
 	})
@@ -584,11 +772,78 @@ func (r *PackageReconcilationContext) afterSuccess(ctx context.Context, results
 
 	r.setShouldUpdate(
 		conditions.SetReady(ctx, r.EventRecorder, r.pkg, &r.pkg.GetStatus().Conditions, reason, message))
+	if previousVersion := r.pkg.GetStatus().Version; previousVersion == "" {
+		notification.ForOperator().Notify(r.pkg, v1alpha1.NotificationEventInstalled, reason, message)
+	} else if previousVersion != r.pi.Status.Version {
+		notification.ForOperator().Notify(r.pkg, v1alpha1.NotificationEventUpdated, reason,
+			fmt.Sprintf("updated from %v to %v", previousVersion, r.pi.Status.Version))
+	}
 	r.setShouldUpdate(r.pkg.GetStatus().Version != r.pi.Status.Version)
 	r.pkg.GetStatus().Version = r.pi.Status.Version
+	r.updateResolvedValuesHash()
 	r.isSuccess = true
 }
 
+// updateDrift records the drift found by the manifest adapters in the package status and sets the Drifted condition
+// accordingly. Corrected drift is only reported with an event. If drift detection is disabled, both are removed.
+func (r *PackageReconcilationContext) updateDrift(ctx context.Context, results []result.ReconcileResult) {
+	status := r.pkg.GetStatus()
+	policy := r.pkg.GetSpec().DriftPolicy
+	var resourceDrift []v1alpha1.ResourceDrift
+	for _, result := range results {
+		resourceDrift = append(resourceDrift, result.Drift...)
+	}
+
+	if policy == "" {
+		r.setShouldUpdate(meta.RemoveStatusCondition(&status.Conditions, string(condition.Drifted)))
+	} else if len(resourceDrift) == 0 {
+		r.setShouldUpdate(
+			conditions.SetNotDrifted(ctx, &status.Conditions, condition.NoDrift, "no drift detected"))
+	} else if policy == v1alpha1.DriftCorrect {
+		message := drift.Message(resourceDrift)
+		r.Event(r.pkg, "Warning", string(condition.DriftCorrected), message)
+		r.setShouldUpdate(
+			conditions.SetNotDrifted(ctx, &status.Conditions, condition.DriftCorrected, message))
+		resourceDrift = nil
+	} else {
+		r.setShouldUpdate(
+			conditions.SetDrifted(ctx, r.EventRecorder, r.pkg, &status.Conditions,
+				condition.DriftDetected, drift.Message(resourceDrift)))
+	}
+
+	if !equality.Semantic.DeepEqual(status.Drift, resourceDrift) {
+		status.Drift = resourceDrift
+		r.setShouldUpdate(true)
+	}
+}
+
+// updateResolvedValuesHash records the hash of the values that were just applied and emits an event if they changed
+// since the last successful reconciliation.
+func (r *PackageReconcilationContext) updateResolvedValuesHash() {
+	status := r.pkg.GetStatus()
+	if r.resolvedValuesHash == "" || status.ResolvedValuesHash == r.resolvedValuesHash {
+		return
+	}
+	if status.ResolvedValuesHash != "" {
+		r.Event(r.pkg, "Normal", valuesChangedReason,
+			fmt.Sprintf("applied changed values (hash %v)", r.resolvedValuesHash))
+	}
+	status.ResolvedValuesHash = r.resolvedValuesHash
+	r.setShouldUpdate(true)
+}
+
+// recordRevision records the version and values of the package in its revision history, together with the outcome
+// of this reconciliation.
+func (r *PackageReconcilationContext) recordRevision() {
+	outcome := v1alpha1.RevisionPending
+	if meta.IsStatusConditionTrue(r.pkg.GetStatus().Conditions, string(condition.Failed)) {
+		outcome = v1alpha1.RevisionFailed
+	} else if r.isSuccess {
+		outcome = v1alpha1.RevisionSucceeded
+	}
+	r.setShouldUpdate(revisions.Record(&r.pkg.GetStatus().Revisions, *r.pkg.GetSpec(), outcome, metav1.Now()))
+}
+
 func (r *PackageReconcilationContext) finalize(ctx context.Context) (ctrl.Result, error) {
 	return requeue.Always(ctx, r.actualFinalize(ctx))
 }
@@ -618,6 +873,16 @@ func (r *PackageReconcilationContext) actualFinalize(ctx context.Context) error
 		log.V(1).Info("cleanup done")
 	}
 
+	if r.pi != nil && r.pkg.GetDeletionTimestamp().IsZero() {
+		r.recordRevision()
+	}
+
+	if r.pkg.GetDeletionTimestamp().IsZero() {
+		metrics.SetPackageStatus(r.pkg)
+	} else {
+		metrics.DeletePackage(r.pkg)
+	}
+
 	if r.shouldUpdateStatus {
 		if err := r.Status().Update(ctx, r.pkg); err != nil {
 			log.Error(err, "package status update failed")
@@ -817,3 +1082,13 @@ func (r *PackageReconcilerCommon) getAllPackagesAndClusterPackages(ctx context.C
 
 	return allPackages, nil
 }
+
+// patchErrorReason returns the condition reason for an error that occurred while generating patches.
+// Errors caused by a valueTemplate are a problem of the package configuration rather than of the installation.
+func patchErrorReason(err error) condition.Reason {
+	var templateErr *resourcepatch.TemplateError
+	if errors.As(err, &templateErr) {
+		return condition.ValueConfigurationInvalid
+	}
+	return condition.InstallationFailed
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/glasskube/glasskube/api/v1alpha1"
+	"github.com/glasskube/glasskube/internal/notification"
 	"github.com/glasskube/glasskube/internal/telemetry"
 	"github.com/glasskube/glasskube/pkg/condition"
 	"k8s.io/apimachinery/pkg/api/meta"
@@ -71,6 +73,7 @@ func SetFailed(ctx context.Context, recorder record.EventRecorder, obj client.Ob
 	)
 	if changed {
 		telemetry.ForOperator().OnEvent(obj, condition.Failed, reason)
+		notification.ForOperator().Notify(obj, v1alpha1.NotificationEventFailed, reason, message)
 	}
 	return changed
 }
@@ -83,6 +86,57 @@ func SetFailedAndUpdate(ctx context.Context, client client.Client, recorder reco
 	return nil
 }
 
+// SetDrifted sets the Drifted condition to Status=True and records a warning event.
+func SetDrifted(ctx context.Context, recorder record.EventRecorder, obj client.Object, objConditions *[]metav1.Condition, reason condition.Reason, message string) bool {
+	log := log.FromContext(ctx)
+	log.V(1).Info("set condition to drifted: " + message)
+	recorder.Event(obj, "Warning", string(reason), message)
+	return setStatusConditions(objConditions,
+		metav1.Condition{Type: string(condition.Drifted), Status: metav1.ConditionTrue, Reason: string(reason), Message: message},
+	)
+}
+
+// SetNotDrifted sets the Drifted condition to Status=False.
+func SetNotDrifted(ctx context.Context, objConditions *[]metav1.Condition, reason condition.Reason, message string) bool {
+	log := log.FromContext(ctx)
+	log.V(1).Info("set condition to not drifted: " + message)
+	return setStatusConditions(objConditions,
+		metav1.Condition{Type: string(condition.Drifted), Status: metav1.ConditionFalse, Reason: string(reason), Message: message},
+	)
+}
+
+// SetUpdateAvailable sets the UpdateAvailable condition and records an event if the condition has changed.
+// If an update has become available, NotificationChannels are notified as well.
+func SetUpdateAvailable(ctx context.Context, recorder record.EventRecorder, obj client.Object, objConditions *[]metav1.Condition, status metav1.ConditionStatus, reason condition.Reason, message string) bool {
+	changed := setConditionWithEvent(ctx, recorder, obj, objConditions, "Normal", condition.UpdateAvailable, status, reason, message)
+	if changed && status == metav1.ConditionTrue {
+		notification.ForOperator().Notify(obj, v1alpha1.NotificationEventUpdateAvailable, reason, message)
+	}
+	return changed
+}
+
+// SetAutoUpdated sets the AutoUpdated condition and records an event if the condition has changed.
+// Events for Status=False are recorded as warnings.
+func SetAutoUpdated(ctx context.Context, recorder record.EventRecorder, obj client.Object, objConditions *[]metav1.Condition, status metav1.ConditionStatus, reason condition.Reason, message string) bool {
+	eventType := "Normal"
+	if status == metav1.ConditionFalse {
+		eventType = "Warning"
+	}
+	return setConditionWithEvent(ctx, recorder, obj, objConditions, eventType, condition.AutoUpdated, status, reason, message)
+}
+
+func setConditionWithEvent(ctx context.Context, recorder record.EventRecorder, obj client.Object, objConditions *[]metav1.Condition, eventType string, conditionType condition.Type, status metav1.ConditionStatus, reason condition.Reason, message string) bool {
+	log := log.FromContext(ctx)
+	changed := setStatusConditions(objConditions,
+		metav1.Condition{Type: string(conditionType), Status: status, Reason: string(reason), Message: message},
+	)
+	if changed {
+		log.V(1).Info(fmt.Sprintf("set condition %v to %v: %v", conditionType, status, message))
+		recorder.Event(obj, eventType, string(reason), message)
+	}
+	return changed
+}
+
 func updateAfterConditionsChanged(ctx context.Context, cl client.Client, obj client.Object) error {
 	log := log.FromContext(ctx)
 	log.V(1).Info("Updating status after conditions changed")
@@ -37,6 +37,8 @@ type PackageReconciler struct {
 //+kubebuilder:rbac:groups=helm.toolkit.fluxcd.io,resources=helmreleases,verbs=get;list;watch;create;update
 //+kubebuilder:rbac:groups=source.toolkit.fluxcd.io,resources=helmrepositories,verbs=get;list;watch;create;update
 //+kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+//+kubebuilder:rbac:groups=core,resources=configmaps;secrets,verbs=get;list;watch
+//+kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;update;patch;delete
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -61,13 +63,13 @@ func (r *PackageReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	if bld, err := r.baseSetup(mgr, &v1alpha1.Package{}, r); err != nil {
 		return err
 	} else {
-		return bld.Complete(r)
+		return r.completeSetup(mgr, bld, r, r)
 	}
 }
 
-func (r *PackageReconciler) ListPackages(ctx context.Context) ([]ctrlpkg.Package, error) {
+func (r *PackageReconciler) ListPackages(ctx context.Context, opts ...client.ListOption) ([]ctrlpkg.Package, error) {
 	var l v1alpha1.PackageList
-	if err := r.Client.List(ctx, &l); err != nil {
+	if err := r.Client.List(ctx, &l, opts...); err != nil {
 		return nil, err
 	}
 	res := make([]ctrlpkg.Package, len(l.Items))
@@ -25,6 +25,7 @@ import (
 	"github.com/glasskube/glasskube/internal/controller/owners"
 	"github.com/glasskube/glasskube/internal/controller/requeue"
 	repoclient "github.com/glasskube/glasskube/internal/repo/client"
+	"github.com/glasskube/glasskube/internal/tracing"
 	"github.com/glasskube/glasskube/pkg/condition"
 	"go.uber.org/multierr"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -44,9 +45,8 @@ type PackageInfoReconciler struct {
 }
 
 var (
-	// 5 minutes in nanoseconds.
-	// TODO: let users configure this value per PackageInfo or per repository
-	repositorySyncInterval = 5 * time.Minute
+	// defaultRepositorySyncInterval is used if neither the PackageInfo nor its PackageRepository specify a sync interval.
+	defaultRepositorySyncInterval = 5 * time.Minute
 )
 
 //+kubebuilder:rbac:groups=packages.glasskube.dev,resources=packageinfos,verbs=get;list;watch;create;update;patch;delete
@@ -71,9 +71,10 @@ func (r *PackageInfoReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
-	if shouldSyncFromRepo(packageInfo) {
+	syncInterval := r.getSyncInterval(ctx, &packageInfo)
+	if shouldSyncFromRepo(packageInfo, syncInterval) {
 		log.Info("updating manifest")
-		if err := r.updatePackageManifest(&packageInfo); err != nil {
+		if err := r.updatePackageManifest(ctx, &packageInfo); err != nil {
 			err1 := conditions.SetFailedAndUpdate(ctx, r.Client, r.EventRecorder, &packageInfo, &packageInfo.Status.Conditions,
 				condition.SyncFailed, err.Error())
 			return requeue.Always(ctx, multierr.Append(err, err1))
@@ -94,10 +95,63 @@ func (r *PackageInfoReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 				r.Event(&packageInfo, "Warning", string(condition.SyncFailed), err.Error())
 				return requeue.Always(ctx, err)
 			}
+			if packageInfo.IsSyncRequested() {
+				if err := r.removeSyncRequestedAnnotation(ctx, &packageInfo); err != nil {
+					return requeue.Always(ctx, err)
+				}
+			}
+		}
+	}
+
+	return requeue.After(ctx, nil, nextSyncAfter(packageInfo, syncInterval))
+}
+
+// getSyncInterval returns the sync interval of the PackageInfo, which can be inherited from its PackageRepository.
+func (r *PackageInfoReconciler) getSyncInterval(ctx context.Context, pi *packagesv1alpha1.PackageInfo) time.Duration {
+	if pi.Spec.SyncInterval != nil {
+		return pi.Spec.SyncInterval.Duration
+	}
+	if repo, err := r.getPackageRepository(ctx, pi.Spec.RepositoryName); err != nil {
+		// Errors related to the repository are reported when syncing, so we can just use the default here.
+		ctrl.LoggerFrom(ctx).V(1).Info("could not get repository for sync interval", "error", err)
+	} else if repo != nil && repo.Spec.SyncInterval != nil {
+		return repo.Spec.SyncInterval.Duration
+	}
+	return defaultRepositorySyncInterval
+}
+
+// getPackageRepository returns the repository with the given name or the default repository if name is empty.
+// If there is no default repository, nil is returned.
+func (r *PackageInfoReconciler) getPackageRepository(
+	ctx context.Context,
+	name string,
+) (*packagesv1alpha1.PackageRepository, error) {
+	if name != "" {
+		var repo packagesv1alpha1.PackageRepository
+		if err := r.Get(ctx, client.ObjectKey{Name: name}, &repo); err != nil {
+			return nil, err
+		}
+		return &repo, nil
+	}
+	var repos packagesv1alpha1.PackageRepositoryList
+	if err := r.List(ctx, &repos); err != nil {
+		return nil, err
+	}
+	for i := range repos.Items {
+		if repos.Items[i].IsDefaultRepository() {
+			return &repos.Items[i], nil
 		}
 	}
+	return nil, nil
+}
 
-	return requeue.Always(ctx, nil)
+func (r *PackageInfoReconciler) removeSyncRequestedAnnotation(
+	ctx context.Context,
+	pi *packagesv1alpha1.PackageInfo,
+) error {
+	patch := client.MergeFrom(pi.DeepCopy())
+	delete(pi.Annotations, packagesv1alpha1.SyncRequestedAnnotation)
+	return r.Patch(ctx, pi, patch)
 }
 
 func (r *PackageInfoReconciler) isLatestResourceVersion(ctx context.Context, packageInfo *packagesv1alpha1.PackageInfo) (bool, error) {
@@ -110,15 +164,44 @@ func (r *PackageInfoReconciler) isLatestResourceVersion(ctx context.Context, pac
 	}
 }
 
-func shouldSyncFromRepo(pi packagesv1alpha1.PackageInfo) bool {
+func shouldSyncFromRepo(pi packagesv1alpha1.PackageInfo, syncInterval time.Duration) bool {
 	return pi.Status.LastUpdateTimestamp == nil ||
+		pi.IsSyncRequested() ||
 		(pi.Spec.Version != "" && pi.Spec.Version != pi.Status.Version) ||
-		time.Since(pi.Status.LastUpdateTimestamp.Time) > repositorySyncInterval
+		time.Since(pi.Status.LastUpdateTimestamp.Time) > syncInterval
+}
+
+// nextSyncAfter returns the duration after which the PackageInfo should be reconciled again.
+// The result is capped at the default requeue duration, so that changes to the sync interval of the repository are
+// picked up in a timely manner.
+func nextSyncAfter(pi packagesv1alpha1.PackageInfo, syncInterval time.Duration) time.Duration {
+	if pi.Status.LastUpdateTimestamp == nil {
+		return requeue.RequeueDuration
+	}
+	return max(min(syncInterval-time.Since(pi.Status.LastUpdateTimestamp.Time), requeue.RequeueDuration), time.Second)
 }
 
-func (r *PackageInfoReconciler) updatePackageManifest(pi *packagesv1alpha1.PackageInfo) error {
+// updatePackageManifest fetches the manifest of pi from its repository.
+func (r *PackageInfoReconciler) updatePackageManifest(
+	ctx context.Context, pi *packagesv1alpha1.PackageInfo,
+) (err error) {
+	ctx, span := tracing.Start(ctx, "fetch package manifest",
+		tracing.AttributeRepository.String(pi.Spec.RepositoryName),
+		tracing.AttributePackage.String(pi.Spec.Name),
+		tracing.AttributePackageVersion.String(pi.Spec.Version))
+	defer func() { tracing.End(span, err) }()
+
 	var manifest packagesv1alpha1.PackageManifest
-	repo := r.RepoClient.ForRepoWithName(pi.Spec.RepositoryName)
+	repo := repoclient.WithContext(ctx, r.RepoClient.ForRepoWithName(pi.Spec.RepositoryName))
+	if verifier, ok := repo.(repoclient.PackageManifestVerifier); ok {
+		if fingerprint, err := verifier.VerifyPackageManifest(pi.Spec.Name, pi.Spec.Version); err != nil {
+			return err
+		} else {
+			pi.Status.SignatureKeyFingerprint = fingerprint
+		}
+	} else {
+		pi.Status.SignatureKeyFingerprint = ""
+	}
 	if err := repo.FetchPackageManifest(pi.Spec.Name, pi.Spec.Version, &manifest); err != nil {
 		return err
 	}
@@ -19,13 +19,17 @@ package controller
 import (
 	"context"
 	"fmt"
+	"time"
 
 	packagesv1alpha1 "github.com/glasskube/glasskube/api/v1alpha1"
 	"github.com/glasskube/glasskube/internal/controller/requeue"
+	"github.com/glasskube/glasskube/internal/metrics"
 	repoclient "github.com/glasskube/glasskube/internal/repo/client"
 	repotypes "github.com/glasskube/glasskube/internal/repo/types"
+	"github.com/glasskube/glasskube/internal/tracing"
 	"github.com/glasskube/glasskube/pkg/condition"
 	"go.uber.org/multierr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -59,12 +63,26 @@ func (r *PackageRepositoryReconciler) Reconcile(ctx context.Context, req ctrl.Re
 
 	var repo packagesv1alpha1.PackageRepository
 	if err := r.Get(ctx, req.NamespacedName, &repo); err != nil {
+		if apierrors.IsNotFound(err) {
+			metrics.DeleteRepository(req.Name)
+			r.RepoClient.Invalidate(req.Name)
+		}
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
+	if repo.IsSyncRequested() {
+		// Discard cached data, so that changes that were published recently are picked up immediately.
+		r.RepoClient.Invalidate(repo.Name)
+	}
+
 	var index repotypes.PackageRepoIndex
 	var cond metav1.Condition
-	err := r.RepoClient.ForRepo(repo).FetchPackageRepoIndex(&index)
+	spanCtx, span := tracing.Start(ctx, "fetch package repository index",
+		tracing.AttributeRepository.String(repo.Name))
+	start := time.Now()
+	err := repoclient.WithContext(spanCtx, r.RepoClient.ForRepo(repo)).FetchPackageRepoIndex(&index)
+	metrics.ObserveRepositorySync(repo.Name, time.Since(start), err)
+	tracing.End(span, err)
 	if err != nil {
 		cond = metav1.Condition{
 			Type:    string(condition.Ready),
@@ -85,7 +103,45 @@ func (r *PackageRepositoryReconciler) Reconcile(ctx context.Context, req ctrl.Re
 		multierr.AppendInto(&err, r.Status().Update(ctx, &repo))
 	}
 
-	return requeue.Always(ctx, err)
+	if err == nil && repo.IsSyncRequested() {
+		err = r.requestPackageInfoSync(ctx, &repo)
+	}
+
+	if repo.Spec.SyncInterval != nil {
+		return requeue.After(ctx, err, repo.Spec.SyncInterval.Duration)
+	} else {
+		return requeue.Always(ctx, err)
+	}
+}
+
+// requestPackageInfoSync propagates the sync request of repo to all PackageInfos from this repository and removes
+// the annotation from repo afterwards.
+func (r *PackageRepositoryReconciler) requestPackageInfoSync(
+	ctx context.Context,
+	repo *packagesv1alpha1.PackageRepository,
+) error {
+	var packageInfos packagesv1alpha1.PackageInfoList
+	if err := r.List(ctx, &packageInfos); err != nil {
+		return err
+	}
+	requested := repo.Annotations[packagesv1alpha1.SyncRequestedAnnotation]
+	for i := range packageInfos.Items {
+		pi := &packageInfos.Items[i]
+		if pi.Spec.RepositoryName != repo.Name && (pi.Spec.RepositoryName != "" || !repo.IsDefaultRepository()) {
+			continue
+		}
+		patch := client.MergeFrom(pi.DeepCopy())
+		if pi.Annotations == nil {
+			pi.Annotations = make(map[string]string)
+		}
+		pi.Annotations[packagesv1alpha1.SyncRequestedAnnotation] = requested
+		if err := r.Patch(ctx, pi, patch); err != nil {
+			return fmt.Errorf("could not request sync of PackageInfo %v: %w", pi.Name, err)
+		}
+	}
+	patch := client.MergeFrom(repo.DeepCopy())
+	delete(repo.Annotations, packagesv1alpha1.SyncRequestedAnnotation)
+	return r.Patch(ctx, repo, patch)
 }
 
 // SetupWithManager sets up the controller with the Manager.
@@ -23,6 +23,15 @@ func Always(ctx context.Context, err error) (ctrl.Result, error) {
 	return requeueAfter(RequeueDuration), nil
 }
 
+// After is like Always, but requeues after the given duration instead of the default RequeueDuration.
+func After(ctx context.Context, err error, duration time.Duration) (ctrl.Result, error) {
+	if result, err := Always(ctx, err); err != nil {
+		return result, err
+	} else {
+		return requeueAfter(duration), nil
+	}
+}
+
 func OnError(ctx context.Context, err error) (ctrl.Result, error) {
 	log := log.FromContext(ctx)
 	if err != nil {
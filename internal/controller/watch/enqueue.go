@@ -2,6 +2,7 @@ package watch
 
 import (
 	"context"
+	"slices"
 
 	ownerutils "github.com/glasskube/glasskube/internal/controller/owners/utils"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -16,7 +17,16 @@ func EnqueueRequestsFromOwnedResource(
 	targetLister PackageLister,
 	ownedResourcesGetter ownedMapperFunc,
 ) handler.EventHandler {
-	return handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, obj client.Object) []reconcile.Request {
+	return handler.EnqueueRequestsFromMapFunc(MapOwnedResource(scheme, targetLister, ownedResourcesGetter))
+}
+
+// MapOwnedResource returns a handler.MapFunc that maps an object to all packages that own it.
+func MapOwnedResource(
+	scheme *runtime.Scheme,
+	targetLister PackageLister,
+	ownedResourcesGetter ownedMapperFunc,
+) handler.MapFunc {
+	return func(ctx context.Context, obj client.Object) []reconcile.Request {
 		log := ctrl.LoggerFrom(ctx)
 		objRef, err := ownerutils.ToOwnedResourceRef(scheme, obj)
 		if err != nil {
@@ -38,5 +48,21 @@ func EnqueueRequestsFromOwnedResource(
 			}
 			return res
 		}
+	}
+}
+
+// EnqueueRequestsFromMapFuncs enqueues the union of the requests returned by all mapFuncs.
+// This allows multiple mappings for the same kind, since a controller must not watch a kind more than once.
+func EnqueueRequestsFromMapFuncs(mapFuncs ...handler.MapFunc) handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, obj client.Object) []reconcile.Request {
+		var res []reconcile.Request
+		for _, mapFunc := range mapFuncs {
+			for _, req := range mapFunc(ctx, obj) {
+				if !slices.Contains(res, req) {
+					res = append(res, req)
+				}
+			}
+		}
+		return res
 	})
 }
@@ -0,0 +1,47 @@
+package watch
+
+import (
+	"context"
+
+	"github.com/glasskube/glasskube/api/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var _ = Describe("EnqueueRequestsFromMapFuncs", func() {
+	It("should enqueue the union of all requests", func(ctx context.Context) {
+		request := func(name string) reconcile.Request {
+			return reconcile.Request{NamespacedName: types.NamespacedName{Name: name}}
+		}
+		mapTo := func(names ...string) func(context.Context, client.Object) []reconcile.Request {
+			return func(context.Context, client.Object) []reconcile.Request {
+				var result []reconcile.Request
+				for _, name := range names {
+					result = append(result, request(name))
+				}
+				return result
+			}
+		}
+		queue := workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]())
+		DeferCleanup(queue.ShutDown)
+
+		EnqueueRequestsFromMapFuncs(mapTo("a", "b"), mapTo(), mapTo("b", "c")).
+			Create(ctx, event.CreateEvent{Object: &v1alpha1.ClusterPackage{ObjectMeta: metav1.ObjectMeta{Name: "x"}}},
+				queue)
+
+		Expect(queue.Len()).To(Equal(3))
+		var requests []reconcile.Request
+		for queue.Len() > 0 {
+			item, _ := queue.Get()
+			requests = append(requests, item)
+			queue.Done(item)
+		}
+		Expect(requests).To(ConsistOf(request("a"), request("b"), request("c")))
+	})
+})
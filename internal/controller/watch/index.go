@@ -0,0 +1,84 @@
+package watch
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/glasskube/glasskube/api/v1alpha1"
+	"github.com/glasskube/glasskube/internal/controller/ctrlpkg"
+	"github.com/glasskube/glasskube/internal/manifestvalues"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// ValueReferenceIndexKey is the name of the field index that contains a key for every object referenced by the
+// values of a package (see ValueReferenceKey).
+const ValueReferenceIndexKey = ".spec.values.valueFrom"
+
+var (
+	ConfigMapGroupKind      = corev1.SchemeGroupVersion.WithKind("ConfigMap").GroupKind()
+	SecretGroupKind         = corev1.SchemeGroupVersion.WithKind("Secret").GroupKind()
+	ClusterPackageGroupKind = v1alpha1.GroupVersion.WithKind("ClusterPackage").GroupKind()
+)
+
+// ValueReferenceKey returns the index key of the referenced object with the given kind, namespace and name.
+func ValueReferenceKey(gk schema.GroupKind, namespace, name string) string {
+	return fmt.Sprintf("%v/%v/%v", gk, namespace, name)
+}
+
+// IndexValueReferences is a client.IndexerFunc that returns the keys of all objects referenced by the values of a
+// package.
+func IndexValueReferences(obj client.Object) []string {
+	if pkg, ok := obj.(ctrlpkg.Package); ok {
+		return valueReferenceKeys(manifestvalues.WithDefaultNamespace(pkg.GetSpec().Values, pkg.GetNamespace()))
+	}
+	return nil
+}
+
+func valueReferenceKeys(values map[string]v1alpha1.ValueConfiguration) []string {
+	var keys []string
+	for _, value := range values {
+		if ref := value.ValueFrom; ref == nil {
+			continue
+		} else if ref.ConfigMapRef != nil {
+			keys = append(keys, ValueReferenceKey(ConfigMapGroupKind, ref.ConfigMapRef.Namespace, ref.ConfigMapRef.Name))
+		} else if ref.SecretRef != nil {
+			keys = append(keys, ValueReferenceKey(SecretGroupKind, ref.SecretRef.Namespace, ref.SecretRef.Name))
+		} else if ref.PackageRef != nil {
+			keys = append(keys, ValueReferenceKey(ClusterPackageGroupKind, "", ref.PackageRef.Name))
+		} else if ref.ResourceRef != nil {
+			if gvk, err := resourceRefGVK(*ref.ResourceRef); err == nil {
+				keys = append(keys, ValueReferenceKey(gvk.GroupKind(), ref.ResourceRef.Namespace, ref.ResourceRef.Name))
+			}
+		}
+	}
+	return keys
+}
+
+// EnqueueRequestsFromValueReference enqueues all packages that have a value referencing the object of the event.
+// The GroupKind must be passed explicitly, because objects from the cache usually don't have their TypeMeta set.
+func EnqueueRequestsFromValueReference(targetLister PackageLister, gk schema.GroupKind) handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(MapValueReference(targetLister, gk))
+}
+
+// MapValueReference returns a handler.MapFunc that maps an object of the given GroupKind to all packages that have a
+// value referencing it.
+func MapValueReference(targetLister PackageLister, gk schema.GroupKind) handler.MapFunc {
+	return func(ctx context.Context, obj client.Object) []reconcile.Request {
+		key := ValueReferenceKey(gk, obj.GetNamespace(), obj.GetName())
+		if pkgs, err := targetLister.ListPackages(ctx, client.MatchingFields{ValueReferenceIndexKey: key}); err != nil {
+			ctrl.LoggerFrom(ctx).Error(err, "could not list packages in event handler")
+			return nil
+		} else {
+			res := make([]reconcile.Request, len(pkgs))
+			for i, pkg := range pkgs {
+				res[i] = reconcile.Request{NamespacedName: client.ObjectKeyFromObject(pkg)}
+			}
+			return res
+		}
+	}
+}
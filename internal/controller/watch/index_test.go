@@ -0,0 +1,98 @@
+package watch
+
+import (
+	"context"
+
+	"github.com/glasskube/glasskube/api/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var serviceGroupKind = schema.GroupKind{Kind: "Service"}
+
+var _ = Describe("IndexValueReferences", func() {
+	It("should use the namespace of a Package for resource references without namespace", func() {
+		pkg := &v1alpha1.Package{
+			ObjectMeta: metav1.ObjectMeta{Name: "pkg", Namespace: "pkg-ns"},
+			Spec: v1alpha1.PackageSpec{Values: map[string]v1alpha1.ValueConfiguration{
+				"a": resourceRefValue("v1", "Service", "a", ""),
+				"b": resourceRefValue("v1", "Service", "b", "other"),
+			}},
+		}
+		Expect(IndexValueReferences(pkg)).To(ConsistOf(
+			ValueReferenceKey(serviceGroupKind, "pkg-ns", "a"),
+			ValueReferenceKey(serviceGroupKind, "other", "b"),
+		))
+		Expect(pkg.Spec.Values["a"].ValueFrom.ResourceRef.Namespace).To(BeEmpty())
+	})
+
+	It("should keep resource references of a ClusterPackage without namespace", func() {
+		pkg := &v1alpha1.ClusterPackage{
+			ObjectMeta: metav1.ObjectMeta{Name: "pkg"},
+			Spec: v1alpha1.PackageSpec{Values: map[string]v1alpha1.ValueConfiguration{
+				"a": resourceRefValue("v1", "Service", "a", ""),
+			}},
+		}
+		Expect(IndexValueReferences(pkg)).To(ConsistOf(ValueReferenceKey(serviceGroupKind, "", "a")))
+	})
+})
+
+var _ = Describe("valueReferenceKeys", func() {
+	It("should return a key for every reference", func() {
+		inline := "foo"
+		keys := valueReferenceKeys(map[string]v1alpha1.ValueConfiguration{
+			"inline": {InlineValueConfiguration: v1alpha1.InlineValueConfiguration{Value: &inline}},
+			"configMap": {ValueFrom: &v1alpha1.ValueReference{
+				ConfigMapRef: &v1alpha1.ObjectKeyValueSource{Name: "cm", Namespace: "ns", Key: "key"},
+			}},
+			"secret": {ValueFrom: &v1alpha1.ValueReference{
+				SecretRef: &v1alpha1.ObjectKeyValueSource{Name: "secret", Namespace: "ns", Key: "key"},
+			}},
+			"package": {ValueFrom: &v1alpha1.ValueReference{
+				PackageRef: &v1alpha1.PackageValueSource{Name: "other", Value: "value"},
+			}},
+			"resource": resourceRefValue("apps/v1", "Deployment", "deploy", "ns"),
+			"invalid":  resourceRefValue("a/b/c", "Deployment", "deploy", "ns"),
+		})
+		Expect(keys).To(ConsistOf(
+			"ConfigMap/ns/cm",
+			"Secret/ns/secret",
+			"ClusterPackage.packages.glasskube.dev//other",
+			"Deployment.apps/ns/deploy",
+		))
+	})
+
+	It("should return no keys for values without references", func() {
+		Expect(valueReferenceKeys(nil)).To(BeEmpty())
+	})
+})
+
+var _ = Describe("MapValueReference", func() {
+	referencing := &v1alpha1.Package{
+		ObjectMeta: metav1.ObjectMeta{Name: "referencing", Namespace: "ns"},
+		Spec: v1alpha1.PackageSpec{Values: map[string]v1alpha1.ValueConfiguration{
+			"a": {ValueFrom: &v1alpha1.ValueReference{
+				ConfigMapRef: &v1alpha1.ObjectKeyValueSource{Name: "cm", Namespace: "ns", Key: "key"},
+			}},
+		}},
+	}
+	other := &v1alpha1.ClusterPackage{ObjectMeta: metav1.ObjectMeta{Name: "other"}}
+	mapFunc := MapValueReference(fakeLister{referencing, other}, ConfigMapGroupKind)
+
+	It("should map a referenced object to the referencing packages", func(ctx context.Context) {
+		cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cm", Namespace: "ns"}}
+		Expect(mapFunc(ctx, cm)).To(ConsistOf(
+			reconcile.Request{NamespacedName: types.NamespacedName{Name: "referencing", Namespace: "ns"}},
+		))
+	})
+
+	It("should not map an object that is not referenced", func(ctx context.Context) {
+		cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cm", Namespace: "other"}}
+		Expect(mapFunc(ctx, cm)).To(BeEmpty())
+	})
+})
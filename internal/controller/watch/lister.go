@@ -4,8 +4,9 @@ import (
 	"context"
 
 	"github.com/glasskube/glasskube/internal/controller/ctrlpkg"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 type PackageLister interface {
-	ListPackages(ctx context.Context) ([]ctrlpkg.Package, error)
+	ListPackages(ctx context.Context, opts ...client.ListOption) ([]ctrlpkg.Package, error)
 }
@@ -0,0 +1,80 @@
+package watch
+
+import (
+	"sync"
+
+	"github.com/glasskube/glasskube/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// ResourceRefWatcher starts watches for resources referenced by resourceRef values.
+// Because any kind of resource can be referenced, watches can not be set up with the controller, but are started
+// lazily for every new GroupVersionKind that is encountered during reconciliation.
+type ResourceRefWatcher struct {
+	controller controller.Controller
+	cache      cache.Cache
+	mapper     meta.RESTMapper
+	lister     PackageLister
+	watched    map[schema.GroupVersionKind]struct{}
+	mutex      sync.Mutex
+}
+
+func NewResourceRefWatcher(
+	controller controller.Controller,
+	cache cache.Cache,
+	mapper meta.RESTMapper,
+	lister PackageLister,
+) *ResourceRefWatcher {
+	return &ResourceRefWatcher{
+		controller: controller,
+		cache:      cache,
+		mapper:     mapper,
+		lister:     lister,
+		watched:    make(map[schema.GroupVersionKind]struct{}),
+	}
+}
+
+// Watch ensures that a watch exists for the kind of every resource referenced in values.
+func (w *ResourceRefWatcher) Watch(values map[string]v1alpha1.ValueConfiguration) error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	for _, value := range values {
+		if value.ValueFrom == nil || value.ValueFrom.ResourceRef == nil {
+			continue
+		}
+		gvk, err := resourceRefGVK(*value.ValueFrom.ResourceRef)
+		if err != nil {
+			return err
+		} else if _, ok := w.watched[gvk]; ok {
+			continue
+		}
+		// Check the mapping first, because the source would otherwise retry to start an informer for an unknown
+		// kind until the controller is stopped.
+		if _, err := w.mapper.RESTMapping(gvk.GroupKind(), gvk.Version); err != nil {
+			return err
+		}
+		obj := &unstructured.Unstructured{}
+		obj.SetGroupVersionKind(gvk)
+		if err := w.controller.Watch(
+			source.Kind[client.Object](w.cache, obj, EnqueueRequestsFromValueReference(w.lister, gvk.GroupKind())),
+		); err != nil {
+			return err
+		}
+		w.watched[gvk] = struct{}{}
+	}
+	return nil
+}
+
+func resourceRefGVK(ref v1alpha1.ResourceValueSource) (schema.GroupVersionKind, error) {
+	if gv, err := schema.ParseGroupVersion(ref.APIVersion); err != nil {
+		return schema.GroupVersionKind{}, err
+	} else {
+		return gv.WithKind(ref.Kind), nil
+	}
+}
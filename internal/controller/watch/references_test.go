@@ -0,0 +1,97 @@
+package watch
+
+import (
+	"errors"
+
+	"github.com/glasskube/glasskube/api/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+type fakeController struct {
+	controller.Controller
+	sources []source.Source
+}
+
+func (c *fakeController) Watch(src source.Source) error {
+	c.sources = append(c.sources, src)
+	return nil
+}
+
+func resourceRefValue(apiVersion, kind, name, namespace string) v1alpha1.ValueConfiguration {
+	return v1alpha1.ValueConfiguration{
+		ValueFrom: &v1alpha1.ValueReference{
+			ResourceRef: &v1alpha1.ResourceValueSource{
+				APIVersion: apiVersion, Kind: kind, Name: name, Namespace: namespace, Path: "{.metadata.name}",
+			},
+		},
+	}
+}
+
+var _ = Describe("ResourceRefWatcher", func() {
+	var ctrl *fakeController
+	var watcher *ResourceRefWatcher
+
+	BeforeEach(func() {
+		mapper := meta.NewDefaultRESTMapper(nil)
+		mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "Service"}, meta.RESTScopeNamespace)
+		mapper.Add(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}, meta.RESTScopeNamespace)
+		ctrl = &fakeController{}
+		watcher = NewResourceRefWatcher(ctrl, nil, mapper, fakeLister{})
+	})
+
+	It("should watch every referenced kind once", func() {
+		Expect(watcher.Watch(map[string]v1alpha1.ValueConfiguration{
+			"a": resourceRefValue("v1", "Service", "a", "default"),
+			"b": resourceRefValue("v1", "Service", "b", ""),
+			"c": resourceRefValue("apps/v1", "Deployment", "c", "default"),
+		})).To(Succeed())
+		Expect(ctrl.sources).To(HaveLen(2))
+
+		Expect(watcher.Watch(map[string]v1alpha1.ValueConfiguration{
+			"a": resourceRefValue("v1", "Service", "other", "other"),
+		})).To(Succeed())
+		Expect(ctrl.sources).To(HaveLen(2))
+	})
+
+	It("should ignore values without resource reference", func() {
+		value := "foo"
+		Expect(watcher.Watch(map[string]v1alpha1.ValueConfiguration{
+			"inline": {InlineValueConfiguration: v1alpha1.InlineValueConfiguration{Value: &value}},
+			"configMap": {ValueFrom: &v1alpha1.ValueReference{
+				ConfigMapRef: &v1alpha1.ObjectKeyValueSource{Name: "foo", Namespace: "default", Key: "foo"},
+			}},
+		})).To(Succeed())
+		Expect(ctrl.sources).To(BeEmpty())
+	})
+
+	It("should fail for a kind that is not known to the cluster", func() {
+		err := watcher.Watch(map[string]v1alpha1.ValueConfiguration{
+			"a": resourceRefValue("example.com/v1", "Unknown", "a", "default"),
+		})
+		var noMatchErr *meta.NoKindMatchError
+		Expect(errors.As(err, &noMatchErr)).To(BeTrue())
+		Expect(ctrl.sources).To(BeEmpty())
+	})
+
+	It("should fail for an invalid apiVersion", func() {
+		Expect(watcher.Watch(map[string]v1alpha1.ValueConfiguration{
+			"a": resourceRefValue("a/b/c", "Service", "a", "default"),
+		})).NotTo(Succeed())
+		Expect(ctrl.sources).To(BeEmpty())
+	})
+
+	It("should retry a kind after a failed watch", func() {
+		Expect(watcher.Watch(map[string]v1alpha1.ValueConfiguration{
+			"a": resourceRefValue("example.com/v1", "Unknown", "a", "default"),
+		})).NotTo(Succeed())
+		Expect(watcher.Watch(map[string]v1alpha1.ValueConfiguration{
+			"a": resourceRefValue("v1", "Service", "a", "default"),
+		})).To(Succeed())
+		Expect(ctrl.sources).To(HaveLen(1))
+	})
+})
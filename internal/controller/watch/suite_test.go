@@ -0,0 +1,37 @@
+package watch
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/glasskube/glasskube/internal/controller/ctrlpkg"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestWatch(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Watch Suite")
+}
+
+// fakeLister returns its packages. The field selector of ValueReferenceIndexKey is evaluated using
+// IndexValueReferences, all other options are ignored.
+type fakeLister []ctrlpkg.Package
+
+func (l fakeLister) ListPackages(ctx context.Context, opts ...client.ListOption) ([]ctrlpkg.Package, error) {
+	var listOpts client.ListOptions
+	listOpts.ApplyOptions(opts)
+	if listOpts.FieldSelector == nil {
+		return l, nil
+	}
+	key, _ := listOpts.FieldSelector.RequiresExactMatch(ValueReferenceIndexKey)
+	var result []ctrlpkg.Package
+	for _, pkg := range l {
+		if slices.Contains(IndexValueReferences(pkg), key) {
+			result = append(result, pkg)
+		}
+	}
+	return result, nil
+}
@@ -235,19 +235,26 @@ func (g *DependencyGraph) Validate() error {
 		pkgRef := PackageRef{Name: ref.name, Namespace: ref.namespace, PackageName: vertex.packageName}
 		for dep, edge := range vertex.edges {
 			depRef := PackageRef{Name: dep.name, Namespace: dep.namespace, PackageName: edge.vertex.packageName}
-			if edge.vertex.version == nil {
-				multierr.AppendInto(&err, ErrDependency(pkgRef, depRef, ErrNotInstalled(depRef)))
-			} else if edge.constraint != nil {
-				if err1 := isemver.ValidateVersionConstraint(edge.vertex.version, edge.constraint); err1 != nil {
-					multierr.AppendInto(&err, ErrDependency(pkgRef, depRef,
-						ErrConstraint(depRef, edge.vertex.version, edge.constraint, err1)))
-				}
+			if edgeErr := edge.validate(depRef); edgeErr != nil {
+				multierr.AppendInto(&err, ErrDependency(pkgRef, depRef, edgeErr))
 			}
 		}
 	}
 	return err
 }
 
+// validate returns an error if the dependency represented by e is not met
+func (e *edge) validate(depRef PackageRef) error {
+	if e.vertex.version == nil {
+		return ErrNotInstalled(depRef)
+	} else if e.constraint != nil {
+		if err := isemver.ValidateVersionConstraint(e.vertex.version, e.constraint); err != nil {
+			return ErrConstraint(depRef, e.vertex.version, e.constraint, err)
+		}
+	}
+	return nil
+}
+
 func (g *DependencyGraph) add(
 	ref vertexRef,
 	manifest v1alpha1.PackageManifest,
@@ -0,0 +1,259 @@
+package graph
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+type Format string
+
+const (
+	FormatDOT     Format = "dot"
+	FormatMermaid Format = "mermaid"
+	FormatTree    Format = "tree"
+)
+
+var Formats = []Format{FormatTree, FormatDOT, FormatMermaid}
+
+// Render writes the graph to w in the given format.
+// If roots are given, only these packages and their (transitive) dependencies are included.
+// Edges are annotated with their version constraint. Manually installed packages and packages with unmet
+// dependencies on them are highlighted.
+func (g *DependencyGraph) Render(w io.Writer, format Format, roots ...PackageRef) error {
+	r := newRenderer(g, roots)
+	switch format {
+	case FormatDOT:
+		return r.dot(w)
+	case FormatMermaid:
+		return r.mermaid(w)
+	case FormatTree:
+		return r.tree(w)
+	default:
+		return fmt.Errorf("unsupported format %q", format)
+	}
+}
+
+type renderer struct {
+	nodes []Node
+	edges []Edge
+	index map[vertexRef]int
+	// roots are the indices of the nodes at which the tree starts
+	roots []int
+}
+
+func newRenderer(g *DependencyGraph, roots []PackageRef) *renderer {
+	allNodes, allEdges := g.Nodes(), g.Edges()
+	adjacency := make(map[vertexRef][]Edge)
+	for _, e := range allEdges {
+		adjacency[refOf(e.From)] = append(adjacency[refOf(e.From)], e)
+	}
+
+	var included map[vertexRef]bool
+	if len(roots) > 0 {
+		included = make(map[vertexRef]bool)
+		var visit func(ref vertexRef)
+		visit = func(ref vertexRef) {
+			if !included[ref] {
+				included[ref] = true
+				for _, e := range adjacency[ref] {
+					visit(refOf(e.To))
+				}
+			}
+		}
+		for _, root := range roots {
+			visit(refOf(root))
+		}
+	}
+
+	r := &renderer{index: make(map[vertexRef]int)}
+	hasDependants := make(map[vertexRef]bool)
+	for _, n := range allNodes {
+		if included == nil || included[refOf(n.PackageRef)] {
+			r.index[refOf(n.PackageRef)] = len(r.nodes)
+			r.nodes = append(r.nodes, n)
+		}
+	}
+	for _, e := range allEdges {
+		if _, ok := r.index[refOf(e.From)]; ok {
+			r.edges = append(r.edges, e)
+			hasDependants[refOf(e.To)] = true
+		}
+	}
+
+	if len(roots) > 0 {
+		for _, root := range roots {
+			if i, ok := r.index[refOf(root)]; ok {
+				r.roots = append(r.roots, i)
+			}
+		}
+	} else {
+		for i, n := range r.nodes {
+			if !hasDependants[refOf(n.PackageRef)] {
+				r.roots = append(r.roots, i)
+			}
+		}
+	}
+	return r
+}
+
+func refOf(ref PackageRef) vertexRef {
+	return vertexRef{name: ref.Name, namespace: ref.Namespace}
+}
+
+func (r *renderer) dot(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("digraph dependencies {\n")
+	b.WriteString("  node [shape=box];\n")
+	for i, n := range r.nodes {
+		attrs := []string{"label=" + strconv.Quote(nodeLabel(n))}
+		if n.Manual {
+			attrs = append(attrs, "style=bold")
+		}
+		if n.Conflict {
+			attrs = append(attrs, "color=red", "fontcolor=red")
+		}
+		fmt.Fprintf(&b, "  n%v [%v];\n", i, strings.Join(attrs, ", "))
+	}
+	for _, e := range r.edges {
+		var attrs []string
+		if e.Constraint != nil {
+			attrs = append(attrs, "label="+strconv.Quote(e.Constraint.String()))
+		}
+		if e.Err != nil {
+			attrs = append(attrs, "color=red", "fontcolor=red")
+		}
+		fmt.Fprintf(&b, "  n%v -> n%v", r.index[refOf(e.From)], r.index[refOf(e.To)])
+		if len(attrs) > 0 {
+			fmt.Fprintf(&b, " [%v]", strings.Join(attrs, ", "))
+		}
+		b.WriteString(";\n")
+	}
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func (r *renderer) mermaid(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("graph TD\n")
+	var manual, conflict []string
+	for i, n := range r.nodes {
+		fmt.Fprintf(&b, "  n%v[\"%v\"]\n", i, mermaidEscape(nodeLabel(n)))
+		if n.Manual {
+			manual = append(manual, fmt.Sprintf("n%v", i))
+		}
+		if n.Conflict {
+			conflict = append(conflict, fmt.Sprintf("n%v", i))
+		}
+	}
+	var violated []string
+	for i, e := range r.edges {
+		fmt.Fprintf(&b, "  n%v -->", r.index[refOf(e.From)])
+		if e.Constraint != nil {
+			fmt.Fprintf(&b, "|\"%v\"|", mermaidEscape(e.Constraint.String()))
+		}
+		fmt.Fprintf(&b, " n%v\n", r.index[refOf(e.To)])
+		if e.Err != nil {
+			violated = append(violated, strconv.Itoa(i))
+		}
+	}
+	if len(manual) > 0 {
+		b.WriteString("  classDef manual stroke-width:3px\n")
+		fmt.Fprintf(&b, "  class %v manual\n", strings.Join(manual, ","))
+	}
+	if len(conflict) > 0 {
+		b.WriteString("  classDef conflict stroke:#f00,color:#f00\n")
+		fmt.Fprintf(&b, "  class %v conflict\n", strings.Join(conflict, ","))
+	}
+	if len(violated) > 0 {
+		fmt.Fprintf(&b, "  linkStyle %v stroke:#f00\n", strings.Join(violated, ","))
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// tree prints every root followed by its dependencies. A dependency that is already on the path from the root is
+// marked as cycle and not expanded again. Installed packages that are not reachable from any root (because they are
+// part of a cycle) are printed as additional roots.
+func (r *renderer) tree(w io.Writer) error {
+	var b strings.Builder
+	visited := make([]bool, len(r.nodes))
+	onPath := make([]bool, len(r.nodes))
+	var printChildren func(i int, prefix string)
+	printChildren = func(i int, prefix string) {
+		visited[i] = true
+		onPath[i] = true
+		children := r.edgesFrom(r.nodes[i].PackageRef)
+		for j, e := range children {
+			branch, indent := "├── ", "│   "
+			if j == len(children)-1 {
+				branch, indent = "└── ", "    "
+			}
+			child := r.index[refOf(e.To)]
+			b.WriteString(prefix + branch + nodeLabel(r.nodes[child]))
+			if e.Constraint != nil {
+				fmt.Fprintf(&b, " (requires %v)", e.Constraint)
+			}
+			b.WriteString(nodeMarkers(r.nodes[child]))
+			if e.Err != nil {
+				fmt.Fprintf(&b, " [conflict: %v]", e.Err)
+			}
+			if onPath[child] {
+				b.WriteString(" [cycle]\n")
+				continue
+			}
+			b.WriteString("\n")
+			printChildren(child, prefix+indent)
+		}
+		onPath[i] = false
+	}
+	printRoot := func(i int) {
+		b.WriteString(nodeLabel(r.nodes[i]) + nodeMarkers(r.nodes[i]) + "\n")
+		printChildren(i, "")
+	}
+	for _, i := range r.roots {
+		printRoot(i)
+	}
+	for i := range r.nodes {
+		if !visited[i] && r.nodes[i].Version != nil {
+			printRoot(i)
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func (r *renderer) edgesFrom(ref PackageRef) []Edge {
+	var result []Edge
+	for _, e := range r.edges {
+		if refOf(e.From) == refOf(ref) {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+func nodeLabel(n Node) string {
+	label := n.PackageRef.String()
+	if n.PackageName != "" && n.PackageName != n.Name {
+		label += " (" + n.PackageName + ")"
+	}
+	if n.Version != nil {
+		return label + " " + n.Version.Original()
+	} else {
+		return label + " (not installed)"
+	}
+}
+
+func nodeMarkers(n Node) string {
+	if n.Manual {
+		return " [manual]"
+	}
+	return ""
+}
+
+func mermaidEscape(s string) string {
+	return strings.ReplaceAll(s, `"`, "#quot;")
+}
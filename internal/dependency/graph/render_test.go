@@ -0,0 +1,114 @@
+package graph
+
+import (
+	"strings"
+
+	"github.com/glasskube/glasskube/api/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Render", func() {
+	var graph *DependencyGraph
+
+	BeforeEach(func() {
+		graph = NewGraph()
+		fooManifest := v1alpha1.PackageManifest{Name: "foo", Dependencies: []v1alpha1.Dependency{
+			{Name: "bar", Version: ">=1.0.0"},
+			{Name: "baz", Version: "2.x.x"},
+		}}
+		Expect(graph.AddCluster(fooManifest, "v1.0.0", true)).To(Succeed())
+		Expect(graph.AddCluster(v1alpha1.PackageManifest{Name: "bar"}, "v1.2.0", false)).To(Succeed())
+		Expect(graph.AddCluster(v1alpha1.PackageManifest{Name: "baz"}, "v1.0.0", false)).To(Succeed())
+		Expect(graph.AddCluster(v1alpha1.PackageManifest{Name: "qux"}, "v3.0.0", true)).To(Succeed())
+	})
+
+	render := func(format Format, roots ...PackageRef) string {
+		var b strings.Builder
+		ExpectWithOffset(1, graph.Render(&b, format, roots...)).To(Succeed())
+		return b.String()
+	}
+
+	It("should return nodes with conflicts", func() {
+		nodes := graph.Nodes()
+		Expect(nodes).To(HaveLen(4))
+		Expect(nodes[0].Name).To(Equal("bar"))
+		Expect(nodes[0].Conflict).To(BeFalse())
+		Expect(nodes[1].Name).To(Equal("baz"))
+		Expect(nodes[1].Conflict).To(BeTrue())
+		Expect(nodes[2].Name).To(Equal("foo"))
+		Expect(nodes[2].Manual).To(BeTrue())
+	})
+
+	It("should return edges with errors", func() {
+		edges := graph.Edges()
+		Expect(edges).To(HaveLen(2))
+		Expect(edges[0].To.Name).To(Equal("bar"))
+		Expect(edges[0].Err).NotTo(HaveOccurred())
+		Expect(edges[1].To.Name).To(Equal("baz"))
+		Expect(edges[1].Err).To(BeAssignableToTypeOf(&ConstraintError{}))
+	})
+
+	It("should render a tree", func() {
+		Expect(render(FormatTree)).To(Equal(
+			"foo v1.0.0 [manual]\n" +
+				"├── bar v1.2.0 (requires >=1.0.0)\n" +
+				"└── baz v1.0.0 (requires 2.x.x) [conflict: constraint 2.x.x violated: " +
+				"1.0.0 is less than 2.x.x]\n" +
+				"qux v3.0.0 [manual]\n",
+		))
+	})
+
+	It("should render a tree for the given roots only", func() {
+		Expect(render(FormatTree, PackageRef{Name: "qux"})).To(Equal("qux v3.0.0 [manual]\n"))
+	})
+
+	It("should mark missing dependencies and cycles", func() {
+		Expect(graph.AddCluster(v1alpha1.PackageManifest{Name: "bar", Dependencies: []v1alpha1.Dependency{
+			{Name: "foo"}, {Name: "missing"},
+		}}, "v1.2.0", false)).To(Succeed())
+		out := render(FormatTree, PackageRef{Name: "foo"})
+		Expect(out).To(ContainSubstring("│   ├── foo v1.0.0 [manual] [cycle]\n"))
+		Expect(out).To(ContainSubstring("│   └── missing (not installed) [conflict: missing not installed]\n"))
+	})
+
+	It("should render DOT", func() {
+		Expect(render(FormatDOT, PackageRef{Name: "foo"})).To(Equal(
+			"digraph dependencies {\n" +
+				"  node [shape=box];\n" +
+				"  n0 [label=\"bar v1.2.0\"];\n" +
+				"  n1 [label=\"baz v1.0.0\", color=red, fontcolor=red];\n" +
+				"  n2 [label=\"foo v1.0.0\", style=bold];\n" +
+				"  n2 -> n0 [label=\">=1.0.0\"];\n" +
+				"  n2 -> n1 [label=\"2.x.x\", color=red, fontcolor=red];\n" +
+				"}\n",
+		))
+	})
+
+	It("should render Mermaid", func() {
+		Expect(render(FormatMermaid, PackageRef{Name: "foo"})).To(Equal(
+			"graph TD\n" +
+				"  n0[\"bar v1.2.0\"]\n" +
+				"  n1[\"baz v1.0.0\"]\n" +
+				"  n2[\"foo v1.0.0\"]\n" +
+				"  n2 -->|\">=1.0.0\"| n0\n" +
+				"  n2 -->|\"2.x.x\"| n1\n" +
+				"  classDef manual stroke-width:3px\n" +
+				"  class n2 manual\n" +
+				"  classDef conflict stroke:#f00,color:#f00\n" +
+				"  class n1 conflict\n" +
+				"  linkStyle 1 stroke:#f00\n",
+		))
+	})
+
+	It("should label namespaced packages", func() {
+		Expect(graph.AddNamespaced("my-app", "apps", v1alpha1.PackageManifest{Name: "app"}, "v1.0.0", true)).
+			To(Succeed())
+		Expect(render(FormatTree, PackageRef{Name: "my-app", Namespace: "apps"})).
+			To(Equal("apps/my-app (app) v1.0.0 [manual]\n"))
+	})
+
+	It("should fail for unknown formats", func() {
+		Expect(graph.Render(&strings.Builder{}, Format("svg"))).NotTo(Succeed())
+	})
+})
@@ -0,0 +1,84 @@
+package graph
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+// Node is a read-only view of a package in a DependencyGraph
+type Node struct {
+	PackageRef
+	// Version is the installed version or nil if the package is not installed
+	Version *semver.Version
+	// Manual is true if the package has been installed by a user and not as a dependency
+	Manual bool
+	// Conflict is true if at least one dependency on this package is not met
+	Conflict bool
+}
+
+// Edge is a read-only view of a dependency of an installed package in a DependencyGraph
+type Edge struct {
+	From, To   PackageRef
+	Constraint *semver.Constraints
+	// Err is the reason why the dependency is not met or nil if it is met
+	Err error
+}
+
+// Nodes returns all packages that are either installed or a dependency of an installed package, sorted by namespace
+// and name.
+func (g *DependencyGraph) Nodes() []Node {
+	nodes := make(map[vertexRef]*Node)
+	node := func(ref vertexRef, v *vertex) *Node {
+		if n, ok := nodes[ref]; ok {
+			return n
+		}
+		n := &Node{
+			PackageRef: PackageRef{Name: ref.name, Namespace: ref.namespace, PackageName: v.packageName},
+			Version:    v.version,
+			Manual:     v.manual,
+		}
+		nodes[ref] = n
+		return n
+	}
+	for ref, v := range g.vertices {
+		if v.version == nil {
+			continue
+		}
+		node(ref, v)
+		for depRef, e := range v.edges {
+			n := node(depRef, e.vertex)
+			n.Conflict = n.Conflict || e.validate(n.PackageRef) != nil
+		}
+	}
+	result := make([]Node, 0, len(nodes))
+	for _, n := range nodes {
+		result = append(result, *n)
+	}
+	slices.SortFunc(result, func(a, b Node) int { return comparePackageRef(a.PackageRef, b.PackageRef) })
+	return result
+}
+
+// Edges returns the dependencies of all installed packages, sorted by dependant and dependency.
+func (g *DependencyGraph) Edges() []Edge {
+	var result []Edge
+	for ref, v := range g.vertices {
+		if v.version == nil {
+			continue
+		}
+		from := PackageRef{Name: ref.name, Namespace: ref.namespace, PackageName: v.packageName}
+		for depRef, e := range v.edges {
+			to := PackageRef{Name: depRef.name, Namespace: depRef.namespace, PackageName: e.vertex.packageName}
+			result = append(result, Edge{From: from, To: to, Constraint: e.constraint, Err: e.validate(to)})
+		}
+	}
+	slices.SortFunc(result, func(a, b Edge) int {
+		return cmp.Or(comparePackageRef(a.From, b.From), comparePackageRef(a.To, b.To))
+	})
+	return result
+}
+
+func comparePackageRef(a, b PackageRef) int {
+	return cmp.Or(cmp.Compare(a.Namespace, b.Namespace), cmp.Compare(a.Name, b.Name))
+}
@@ -3,7 +3,6 @@ package dependency
 import (
 	"context"
 	"errors"
-	"fmt"
 	"slices"
 	"strings"
 
@@ -13,6 +12,7 @@ import (
 
 	"github.com/glasskube/glasskube/internal/controller/ctrlpkg"
 	"github.com/glasskube/glasskube/internal/names"
+	"github.com/glasskube/glasskube/internal/tracing"
 	"go.uber.org/multierr"
 
 	"github.com/glasskube/glasskube/internal/adapter"
@@ -61,15 +61,26 @@ func (dm *DependendcyManager) Validate(
 		return nil, err
 	}
 
-	requirements, err := dm.addDependencies(g, name, namespace, false)
+	requirements, explanation, err := dm.resolveDependencies(g, name, namespace)
 	if err != nil {
 		return nil, err
 	}
 	slices.SortFunc(requirements, func(a, b Requirement) int { return strings.Compare(a.Name, b.Name) })
 
 	var conflicts []Conflict
+	if explanation != nil {
+		conflicts = append(conflicts, Conflict{
+			Actual:      PackageWithVersion{Name: explanation.Package},
+			Required:    PackageWithVersion{Name: explanation.Package},
+			Cause:       explanation,
+			Explanation: explanation,
+		})
+	}
 	for _, err := range multierr.Errors(g.Validate()) {
-		if isErrNew(err, errBefore) {
+		if errNotInstalled := (&graph.NotInstalledError{}); explanation != nil && errors.As(err, &errNotInstalled) {
+			// Dependencies are missing because they could not be resolved, which is already explained.
+			continue
+		} else if isErrNew(err, errBefore) {
 			if conflict, err := errorToConflict(err); err != nil {
 				return nil, err
 			} else {
@@ -122,8 +133,41 @@ PruneLoop:
 	}, nil
 }
 
+// Resolve returns all packages that are required to install version of manifest in an empty cluster.
+// In contrast to Validate, the current cluster state is not considered, so no packages need to be installed.
+func (dm *DependendcyManager) Resolve(manifest *v1alpha1.PackageManifest, version string) ([]Requirement, error) {
+	if manifest == nil {
+		return nil, errors.New("manifest must not be nil")
+	}
+
+	g := graph.NewGraph()
+	name, namespace := manifest.Name, ""
+	if manifest.Scope.IsNamespaced() {
+		// The actual namespace is not relevant here, but namespaced packages must be added with a namespace.
+		namespace = "default"
+	}
+	if err := dm.add(g, name, namespace, *manifest, version); err != nil {
+		return nil, err
+	}
+
+	requirements, explanation, err := dm.resolveDependencies(g, name, namespace)
+	if err != nil {
+		return nil, err
+	} else if explanation != nil {
+		return nil, explanation
+	}
+	if err := g.Validate(); err != nil {
+		return nil, err
+	}
+	slices.SortFunc(requirements, func(a, b Requirement) int { return strings.Compare(a.Name, b.Name) })
+	return requirements, nil
+}
+
 // NewGraph constructs a DependencyGraph from all packages returned by clientAdapter.ListPackages
-func (dm *DependendcyManager) NewGraph(ctx context.Context) (*graph.DependencyGraph, error) {
+func (dm *DependendcyManager) NewGraph(ctx context.Context) (_ *graph.DependencyGraph, err error) {
+	ctx, span := tracing.Start(ctx, "build dependency graph")
+	defer func() { tracing.End(span, err) }()
+
 	var allPkgs []ctrlpkg.Package
 	if pkgs, err := dm.pkgClient.ListClusterPackages(ctx); err != nil {
 		return nil, err
@@ -175,6 +219,31 @@ func (dm *DependendcyManager) NewGraph(ctx context.Context) (*graph.DependencyGr
 	return g, nil
 }
 
+// NewGraphWithPackage constructs the DependencyGraph that would result from installing or updating the package with
+// the given name and namespace to version of manifest, including all dependencies that would be installed.
+func (dm *DependendcyManager) NewGraphWithPackage(
+	ctx context.Context,
+	name, namespace string,
+	manifest *v1alpha1.PackageManifest,
+	version string,
+) (*graph.DependencyGraph, error) {
+	if manifest == nil {
+		return nil, errors.New("manifest must not be nil")
+	}
+	g, err := dm.NewGraph(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := dm.add(g, name, namespace, *manifest, version); err != nil {
+		return nil, err
+	}
+	// If the dependencies can not be resolved, they are missing from the graph, which is what we want to show.
+	if _, _, err := dm.resolveDependencies(g, name, namespace); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
 func (dm *DependendcyManager) add(
 	g *graph.DependencyGraph,
 	name, namespace string,
@@ -188,44 +257,6 @@ func (dm *DependendcyManager) add(
 	}
 }
 
-// addDependencies adds the highest possible version of every uninstalled dependency and installs all transitive
-// dependencies
-func (dm *DependendcyManager) addDependencies(
-	g *graph.DependencyGraph,
-	name, namespace string,
-	transitive bool,
-) ([]Requirement, error) {
-	var allAdded []Requirement
-	for _, dep := range g.Dependencies(name, namespace) {
-		if g.Version(dep.Name, dep.Namespace) == nil {
-			if versions, err := dm.getVersions(dep.PackageName); repoerror.IsComplete(err) {
-				return nil, fmt.Errorf("failed to get version of dep package \"%v\": %w", dep.PackageName, err)
-			} else if maxVersion, err := g.Max(dep.Name, dep.Namespace, versions); err != nil {
-				// This error occurs when no suitable version exists.
-				// In this case, the dependency is not added to the graph and a validation error detects this later.
-				continue
-			} else if depManifest, err := dm.repoAdapter.GetManifest(dep.PackageName, maxVersion.Original()); repoerror.IsComplete(err) {
-				return nil, fmt.Errorf("failed to get manifest of dep package \"%v\" in version %v: %w", dep.PackageName, maxVersion.Original(), err)
-			} else if err := dm.add(g, dep.Name, dep.Namespace, *depManifest, maxVersion.Original()); err != nil {
-				return nil, err
-			} else if added, err := dm.addDependencies(g, dep.Name, dep.Namespace, true); err != nil {
-				return nil, err
-			} else {
-				req := Requirement{
-					PackageWithVersion: PackageWithVersion{Name: depManifest.Name, Version: maxVersion.Original()},
-					Transitive:         transitive,
-				}
-				if dep.Namespace != "" {
-					req.ComponentMetadata = &ComponentMetadata{Name: dep.Name, Namespace: dep.Namespace}
-				}
-				allAdded = append(allAdded, req)
-				allAdded = append(allAdded, added...)
-			}
-		}
-	}
-	return allAdded, nil
-}
-
 // errorToConflict returns a Conflict if the error is a graph.ConstraintError. Otherwise, it returns the error
 // unmodified
 func errorToConflict(err error) (*Conflict, error) {
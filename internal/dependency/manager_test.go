@@ -9,6 +9,10 @@ import (
 	"github.com/glasskube/glasskube/internal/repo/client/fake"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace/noop"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -726,4 +730,134 @@ var _ = Describe("Dependency Manager", func() {
 			})
 		})
 	})
+
+	Describe("Solver", func() {
+		It("should backtrack to an older version of a dependency", func(ctx context.Context) {
+			createClusterPackageAndInfo("E", "1.0.0", true, false)
+			createClusterPackageAndInfo("D", "1.0.0", false, false)
+			_, d2i := createClusterPackageAndInfo("D", "2.0.0", false, false)
+			d2i.Status.Manifest.Dependencies = []v1alpha1.Dependency{{Name: "E", Version: ">=2.0.0"}}
+			pi.Status.Manifest.Dependencies = []v1alpha1.Dependency{{Name: "D"}}
+			res, err := dm.Validate(ctx, p.Name, p.Namespace, pi.Status.Manifest, p.Spec.PackageInfo.Version)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(res.Status).Should(Equal(ValidationResultStatusResolvable))
+			Expect(res.Requirements).Should(Equal([]Requirement{
+				{PackageWithVersion: PackageWithVersion{Name: "D", Version: "1.0.0"}},
+			}))
+			Expect(res.Conflicts).Should(BeEmpty())
+		})
+
+		It("should resolve transitive version constraints", func(ctx context.Context) {
+			createClusterPackageAndInfo("D", "1.0.0", false, false)
+			createClusterPackageAndInfo("D", "2.0.0", false, false)
+			_, ei = createClusterPackageAndInfo("E", "1.0.0", false, false)
+			ei.Status.Manifest.Dependencies = []v1alpha1.Dependency{{Name: "D", Version: "1.x.x"}}
+			pi.Status.Manifest.Dependencies = []v1alpha1.Dependency{{Name: "E"}, {Name: "D"}}
+			res, err := dm.Validate(ctx, p.Name, p.Namespace, pi.Status.Manifest, p.Spec.PackageInfo.Version)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(res.Status).Should(Equal(ValidationResultStatusResolvable))
+			Expect(res.Requirements).Should(ConsistOf(
+				Requirement{PackageWithVersion: PackageWithVersion{Name: "E", Version: "1.0.0"}},
+				Requirement{PackageWithVersion: PackageWithVersion{Name: "D", Version: "1.0.0"}},
+			))
+		})
+
+		It("should explain unsatisfiable requirements", func(ctx context.Context) {
+			createClusterPackageAndInfo("D", "1.0.0", false, false)
+			createClusterPackageAndInfo("D", "2.0.0", false, false)
+			createClusterPackageAndInfo("F", "1.0.0", false, false)
+			_, ei = createClusterPackageAndInfo("E", "1.0.0", false, false)
+			ei.Status.Manifest.Dependencies = []v1alpha1.Dependency{{Name: "D", Version: "2.x.x"}}
+			pi.Status.Manifest.Dependencies = []v1alpha1.Dependency{{Name: "F"}, {Name: "D", Version: "1.x.x"}, {Name: "E"}}
+			res, err := dm.Validate(ctx, p.Name, p.Namespace, pi.Status.Manifest, p.Spec.PackageInfo.Version)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(res.Status).Should(Equal(ValidationResultStatusConflict))
+			Expect(res.Requirements).Should(BeEmpty())
+			Expect(res.Conflicts).Should(HaveLen(1))
+			explanation := res.Conflicts[0].Explanation
+			Expect(explanation).ShouldNot(BeNil())
+			Expect(explanation.Requirements).Should(ConsistOf(
+				ExplainedRequirement{
+					Dependant:  PackageWithVersion{Name: "P", Version: "12.2.0"},
+					Dependency: "D",
+					Constraint: "1.x.x",
+				},
+				ExplainedRequirement{
+					Dependant:  PackageWithVersion{Name: "P", Version: "12.2.0"},
+					Dependency: "E",
+				},
+				ExplainedRequirement{
+					Dependant:  PackageWithVersion{Name: "E", Version: "1.0.0"},
+					Dependency: "D",
+					Constraint: "2.x.x",
+				},
+			))
+			Expect(explanation.Reason).Should(Equal("E 1.0.0 requires D 2.x.x, but version 1.0.0 is installed"))
+			Expect(res.Conflicts[0].String()).Should(Equal(explanation.String()))
+		})
+
+		It("should explain when the step limit is exceeded", func(ctx context.Context) {
+			DeferCleanup(func(steps int) { maxSolverSteps = steps }, maxSolverSteps)
+			maxSolverSteps = 1
+			createClusterPackageAndInfo("E", "1.0.0", true, false)
+			createClusterPackageAndInfo("D", "1.0.0", false, false)
+			_, d2i := createClusterPackageAndInfo("D", "2.0.0", false, false)
+			d2i.Status.Manifest.Dependencies = []v1alpha1.Dependency{{Name: "E", Version: ">=2.0.0"}}
+			pi.Status.Manifest.Dependencies = []v1alpha1.Dependency{{Name: "D"}}
+			res, err := dm.Validate(ctx, p.Name, p.Namespace, pi.Status.Manifest, p.Spec.PackageInfo.Version)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(res.Status).Should(Equal(ValidationResultStatusConflict))
+			Expect(res.Conflicts).Should(HaveLen(1))
+			Expect(res.Conflicts[0].Explanation).Should(HaveField("Requirements", BeEmpty()))
+			Expect(res.Conflicts[0].String()).Should(Equal(
+				"cannot resolve dependencies of P: no solution was found after trying 1 package versions"))
+		})
+
+		It("should return the explanation as error of Resolve", func() {
+			createClusterPackageAndInfo("D", "1.0.0", false, false)
+			pi.Status.Manifest.Dependencies = []v1alpha1.Dependency{{Name: "D", Version: "2.x.x"}}
+			_, err := dm.Resolve(pi.Status.Manifest, p.Spec.PackageInfo.Version)
+			Expect(err).Should(BeAssignableToTypeOf(&Explanation{}))
+			Expect(err.Error()).Should(Equal(
+				"cannot satisfy P 12.2.0 requires D 2.x.x: no version of D satisfies 2.x.x (required by P 12.2.0)"))
+		})
+	})
+
+	Describe("Graph", func() {
+		It("should record a span", func() {
+			exporter := tracetest.NewInMemoryExporter()
+			otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+			DeferCleanup(func() { otel.SetTracerProvider(noop.NewTracerProvider()) })
+
+			d, di = createClusterPackageAndInfo("D", "1.1.1", true, false)
+			g, err := dm.NewGraph(context.Background())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(g).NotTo(BeNil())
+			spans := exporter.GetSpans()
+			Expect(spans).To(HaveLen(1))
+			Expect(spans[0].Name).To(Equal("build dependency graph"))
+		})
+	})
+
+	Describe("Resolution", func() {
+		It("should return all dependencies, even if they are installed", func() {
+			d, di = createClusterPackageAndInfo("D", "1.1.1", true, false)
+			e, ei = createClusterPackageAndInfo("E", "2.0.0", false, false)
+			di.Status.Manifest.Dependencies = []v1alpha1.Dependency{{Name: "E"}}
+			pi.Status.Manifest.Dependencies = []v1alpha1.Dependency{{Name: "D", Version: "1.x.x"}}
+			res, err := dm.Resolve(pi.Status.Manifest, p.Spec.PackageInfo.Version)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res).To(Equal([]Requirement{
+				{PackageWithVersion: PackageWithVersion{Name: "D", Version: "1.1.1"}},
+				{PackageWithVersion: PackageWithVersion{Name: "E", Version: "2.0.0"}, Transitive: true},
+			}))
+		})
+
+		It("should fail if a dependency can not be resolved", func() {
+			d, di = createClusterPackageAndInfo("D", "1.1.1", false, false)
+			pi.Status.Manifest.Dependencies = []v1alpha1.Dependency{{Name: "D", Version: "2.x.x"}}
+			_, err := dm.Resolve(pi.Status.Manifest, p.Spec.PackageInfo.Version)
+			Expect(err).To(HaveOccurred())
+		})
+	})
 })
@@ -0,0 +1,321 @@
+package dependency
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/glasskube/glasskube/api/v1alpha1"
+	"github.com/glasskube/glasskube/internal/dependency/graph"
+	repoerror "github.com/glasskube/glasskube/internal/repo/error"
+	isemver "github.com/glasskube/glasskube/internal/semver"
+)
+
+// maxSolverSteps is the maximum number of package versions the solver tries during a single resolution
+var maxSolverSteps = 1000
+
+// errSolverLimit is returned by solve if maxSolverSteps is exceeded
+var errSolverLimit = errors.New("dependency resolution exceeded the maximum number of steps")
+
+type edgeKey struct {
+	fromName, fromNamespace, toName, toNamespace string
+}
+
+func keyOf(e graph.Edge) edgeKey {
+	return edgeKey{e.From.Name, e.From.Namespace, e.To.Name, e.To.Namespace}
+}
+
+type assignment struct {
+	ref     graph.PackageRef
+	name    string
+	version string
+}
+
+// failure describes why the solver could not find a suitable version of a package
+type failure struct {
+	pkg    string
+	reason string
+	// branch contains the versions chosen by the solver that lead to the failure, including the failed version
+	branch []assignment
+}
+
+// solver searches for versions of all dependencies of a package that is added to a graph, such that all version
+// constraints are satisfied.
+// Dependencies are resolved one at a time, trying the available versions from highest to lowest. If a version
+// leads to a dead end, the solver backtracks and tries the next lower version. Packages that are already installed
+// are never changed.
+type solver struct {
+	dm        *DependendcyManager
+	root      graph.PackageRef
+	versions  map[string][]*semver.Version
+	manifests map[PackageWithVersion]*v1alpha1.PackageManifest
+	steps     int
+}
+
+func newSolver(dm *DependendcyManager, name, namespace string) *solver {
+	return &solver{
+		dm:        dm,
+		root:      graph.PackageRef{Name: name, Namespace: namespace},
+		versions:  make(map[string][]*semver.Version),
+		manifests: make(map[PackageWithVersion]*v1alpha1.PackageManifest),
+	}
+}
+
+// resolveDependencies adds versions of all missing (transitive) dependencies of the package with the given name and
+// namespace to g and returns them as requirements.
+// If the dependencies can not be resolved, g is not modified and an Explanation containing a minimal set of
+// unsatisfiable requirements is returned instead.
+func (dm *DependendcyManager) resolveDependencies(
+	g *graph.DependencyGraph,
+	name, namespace string,
+) ([]Requirement, *Explanation, error) {
+	s := newSolver(dm, name, namespace)
+	result, assigned, f, err := s.solve(g, nil, []graph.PackageRef{s.root})
+	if errors.Is(err, errSolverLimit) {
+		return nil, s.limitExplanation(g), nil
+	} else if err != nil {
+		return nil, nil, err
+	} else if result == nil {
+		explanation, err := s.explain(g, f)
+		return nil, explanation, err
+	}
+
+	// apply the result to g
+	direct := make(map[edgeKey]bool)
+	for _, dep := range g.Dependencies(name, namespace) {
+		direct[edgeKey{name, namespace, dep.Name, dep.Namespace}] = true
+	}
+	requirements := make([]Requirement, len(assigned))
+	for i, a := range assigned {
+		if err := dm.add(g, a.ref.Name, a.ref.Namespace, *s.manifests[PackageWithVersion{a.name, a.version}],
+			a.version); err != nil {
+			return nil, nil, err
+		}
+		requirements[i] = Requirement{
+			PackageWithVersion: PackageWithVersion{Name: a.name, Version: a.version},
+			Transitive:         !direct[edgeKey{name, namespace, a.ref.Name, a.ref.Namespace}],
+		}
+		if a.ref.Namespace != "" {
+			requirements[i].ComponentMetadata = &ComponentMetadata{Name: a.ref.Name, Namespace: a.ref.Namespace}
+		}
+	}
+	return requirements, nil, nil
+}
+
+// solve returns a graph in which all dependencies of the packages in scope are installed. Packages added by the
+// solver are added to the scope. If considered is not nil, all edges that are not in considered are disregarded.
+// If no solution exists, the returned graph is nil and the returned failure describes the first dead end.
+func (s *solver) solve(
+	g *graph.DependencyGraph,
+	considered map[edgeKey]bool,
+	scope []graph.PackageRef,
+) (*graph.DependencyGraph, []assignment, *failure, error) {
+	edges := slices.DeleteFunc(g.Edges(), func(e graph.Edge) bool { return considered != nil && !considered[keyOf(e)] })
+	open, found := nextOpen(g, edges, scope)
+	if !found {
+		return g, nil, nil, nil
+	}
+
+	var constraints []graph.Edge
+	for _, e := range edges {
+		if e.To.Name == open.Name && e.To.Namespace == open.Namespace && e.Constraint != nil {
+			constraints = append(constraints, e)
+		}
+	}
+	versions, err := s.getVersions(open.PackageName)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var firstFailure *failure
+	for _, version := range versions {
+		if !slices.ContainsFunc(constraints, func(e graph.Edge) bool {
+			return isemver.ValidateVersionConstraint(version, e.Constraint) != nil
+		}) {
+			if s.steps++; s.steps > maxSolverSteps {
+				return nil, nil, nil, errSolverLimit
+			}
+			manifest, err := s.getManifest(open.PackageName, version.Original())
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			gc := g.DeepCopy()
+			if err := s.dm.add(gc, open.Name, open.Namespace, *manifest, version.Original()); err != nil {
+				return nil, nil, nil, err
+			}
+			a := assignment{ref: open, name: manifest.Name, version: version.Original()}
+			if f := violatedDependency(gc, considered, open); f != nil {
+				if firstFailure == nil {
+					f.branch = []assignment{a}
+					firstFailure = f
+				}
+			} else if result, assigned, f, err :=
+				s.solve(gc, considered, append(slices.Clip(scope), open)); err != nil {
+				return nil, nil, nil, err
+			} else if result != nil {
+				return result, append([]assignment{a}, assigned...), nil, nil
+			} else if firstFailure == nil {
+				f.branch = append([]assignment{a}, f.branch...)
+				firstFailure = f
+			}
+		}
+	}
+
+	if firstFailure == nil {
+		firstFailure = &failure{pkg: open.PackageName, reason: noVersionReason(g, open, constraints, len(versions))}
+	}
+	return nil, nil, firstFailure, nil
+}
+
+// nextOpen returns the first dependency of a package in scope that is not installed
+func nextOpen(g *graph.DependencyGraph, edges []graph.Edge, scope []graph.PackageRef) (graph.PackageRef, bool) {
+	for _, e := range edges {
+		if g.Version(e.To.Name, e.To.Namespace) == nil && slices.ContainsFunc(scope, func(ref graph.PackageRef) bool {
+			return ref.Name == e.From.Name && ref.Namespace == e.From.Namespace
+		}) {
+			return e.To, true
+		}
+	}
+	return graph.PackageRef{}, false
+}
+
+// explain computes a minimal set of unsatisfiable requirements by removing one requirement at a time and keeping it
+// removed if the remaining requirements are still unsatisfiable.
+// The initial set contains all requirements on packages that were not installed before resolution started,
+// including the requirements of the versions the solver chose in the branch that failed.
+func (s *solver) explain(g *graph.DependencyGraph, f *failure) (*Explanation, error) {
+	branch := g.DeepCopy()
+	for _, a := range f.branch {
+		manifest := s.manifests[PackageWithVersion{a.name, a.version}]
+		if err := s.dm.add(branch, a.ref.Name, a.ref.Namespace, *manifest, a.version); err != nil {
+			return nil, err
+		}
+	}
+	var core []graph.Edge
+	for _, e := range branch.Edges() {
+		if g.Version(e.To.Name, e.To.Namespace) == nil {
+			core = append(core, e)
+		}
+	}
+	for i := 0; i < len(core); {
+		candidate := slices.Delete(slices.Clone(core), i, i+1)
+		considered := make(map[edgeKey]bool, len(candidate))
+		for _, e := range candidate {
+			considered[keyOf(e)] = true
+		}
+		s.steps = 0
+		result, _, candidateFailure, err := s.solve(g, considered, []graph.PackageRef{s.root})
+		if errors.Is(err, errSolverLimit) {
+			// The explanation is not minimal, but still correct.
+			break
+		} else if err != nil {
+			return nil, err
+		} else if result == nil {
+			core, f = candidate, candidateFailure
+		} else {
+			i++
+		}
+	}
+
+	explanation := &Explanation{Package: f.pkg, Reason: f.reason}
+	for _, e := range core {
+		r := ExplainedRequirement{
+			Dependant:  PackageWithVersion{Name: e.From.PackageName},
+			Dependency: e.To.PackageName,
+		}
+		if v := branch.Version(e.From.Name, e.From.Namespace); v != nil {
+			r.Dependant.Version = v.Original()
+		}
+		if e.Constraint != nil {
+			r.Constraint = e.Constraint.String()
+		}
+		explanation.Requirements = append(explanation.Requirements, r)
+	}
+	return explanation, nil
+}
+
+// limitExplanation returns an Explanation for a resolution that was aborted because maxSolverSteps was exceeded.
+// It does not contain any requirements, because it is unknown whether a solution exists.
+func (s *solver) limitExplanation(g *graph.DependencyGraph) *Explanation {
+	explanation := &Explanation{
+		Package: s.root.Name,
+		Reason:  fmt.Sprintf("no solution was found after trying %v package versions", maxSolverSteps),
+	}
+	for _, e := range g.Edges() {
+		if e.From.Name == s.root.Name && e.From.Namespace == s.root.Namespace {
+			explanation.Package = e.From.PackageName
+			break
+		}
+	}
+	return explanation
+}
+
+// getVersions returns all versions of a package, highest version first
+func (s *solver) getVersions(name string) ([]*semver.Version, error) {
+	if versions, ok := s.versions[name]; ok {
+		return versions, nil
+	}
+	versions, err := s.dm.getVersions(name)
+	if repoerror.IsComplete(err) {
+		return nil, fmt.Errorf("failed to get version of dep package \"%v\": %w", name, err)
+	}
+	slices.SortStableFunc(versions, func(a, b *semver.Version) int {
+		if isemver.IsVersionUpgradable(b, a) {
+			return -1
+		} else if isemver.IsVersionUpgradable(a, b) {
+			return 1
+		} else {
+			return 0
+		}
+	})
+	s.versions[name] = versions
+	return versions, nil
+}
+
+func (s *solver) getManifest(name, version string) (*v1alpha1.PackageManifest, error) {
+	key := PackageWithVersion{Name: name, Version: version}
+	if manifest, ok := s.manifests[key]; ok {
+		return manifest, nil
+	}
+	manifest, err := s.dm.repoAdapter.GetManifest(name, version)
+	if repoerror.IsComplete(err) {
+		return nil, fmt.Errorf("failed to get manifest of dep package \"%v\" in version %v: %w", name, version, err)
+	}
+	s.manifests[key] = manifest
+	return manifest, nil
+}
+
+// violatedDependency returns a failure if a dependency of ref on an installed package is violated.
+// If considered is not nil, only the edges in considered are checked.
+func violatedDependency(g *graph.DependencyGraph, considered map[edgeKey]bool, ref graph.PackageRef) *failure {
+	for _, e := range g.Edges() {
+		if e.From.Name == ref.Name && e.From.Namespace == ref.Namespace && e.Err != nil &&
+			g.Version(e.To.Name, e.To.Namespace) != nil && (considered == nil || considered[keyOf(e)]) {
+			return &failure{
+				pkg: e.To.PackageName,
+				reason: fmt.Sprintf("%v %v requires %v %v, but version %v is installed",
+					e.From.PackageName, g.Version(ref.Name, ref.Namespace).Original(), e.To.PackageName,
+					e.Constraint, g.Version(e.To.Name, e.To.Namespace).Original()),
+			}
+		}
+	}
+	return nil
+}
+
+func noVersionReason(g *graph.DependencyGraph, ref graph.PackageRef, constraints []graph.Edge, available int) string {
+	if available == 0 {
+		return fmt.Sprintf("no version of %v is available", ref.PackageName)
+	} else if len(constraints) == 0 {
+		return fmt.Sprintf("no version of %v can be installed", ref.PackageName)
+	}
+	s := make([]string, len(constraints))
+	for i, e := range constraints {
+		s[i] = e.Constraint.String()
+		if v := g.Version(e.From.Name, e.From.Namespace); v != nil {
+			s[i] += fmt.Sprintf(" (required by %v %v)", e.From.PackageName, v.Original())
+		}
+	}
+	return fmt.Sprintf("no version of %v satisfies %v", ref.PackageName, strings.Join(s, ", "))
+}
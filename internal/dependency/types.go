@@ -32,12 +32,60 @@ type Conflict struct {
 	Actual   PackageWithVersion
 	Required PackageWithVersion
 	Cause    error
+	// Explanation is set if no combination of available package versions satisfies all requirements.
+	// In this case, Actual and Required only contain the name of the package that could not be resolved.
+	Explanation *Explanation
 }
 
 func (cf Conflict) String() string {
+	if cf.Explanation != nil {
+		return cf.Explanation.String()
+	}
 	return fmt.Sprintf("%v (required: %v, actual: %v)", cf.Required.Name, cf.Required.Version, cf.Actual.Version)
 }
 
+// ExplainedRequirement is a dependency of an installed package or the package that is being installed.
+type ExplainedRequirement struct {
+	Dependant  PackageWithVersion
+	Dependency string
+	// Constraint is empty if any version of Dependency satisfies the requirement.
+	Constraint string
+}
+
+func (r ExplainedRequirement) String() string {
+	if r.Constraint == "" {
+		return fmt.Sprintf("%v %v requires %v", r.Dependant.Name, r.Dependant.Version, r.Dependency)
+	}
+	return fmt.Sprintf("%v %v requires %v %v", r.Dependant.Name, r.Dependant.Version, r.Dependency, r.Constraint)
+}
+
+// Explanation describes why dependencies can not be resolved.
+type Explanation struct {
+	// Requirements is a minimal set of requirements that can not be satisfied together: If any of them is removed,
+	// the remaining requirements can be satisfied.
+	Requirements []ExplainedRequirement
+	// Package is the name of the package that could not be resolved.
+	// If Requirements is empty, resolution was aborted and Package is the name of the package that is being installed.
+	Package string
+	// Reason describes why no suitable version of Package was found.
+	Reason string
+}
+
+func (e *Explanation) String() string {
+	if len(e.Requirements) == 0 {
+		return fmt.Sprintf("cannot resolve dependencies of %v: %v", e.Package, e.Reason)
+	}
+	s := make([]string, len(e.Requirements))
+	for i, r := range e.Requirements {
+		s[i] = r.String()
+	}
+	return fmt.Sprintf("cannot satisfy %v: %v", strings.Join(s, " and "), e.Reason)
+}
+
+func (e *Explanation) Error() string {
+	return e.String()
+}
+
 type Conflicts []Conflict
 
 func (cf Conflicts) String() string {
@@ -0,0 +1,44 @@
+package dependency
+
+import (
+	"context"
+	"errors"
+
+	"github.com/glasskube/glasskube/api/v1alpha1"
+	"github.com/glasskube/glasskube/internal/controller/ctrlpkg"
+	"github.com/glasskube/glasskube/internal/manifestvalues"
+	repoclient "github.com/glasskube/glasskube/internal/repo/client"
+)
+
+// ValidatePackage validates the spec of pkg in the same way as the validating webhook does before a package is
+// created or updated: The scope and values of pkg must be valid for the manifest of the requested version and
+// the dependencies of that version must be resolvable. Dependency conflicts are returned as part of the result.
+func (dm *DependendcyManager) ValidatePackage(
+	ctx context.Context,
+	repo repoclient.RepoClientset,
+	pkg ctrlpkg.Package,
+) (*ValidationResult, error) {
+	// We must expect that this package is not installed in this version, so the PackageInfo does not exist.
+	var manifest v1alpha1.PackageManifest
+	err := repo.ForPackage(pkg).FetchPackageManifest(
+		pkg.GetSpec().PackageInfo.Name,
+		pkg.GetSpec().PackageInfo.Version,
+		&manifest,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Scope validation
+	if manifest.Scope.IsCluster() && pkg.IsNamespaceScoped() {
+		return nil, errors.New("invalid scope, expected ClusterPackage but found Package")
+	} else if manifest.Scope.IsNamespaced() && !pkg.IsNamespaceScoped() {
+		return nil, errors.New("invalid scope, expected Package but found ClusterPackage")
+	}
+
+	if err := manifestvalues.ValidatePackage(manifest, pkg); err != nil {
+		return nil, err
+	}
+
+	return dm.Validate(ctx, pkg.GetName(), pkg.GetNamespace(), &manifest, pkg.GetSpec().PackageInfo.Version)
+}
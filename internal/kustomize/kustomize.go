@@ -7,6 +7,7 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/kustomize/api/krusty"
+	"sigs.k8s.io/kustomize/api/resmap"
 	kstypes "sigs.k8s.io/kustomize/api/types"
 	"sigs.k8s.io/kustomize/kyaml/filesys"
 	"sigs.k8s.io/yaml"
@@ -23,55 +24,90 @@ const (
 // The Kustomization may not specify any resources or generators, but thinks like namePrefix, namespace, labels should
 // all work.
 func KustomizeObjects(kustomization kstypes.Kustomization, objects []client.Object) ([]client.Object, error) {
-	if fs, err := createVirtualFilesys(kustomization, objects); err != nil {
+	fs := filesys.MakeFsInMemory()
+	if err := writeKustomization(fs, ".", kustomization, objects); err != nil {
 		return nil, err
 	} else if resMap, err := krusty.MakeKustomizer(krusty.MakeDefaultOptions()).Run(fs, "."); err != nil {
 		return nil, err
 	} else {
-		resources := resMap.Resources()
-		result := make([]client.Object, len(resources))
-		for i, res := range resMap.Resources() {
-			if data, err := res.MarshalJSON(); err != nil {
+		return toObjects(resMap)
+	}
+}
+
+// Build runs "kustomize build" for the given [kstypes.Kustomization] in dir. The objects, if any, are added as an
+// additional local resource.
+// In contrast to KustomizeObjects, the kustomization is written to dir on disk, so that its resources can refer to
+// other files and directories in dir, like a kustomization base that was fetched or cloned before.
+// Remote git resources are loaded by kustomize using the git executable, so callers should replace them with local
+// copies.
+func Build(dir string, kustomization kstypes.Kustomization, objects []client.Object) ([]client.Object, error) {
+	fs := filesys.MakeFsOnDisk()
+	if err := writeKustomization(fs, dir, kustomization, objects); err != nil {
+		return nil, err
+	} else if resMap, err := krusty.MakeKustomizer(krusty.MakeDefaultOptions()).Run(fs, dir); err != nil {
+		return nil, err
+	} else {
+		return toObjects(resMap)
+	}
+}
+
+func toObjects(resMap resmap.ResMap) ([]client.Object, error) {
+	resources := resMap.Resources()
+	result := make([]client.Object, len(resources))
+	for i, res := range resources {
+		if data, err := res.MarshalJSON(); err != nil {
+			return nil, err
+		} else {
+			result[i] = &unstructured.Unstructured{}
+			if err := json.Unmarshal(data, result[i]); err != nil {
 				return nil, err
-			} else {
-				result[i] = &unstructured.Unstructured{}
-				if err := json.Unmarshal(data, result[i]); err != nil {
-					return nil, err
-				}
 			}
 		}
-		return result, nil
 	}
+	return result, nil
 }
 
-func createVirtualFilesys(kustomization kstypes.Kustomization, objs []client.Object) (filesys.FileSystem, error) {
-	fs := filesys.MakeFsInMemory()
+func writeKustomization(
+	fs filesys.FileSystem,
+	dir string,
+	kustomization kstypes.Kustomization,
+	objs []client.Object,
+) error {
+	// The objects file is always included if the kustomization has no other resources, because kustomize can not build
+	// an empty kustomization.
+	withObjects := len(objs) > 0 || len(kustomization.Resources) == 0
+	if withObjects {
+		kustomization.Resources = append(kustomization.Resources, objectsFileName)
+	}
 
-	if f, err := fs.Create(kustomizationFileName); err != nil {
-		return nil, err
+	if f, err := fs.Create(filesys.ConfirmedDir(dir).Join(kustomizationFileName)); err != nil {
+		return err
 	} else {
 		defer func() { _ = f.Close() }()
-		kustomization.Resources = append(kustomization.Resources, objectsFileName)
 		if data, err := yaml.Marshal(kustomization); err != nil {
-			return nil, err
+			return err
 		} else if _, err := f.Write(data); err != nil {
-			return nil, err
+			return err
 		}
 	}
 
-	if f, err := fs.Create(objectsFileName); err != nil {
-		return nil, err
+	if !withObjects {
+		return nil
+	}
+
+	if f, err := fs.Create(filesys.ConfirmedDir(dir).Join(objectsFileName)); err != nil {
+		return err
 	} else {
 		defer func() { _ = f.Close() }()
 		for _, obj := range objs {
 			if data, err := yaml.Marshal(obj); err != nil {
-				return nil, err
+				return err
 			} else if _, err = fmt.Fprintln(f, "\n---"); err != nil {
-				return nil, err
+				return err
 			} else if _, err = f.Write(data); err != nil {
-				return nil, err
+				return err
 			}
 		}
 	}
-	return fs, nil
+	return nil
 }
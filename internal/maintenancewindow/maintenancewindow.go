@@ -0,0 +1,225 @@
+// Package maintenancewindow implements maintenance windows for automatic updates.
+//
+// A schedule consists of one or more windows, separated by ";". Each window has the form
+//
+//	<days> <start>-<end> [<timezone>]
+//
+// where <days> is "*" or a comma-separated list of weekdays and weekday ranges (e.g. "Mon-Fri,Sun"), <start> and <end>
+// are times of day in 24-hour format (e.g. "22:00") and <timezone> is an IANA time zone name (default "UTC").
+// A window starts on each of the given days. If end is not after start, the window ends on the following day, so
+// "Fri 22:00-02:00" is open from Friday 22:00 until Saturday 02:00 and "* 00:00-00:00" is always open.
+//
+// An empty schedule imposes no restrictions, i.e. updates may be applied at any time.
+package maintenancewindow
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	// Time zone names must be resolvable, even if no time zone database is installed (e.g. in distroless images).
+	_ "time/tzdata"
+
+	"github.com/glasskube/glasskube/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PeriodLayout is the layout used to format the bounds of a Period.
+const PeriodLayout = "Mon 02 Jan 15:04 MST"
+
+var ErrInvalid = errors.New("invalid maintenance window")
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// Window is a recurring time range on some days of the week.
+type Window struct {
+	// Days contains true for every weekday on which the window starts, indexed by time.Weekday.
+	Days                [7]bool
+	StartHour, StartMin int
+	EndHour, EndMin     int
+	Location            *time.Location
+	source              string
+}
+
+// Schedule is a list of maintenance windows.
+type Schedule []Window
+
+// Period is a single occurrence of a Window.
+type Period struct {
+	Start, End time.Time
+}
+
+func (p Period) String() string {
+	return fmt.Sprintf("%v – %v", p.Start.Format(PeriodLayout), p.End.Format(PeriodLayout))
+}
+
+// Parse parses a schedule. An empty string results in an empty schedule.
+func Parse(s string) (Schedule, error) {
+	var schedule Schedule
+	for _, part := range strings.Split(s, ";") {
+		if part = strings.TrimSpace(part); part == "" {
+			continue
+		}
+		if window, err := parseWindow(part); err != nil {
+			return nil, fmt.Errorf("%w %q: %w", ErrInvalid, part, err)
+		} else {
+			schedule = append(schedule, *window)
+		}
+	}
+	return schedule, nil
+}
+
+// ForPackage returns the schedule of pkg. The auto-update window annotation of pkg takes precedence over
+// defaultSchedule, so an empty annotation lifts the restrictions of defaultSchedule for pkg.
+func ForPackage(pkg metav1.Object, defaultSchedule Schedule) (Schedule, error) {
+	if value, ok := pkg.GetAnnotations()[v1alpha1.AnnotationAutoUpdateWindow]; ok {
+		return Parse(value)
+	}
+	return defaultSchedule, nil
+}
+
+func (s Schedule) String() string {
+	sources := make([]string, len(s))
+	for i, window := range s {
+		sources[i] = window.source
+	}
+	return strings.Join(sources, "; ")
+}
+
+// IsOpen returns true if t lies within at least one window of s or if s is empty.
+func (s Schedule) IsOpen(t time.Time) bool {
+	if len(s) == 0 {
+		return true
+	}
+	period, ok := s.Next(t)
+	return ok && !period.Start.After(t)
+}
+
+// Next returns the period that is open at t or, if there is none, the next period that starts after t.
+// If s is empty, ok is false.
+func (s Schedule) Next(t time.Time) (next Period, ok bool) {
+	for _, window := range s {
+		local := t.In(window.Location)
+		// Start one day before t, because a window that started on the previous day may still be open.
+		for offset := -1; offset <= 7; offset++ {
+			day := time.Date(local.Year(), local.Month(), local.Day()+offset, 0, 0, 0, 0, window.Location)
+			if !window.Days[day.Weekday()] {
+				continue
+			}
+			period := window.periodOn(day)
+			if period.End.After(t) {
+				if !ok || period.Start.Before(next.Start) {
+					next, ok = period, true
+				}
+				break
+			}
+		}
+	}
+	return
+}
+
+// Describe returns a short description of the period of s that is open at t or starts next, e.g.
+// "next window Sat 08 Jun 02:00 UTC". If s is empty, it returns an empty string.
+func (s Schedule) Describe(t time.Time) string {
+	if period, ok := s.Next(t); !ok {
+		return ""
+	} else if period.Start.After(t) {
+		return "next window " + period.Start.Format(PeriodLayout)
+	} else {
+		return "window open until " + period.End.Format(PeriodLayout)
+	}
+}
+
+func (w Window) periodOn(day time.Time) Period {
+	start := time.Date(day.Year(), day.Month(), day.Day(), w.StartHour, w.StartMin, 0, 0, w.Location)
+	endDay := day.Day()
+	if w.EndHour*60+w.EndMin <= w.StartHour*60+w.StartMin {
+		endDay++
+	}
+	end := time.Date(day.Year(), day.Month(), endDay, w.EndHour, w.EndMin, 0, 0, w.Location)
+	return Period{Start: start, End: end}
+}
+
+func parseWindow(s string) (*Window, error) {
+	fields := strings.Fields(s)
+	if len(fields) < 2 || len(fields) > 3 {
+		return nil, errors.New("expected \"<days> <start>-<end> [<timezone>]\"")
+	}
+	window := Window{Location: time.UTC, source: strings.Join(fields, " ")}
+	if err := parseDays(fields[0], &window.Days); err != nil {
+		return nil, err
+	}
+	if start, end, ok := strings.Cut(fields[1], "-"); !ok {
+		return nil, fmt.Errorf("invalid time range %q", fields[1])
+	} else if h, m, err := parseTime(start, false); err != nil {
+		return nil, err
+	} else if eh, em, err := parseTime(end, true); err != nil {
+		return nil, err
+	} else {
+		window.StartHour, window.StartMin, window.EndHour, window.EndMin = h, m, eh, em
+	}
+	if len(fields) == 3 {
+		if loc, err := time.LoadLocation(fields[2]); err != nil {
+			return nil, err
+		} else {
+			window.Location = loc
+		}
+	}
+	return &window, nil
+}
+
+func parseDays(s string, days *[7]bool) error {
+	if s == "*" {
+		for i := range days {
+			days[i] = true
+		}
+		return nil
+	}
+	for _, part := range strings.Split(s, ",") {
+		from, to, isRange := strings.Cut(part, "-")
+		fromDay, ok := weekdays[strings.ToLower(from)]
+		if !ok {
+			return fmt.Errorf("invalid weekday %q", from)
+		}
+		toDay := fromDay
+		if isRange {
+			if toDay, ok = weekdays[strings.ToLower(to)]; !ok {
+				return fmt.Errorf("invalid weekday %q", to)
+			}
+		}
+		// Ranges may wrap around the end of the week, e.g. "Sat-Mon".
+		for day := fromDay; ; day = (day + 1) % 7 {
+			days[day] = true
+			if day == toDay {
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// parseTime parses a time of day in the format "HH:MM". If isEnd is true, "24:00" is also accepted.
+func parseTime(s string, isEnd bool) (int, int, error) {
+	hourStr, minStr, ok := strings.Cut(s, ":")
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid time %q", s)
+	}
+	hour, err := strconv.Atoi(hourStr)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid time %q", s)
+	}
+	minute, err := strconv.Atoi(minStr)
+	if err != nil || minute < 0 || minute > 59 || hour < 0 || hour > 24 || (hour == 24 && (!isEnd || minute != 0)) {
+		return 0, 0, fmt.Errorf("invalid time %q", s)
+	}
+	return hour, minute, nil
+}
@@ -0,0 +1,129 @@
+package maintenancewindow
+
+import (
+	"time"
+
+	"github.com/glasskube/glasskube/api/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// 2024-06-07 is a Friday.
+func at(day, hour, minute int, loc *time.Location) time.Time {
+	return time.Date(2024, time.June, day, hour, minute, 0, 0, loc)
+}
+
+var _ = Describe("Parse", func() {
+	DescribeTable("valid schedules",
+		func(s string, expected string) {
+			schedule, err := Parse(s)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(schedule.String()).To(Equal(expected))
+		},
+		Entry("empty", "", ""),
+		Entry("single window", "Mon-Fri 22:00-04:00", "Mon-Fri 22:00-04:00"),
+		Entry("time zone", "sat,sun 02:00-06:00 Europe/Vienna", "sat,sun 02:00-06:00 Europe/Vienna"),
+		Entry("multiple windows", " * 00:00-24:00 ;Sat 01:00-02:00; ", "* 00:00-24:00; Sat 01:00-02:00"),
+	)
+
+	DescribeTable("invalid schedules",
+		func(s string) {
+			_, err := Parse(s)
+			Expect(err).To(MatchError(ErrInvalid))
+		},
+		Entry("missing time", "Mon"),
+		Entry("invalid day", "Xyz 01:00-02:00"),
+		Entry("invalid time", "Mon 25:00-02:00"),
+		Entry("24:00 as start", "Mon 24:00-02:00"),
+		Entry("invalid range", "Mon 01:00"),
+		Entry("invalid time zone", "Mon 01:00-02:00 Nowhere/Nothing"),
+		Entry("too many fields", "Mon 01:00-02:00 UTC extra"),
+	)
+
+	It("should parse wrapping day ranges", func() {
+		schedule, err := Parse("Sat-Mon 01:00-02:00")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(schedule[0].Days).To(Equal([7]bool{true, true, false, false, false, false, true}))
+	})
+})
+
+var _ = Describe("Schedule", func() {
+	It("should always be open if empty", func() {
+		Expect(Schedule(nil).IsOpen(time.Now())).To(BeTrue())
+		_, ok := Schedule(nil).Next(time.Now())
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should be open within a window", func() {
+		schedule, _ := Parse("Fri 10:00-12:00")
+		Expect(schedule.IsOpen(at(7, 9, 59, time.UTC))).To(BeFalse())
+		Expect(schedule.IsOpen(at(7, 10, 0, time.UTC))).To(BeTrue())
+		Expect(schedule.IsOpen(at(7, 11, 59, time.UTC))).To(BeTrue())
+		Expect(schedule.IsOpen(at(7, 12, 0, time.UTC))).To(BeFalse())
+		Expect(schedule.IsOpen(at(8, 11, 0, time.UTC))).To(BeFalse())
+	})
+
+	It("should extend windows past midnight", func() {
+		schedule, _ := Parse("Fri 22:00-02:00")
+		Expect(schedule.IsOpen(at(8, 1, 0, time.UTC))).To(BeTrue())
+		Expect(schedule.IsOpen(at(7, 1, 0, time.UTC))).To(BeFalse())
+		period, ok := schedule.Next(at(8, 1, 0, time.UTC))
+		Expect(ok).To(BeTrue())
+		Expect(period.Start).To(Equal(at(7, 22, 0, time.UTC)))
+		Expect(period.End).To(Equal(at(8, 2, 0, time.UTC)))
+	})
+
+	It("should return the earliest next window", func() {
+		schedule, _ := Parse("Mon 01:00-02:00; Sun 03:00-04:00")
+		period, ok := schedule.Next(at(7, 12, 0, time.UTC))
+		Expect(ok).To(BeTrue())
+		Expect(period.Start).To(Equal(at(9, 3, 0, time.UTC)))
+		Expect(schedule.IsOpen(at(7, 12, 0, time.UTC))).To(BeFalse())
+	})
+
+	It("should find the window a week later", func() {
+		schedule, _ := Parse("Fri 10:00-11:00")
+		period, ok := schedule.Next(at(7, 11, 0, time.UTC))
+		Expect(ok).To(BeTrue())
+		Expect(period.Start).To(Equal(at(14, 10, 0, time.UTC)))
+	})
+
+	It("should describe the next period", func() {
+		schedule, _ := Parse("Fri 10:00-12:00")
+		Expect(schedule.Describe(at(7, 9, 0, time.UTC))).To(Equal("next window Fri 07 Jun 10:00 UTC"))
+		Expect(schedule.Describe(at(7, 11, 0, time.UTC))).To(Equal("window open until Fri 07 Jun 12:00 UTC"))
+		Expect(Schedule(nil).Describe(at(7, 11, 0, time.UTC))).To(BeEmpty())
+	})
+
+	It("should respect the time zone", func() {
+		vienna, err := time.LoadLocation("Europe/Vienna")
+		Expect(err).NotTo(HaveOccurred())
+		schedule, _ := Parse("Fri 10:00-11:00 Europe/Vienna")
+		// 10:30 in Vienna is 08:30 UTC in summer
+		Expect(schedule.IsOpen(at(7, 8, 30, time.UTC))).To(BeTrue())
+		Expect(schedule.IsOpen(at(7, 10, 30, time.UTC))).To(BeFalse())
+		period, _ := schedule.Next(at(7, 0, 0, time.UTC))
+		Expect(period.Start.Equal(at(7, 10, 0, vienna))).To(BeTrue())
+	})
+})
+
+var _ = Describe("ForPackage", func() {
+	defaultSchedule, _ := Parse("Sun 01:00-02:00")
+
+	It("should return the default schedule without annotation", func() {
+		Expect(ForPackage(&metav1.ObjectMeta{}, defaultSchedule)).To(Equal(defaultSchedule))
+	})
+
+	It("should prefer the package annotation", func() {
+		pkg := &metav1.ObjectMeta{Annotations: map[string]string{v1alpha1.AnnotationAutoUpdateWindow: "Mon 01:00-02:00"}}
+		schedule, err := ForPackage(pkg, defaultSchedule)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(schedule.String()).To(Equal("Mon 01:00-02:00"))
+	})
+
+	It("should lift the default schedule if the annotation is empty", func() {
+		pkg := &metav1.ObjectMeta{Annotations: map[string]string{v1alpha1.AnnotationAutoUpdateWindow: ""}}
+		Expect(ForPackage(pkg, defaultSchedule)).To(BeEmpty())
+	})
+})
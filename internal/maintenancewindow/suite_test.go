@@ -0,0 +1,13 @@
+package maintenancewindow
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestMaintenanceWindow(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "MaintenanceWindow Suite")
+}
@@ -0,0 +1,183 @@
+// Package drift compares the live state of resources with their desired state.
+//
+// To tell drift apart from intended changes (e.g. a new package version), the hash of the desired state of every
+// resource is stored in an annotation when the resource is applied. If the hash of the current desired state matches
+// the annotation of the live resource, any difference between the two must have been introduced outside of Glasskube.
+package drift
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/glasskube/glasskube/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	// MaxFields is the maximum number of fields that are recorded for a single resource.
+	MaxFields = 20
+	// maxValueLength is the maximum length of a recorded value.
+	maxValueLength = 80
+	redacted       = "<redacted>"
+)
+
+var (
+	secretGroupKind = schema.GroupKind{Kind: "Secret"}
+	simpleKeyRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	// ignoredMetadataFields are set by the API server and differ between a dry-run response and the live object.
+	ignoredMetadataFields = []string{"managedFields", "resourceVersion", "generation", "uid", "creationTimestamp"}
+)
+
+// Hash returns the hash of the desired state of obj, ignoring the desired state hash annotation itself.
+func Hash(obj *unstructured.Unstructured) (string, error) {
+	objCopy := obj.DeepCopy()
+	if annotations := objCopy.GetAnnotations(); annotations != nil {
+		delete(annotations, v1alpha1.AnnotationDesiredStateHash)
+		if len(annotations) == 0 {
+			annotations = nil
+		}
+		objCopy.SetAnnotations(annotations)
+	}
+	h := sha256.New()
+	if err := json.NewEncoder(h).Encode(objCopy.Object); err != nil {
+		return "", fmt.Errorf("failed to encode object: %w", err)
+	} else {
+		return hex.EncodeToString(h.Sum(nil)), nil
+	}
+}
+
+// SetHash sets the desired state hash annotation of obj.
+func SetHash(obj *unstructured.Unstructured, hash string) {
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string, 1)
+	}
+	annotations[v1alpha1.AnnotationDesiredStateHash] = hash
+	obj.SetAnnotations(annotations)
+}
+
+// GetHash returns the desired state hash annotation of obj.
+func GetHash(obj *unstructured.Unstructured) string {
+	return obj.GetAnnotations()[v1alpha1.AnnotationDesiredStateHash]
+}
+
+// Compare returns all fields that differ between desired and live, ordered by path.
+// The status and all metadata fields that are maintained by the API server are ignored. Values are not recorded for
+// Secrets. At most MaxFields fields are returned.
+func Compare(desired, live *unstructured.Unstructured) []v1alpha1.FieldDrift {
+	redact := desired.GroupVersionKind().GroupKind() == secretGroupKind
+	var result []v1alpha1.FieldDrift
+	compareValue("", prune(desired.Object), prune(live.Object), redact, &result)
+	if len(result) > MaxFields {
+		result = result[:MaxFields]
+	}
+	return result
+}
+
+// Message returns a human-readable summary of the given drift, suitable for a condition message.
+func Message(resources []v1alpha1.ResourceDrift) string {
+	descriptions := make([]string, len(resources))
+	for i, resource := range resources {
+		name := resource.Name
+		if resource.Namespace != "" {
+			name = resource.Namespace + "/" + name
+		}
+		if resource.Missing {
+			descriptions[i] = fmt.Sprintf("%v %v (deleted)", resource.Kind, name)
+		} else {
+			paths := make([]string, len(resource.Fields))
+			for j, field := range resource.Fields {
+				paths[j] = field.Path
+			}
+			descriptions[i] = fmt.Sprintf("%v %v (%v)", resource.Kind, name, strings.Join(paths, ", "))
+		}
+	}
+	return fmt.Sprintf("%v resources drifted: %v", len(resources), strings.Join(descriptions, ", "))
+}
+
+func prune(obj map[string]any) map[string]any {
+	result := make(map[string]any, len(obj))
+	for key, value := range obj {
+		if key != "status" {
+			result[key] = value
+		}
+	}
+	if metadata, ok := result["metadata"].(map[string]any); ok {
+		prunedMetadata := make(map[string]any, len(metadata))
+		for key, value := range metadata {
+			if !slices.Contains(ignoredMetadataFields, key) {
+				prunedMetadata[key] = value
+			}
+		}
+		result["metadata"] = prunedMetadata
+	}
+	return result
+}
+
+func compareValue(path string, desired, live any, redact bool, result *[]v1alpha1.FieldDrift) {
+	if desiredMap, ok := desired.(map[string]any); ok {
+		if liveMap, ok := live.(map[string]any); ok {
+			compareMaps(path, desiredMap, liveMap, redact, result)
+			return
+		}
+	} else if desiredSlice, ok := desired.([]any); ok {
+		if liveSlice, ok := live.([]any); ok && len(desiredSlice) == len(liveSlice) {
+			for i := range desiredSlice {
+				compareValue(fmt.Sprintf("%v[%v]", path, i), desiredSlice[i], liveSlice[i], redact, result)
+			}
+			return
+		}
+	}
+	if !equality.Semantic.DeepEqual(desired, live) {
+		*result = append(*result, v1alpha1.FieldDrift{
+			Path:    path,
+			Desired: formatValue(desired, redact),
+			Live:    formatValue(live, redact),
+		})
+	}
+}
+
+func compareMaps(path string, desired, live map[string]any, redact bool, result *[]v1alpha1.FieldDrift) {
+	keys := make([]string, 0, len(desired)+len(live))
+	for key := range desired {
+		keys = append(keys, key)
+	}
+	for key := range live {
+		if _, ok := desired[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		compareValue(childPath(path, key), desired[key], live[key], redact, result)
+	}
+}
+
+func childPath(path, key string) string {
+	if simpleKeyRegexp.MatchString(key) {
+		return path + "." + key
+	} else {
+		return fmt.Sprintf("%v[%q]", path, key)
+	}
+}
+
+func formatValue(value any, redact bool) string {
+	if value == nil {
+		return ""
+	} else if redact {
+		return redacted
+	} else if data, err := json.Marshal(value); err != nil {
+		return fmt.Sprint(value)
+	} else if len(data) > maxValueLength {
+		return string(data[:maxValueLength-3]) + "..."
+	} else {
+		return string(data)
+	}
+}
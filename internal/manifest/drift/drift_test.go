@@ -0,0 +1,115 @@
+package drift
+
+import (
+	"github.com/glasskube/glasskube/api/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func configMap(data map[string]any) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata": map[string]any{
+			"name":            "test",
+			"namespace":       "default",
+			"resourceVersion": "1",
+			"labels":          map[string]any{"app.kubernetes.io/name": "test"},
+		},
+		"data": data,
+	}}
+}
+
+var _ = Describe("Hash", func() {
+	It("should ignore the hash annotation", func() {
+		obj := configMap(map[string]any{"a": "1"})
+		hash, err := Hash(obj)
+		Expect(err).NotTo(HaveOccurred())
+		SetHash(obj, hash)
+		Expect(GetHash(obj)).To(Equal(hash))
+		Expect(Hash(obj)).To(Equal(hash))
+	})
+
+	It("should change if the object changes", func() {
+		hash1, _ := Hash(configMap(map[string]any{"a": "1"}))
+		hash2, _ := Hash(configMap(map[string]any{"a": "2"}))
+		Expect(hash1).NotTo(Equal(hash2))
+	})
+})
+
+var _ = Describe("Compare", func() {
+	It("should return nothing for equal objects", func() {
+		Expect(Compare(configMap(map[string]any{"a": "1"}), configMap(map[string]any{"a": "1"}))).To(BeEmpty())
+	})
+
+	It("should ignore status and server-side metadata", func() {
+		live := configMap(map[string]any{"a": "1"})
+		live.SetResourceVersion("2")
+		live.SetGeneration(3)
+		live.Object["status"] = map[string]any{"phase": "Active"}
+		Expect(Compare(configMap(map[string]any{"a": "1"}), live)).To(BeEmpty())
+	})
+
+	It("should return changed, added and removed fields", func() {
+		desired := configMap(map[string]any{"a": "1", "b": "2", "with.dot": "x"})
+		live := configMap(map[string]any{"a": "changed", "c": "3", "with.dot": "y"})
+		Expect(Compare(desired, live)).To(Equal([]v1alpha1.FieldDrift{
+			{Path: ".data.a", Desired: `"1"`, Live: `"changed"`},
+			{Path: ".data.b", Desired: `"2"`},
+			{Path: ".data.c", Live: `"3"`},
+			{Path: `.data["with.dot"]`, Desired: `"x"`, Live: `"y"`},
+		}))
+	})
+
+	It("should compare list items", func() {
+		desired := configMap(nil)
+		desired.Object["spec"] = map[string]any{"ports": []any{map[string]any{"port": int64(80)}}}
+		live := configMap(nil)
+		live.Object["spec"] = map[string]any{"ports": []any{map[string]any{"port": int64(8080)}}}
+		Expect(Compare(desired, live)).To(Equal([]v1alpha1.FieldDrift{
+			{Path: ".spec.ports[0].port", Desired: "80", Live: "8080"},
+		}))
+	})
+
+	It("should redact values of secrets", func() {
+		desired := configMap(map[string]any{"password": "c2VjcmV0"})
+		desired.SetKind("Secret")
+		live := configMap(map[string]any{"password": "b3RoZXI="})
+		live.SetKind("Secret")
+		Expect(Compare(desired, live)).To(Equal([]v1alpha1.FieldDrift{
+			{Path: ".data.password", Desired: redacted, Live: redacted},
+		}))
+	})
+
+	It("should return at most MaxFields fields", func() {
+		data := make(map[string]any, MaxFields+5)
+		for i := 0; i < MaxFields+5; i++ {
+			data[string(rune('a'+i))] = "x"
+		}
+		Expect(Compare(configMap(data), configMap(nil))).To(HaveLen(MaxFields))
+	})
+})
+
+var _ = Describe("Message", func() {
+	It("should list resources and fields", func() {
+		Expect(Message([]v1alpha1.ResourceDrift{
+			{
+				OwnedResourceRef: v1alpha1.OwnedResourceRef{
+					GroupVersionKind: metav1.GroupVersionKind{Version: "v1", Kind: "ConfigMap"},
+					Name:             "test",
+					Namespace:        "default",
+				},
+				Fields: []v1alpha1.FieldDrift{{Path: ".data.a"}, {Path: ".data.b"}},
+			},
+			{
+				OwnedResourceRef: v1alpha1.OwnedResourceRef{
+					GroupVersionKind: metav1.GroupVersionKind{Version: "v1", Kind: "Namespace"},
+					Name:             "other",
+				},
+				Missing: true,
+			},
+		})).To(Equal("2 resources drifted: ConfigMap default/test (.data.a, .data.b), Namespace other (deleted)"))
+	})
+})
@@ -18,6 +18,7 @@ import (
 	"github.com/glasskube/glasskube/internal/names"
 	repoclient "github.com/glasskube/glasskube/internal/repo/client"
 	"github.com/glasskube/glasskube/internal/resourcepatch"
+	"github.com/glasskube/glasskube/internal/tracing"
 	corev1 "k8s.io/api/core/v1"
 	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
@@ -105,7 +106,10 @@ func (a *FluxHelmAdapter) ensureNamespace(
 	ctx context.Context,
 	pkg ctrlpkg.Package,
 	manifest *packagesv1alpha1.PackageManifest,
-) (*corev1.Namespace, error) {
+) (_ *corev1.Namespace, err error) {
+	ctx, span := tracing.Start(ctx, "ensure namespace")
+	defer func() { tracing.End(span, err) }()
+
 	namespace := corev1.Namespace{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: manifest.DefaultNamespace,
@@ -131,7 +135,10 @@ func (a *FluxHelmAdapter) ensureHelmRepository(
 	ctx context.Context,
 	pkg ctrlpkg.Package,
 	manifest *packagesv1alpha1.PackageManifest,
-) (*sourcev1.HelmRepository, error) {
+) (_ *sourcev1.HelmRepository, err error) {
+	ctx, span := tracing.Start(ctx, "ensure helm repository", tracing.AttributeURL.String(manifest.Helm.RepositoryUrl))
+	defer func() { tracing.End(span, err) }()
+
 	var namespace string
 	if pkg.IsNamespaceScoped() {
 		namespace = pkg.GetNamespace()
@@ -198,7 +205,10 @@ func (a *FluxHelmAdapter) ensureHelmRelease(
 	patches resourcepatch.TargetPatches,
 	helmReleaseName, chartName, chartVersion string,
 	values *packagesv1alpha1.JSON,
-) (*helmv2.HelmRelease, error) {
+) (_ *helmv2.HelmRelease, err error) {
+	ctx, span := tracing.Start(ctx, "ensure helm release")
+	defer func() { tracing.End(span, err) }()
+
 	var namespace string
 	if pkg.IsNamespaceScoped() {
 		namespace = pkg.GetNamespace()
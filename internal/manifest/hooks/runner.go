@@ -0,0 +1,200 @@
+// Package hooks runs the lifecycle hooks of a package.
+// A hook is a plain manifest, usually containing a Job, that is applied at a specific phase of the lifecycle of a
+// package. Hooks are run by the controller around all manifest adapters, so they work the same way for plain
+// manifests, kustomizations and helm charts.
+package hooks
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/glasskube/glasskube/api/v1alpha1"
+	"github.com/glasskube/glasskube/internal/controller/ctrlpkg"
+	ownerutils "github.com/glasskube/glasskube/internal/controller/owners/utils"
+	"github.com/glasskube/glasskube/internal/manifest/plain"
+	"github.com/glasskube/glasskube/internal/manifest/result"
+	repoclient "github.com/glasskube/glasskube/internal/repo/client"
+	"github.com/glasskube/glasskube/internal/resourcepatch"
+	batchv1 "k8s.io/api/batch/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Runner applies the resources of hooks and waits for them to complete.
+// Fetching and applying hook manifests is delegated to the plain manifest adapter, so that hook resources get the same
+// namespace, owner references, value patches and prefixes as the other resources of a package.
+type Runner struct {
+	client.Client
+	adapter *plain.Adapter
+}
+
+func NewRunner() *Runner {
+	return &Runner{adapter: &plain.Adapter{}}
+}
+
+func (r *Runner) ControllerInit(
+	buildr *builder.Builder,
+	client client.Client,
+	repo repoclient.RepoClientset,
+	scheme *runtime.Scheme,
+) error {
+	if err := r.adapter.ControllerInit(buildr, client, repo, scheme); err != nil {
+		return err
+	}
+	r.Client = client
+	buildr.Owns(&batchv1.Job{}, builder.MatchEveryOwner)
+	return nil
+}
+
+// Run runs all hooks of the given phase that have not yet completed for version, one after another.
+// The state of every hook is recorded in the status of pkg and the returned bool is true if it was changed.
+// The result is only ready if all hooks of the phase have completed. A hook that has failed is not run again until
+// the version changes, so the result stays failed.
+func (r *Runner) Run(
+	ctx context.Context,
+	pkg ctrlpkg.Package,
+	pi *v1alpha1.PackageInfo,
+	phase v1alpha1.HookPhase,
+	version string,
+	patches resourcepatch.TargetPatches,
+) (*result.ReconcileResult, bool, error) {
+	var changed bool
+	var completed int
+	for _, hook := range ForPhase(pi.Status.Manifest, phase) {
+		if IsCompleted(pkg.GetStatus().Hooks, hook, version) {
+			completed++
+			continue
+		}
+		res, hookChanged, err := r.runHook(ctx, pkg, pi, hook, version, patches)
+		changed = changed || hookChanged
+		if err != nil {
+			return nil, changed, fmt.Errorf("%v hook %v: %w", phase, hook.Name, err)
+		} else if !res.IsReady() {
+			return res, changed, nil
+		}
+		completed++
+	}
+	return result.Ready(fmt.Sprintf("%v %v hooks completed", completed, phase), nil), changed, nil
+}
+
+func (r *Runner) runHook(
+	ctx context.Context,
+	pkg ctrlpkg.Package,
+	pi *v1alpha1.PackageInfo,
+	hook v1alpha1.PackageHook,
+	version string,
+	patches resourcepatch.TargetPatches,
+) (*result.ReconcileResult, bool, error) {
+	log := ctrl.LoggerFrom(ctx).WithValues("hook", hook.Name, "phase", hook.Phase)
+	status := findStatus(pkg.GetStatus().Hooks, hook)
+
+	if status != nil && status.Version == version && status.Failed {
+		// A failed hook is not run again for the same version. Otherwise, a hook whose resources are deleted on
+		// failure would be applied again and again.
+		return result.Failed(fmt.Sprintf("%v hook %v failed: %v", hook.Phase, hook.Name, status.Message), nil),
+			false, nil
+	} else if status == nil || status.Version != version {
+		// Resources of a previous run must be gone before the hook can run again, because most of them (i.e. Jobs)
+		// are immutable.
+		if status != nil {
+			if gone, err := r.deleteResources(ctx, status.Resources); err != nil {
+				return nil, false, err
+			} else if !gone {
+				return result.Waiting(fmt.Sprintf("%v hook %v: waiting for resources of previous run to be deleted",
+					hook.Phase, hook.Name), nil), false, nil
+			}
+		}
+		return r.apply(ctx, pkg, pi, hook, version, patches)
+	}
+
+	res, err := r.adapter.CheckReadiness(ctx, status.Resources, pi.Status.Manifest.ReadinessChecks)
+	if apierrors.IsNotFound(err) {
+		// The resources of an incomplete run were deleted, either manually or because of the deletion policy.
+		// In both cases, the hook is run again.
+		log.Info("resources of hook not found, running hook again")
+		return r.apply(ctx, pkg, pi, hook, version, patches)
+	} else if err != nil {
+		return nil, false, err
+	}
+
+	policy := deletionPolicy(hook)
+	if res.IsFailed() {
+		log.Info("hook failed", "message", res.Message)
+		status.Failed = true
+		status.Message = res.Message
+		if policy == v1alpha1.HookDeleteOnFailure {
+			if _, err := r.deleteResources(ctx, status.Resources); err != nil {
+				log.Error(err, "could not delete resources of failed hook")
+			}
+		}
+		return result.Failed(fmt.Sprintf("%v hook %v failed: %v", hook.Phase, hook.Name, res.Message), nil), true, nil
+	} else if res.IsWaiting() {
+		return result.Waiting(fmt.Sprintf("%v hook %v: %v", hook.Phase, hook.Name, res.Message), nil), false, nil
+	}
+
+	log.Info("hook completed")
+	status.Completed = true
+	if policy == v1alpha1.HookDeleteOnSuccess {
+		if _, err := r.deleteResources(ctx, status.Resources); err != nil {
+			log.Error(err, "could not delete resources of completed hook")
+		}
+	}
+	return result.Ready(fmt.Sprintf("%v hook %v completed", hook.Phase, hook.Name), nil), true, nil
+}
+
+func (r *Runner) apply(
+	ctx context.Context,
+	pkg ctrlpkg.Package,
+	pi *v1alpha1.PackageInfo,
+	hook v1alpha1.PackageHook,
+	version string,
+	patches resourcepatch.TargetPatches,
+) (*result.ReconcileResult, bool, error) {
+	var objectsToApply []client.Object
+	if unstructured, err := r.adapter.FetchManifestResources(pi, hook.Manifest.Url, hook.Manifest.Digest); err != nil {
+		return nil, false, err
+	} else {
+		objectsToApply = make([]client.Object, len(unstructured))
+		for i := range unstructured {
+			objectsToApply[i] = &unstructured[i]
+		}
+	}
+
+	owned, err := r.adapter.ApplyObjects(ctx, pkg, pi, hook.Manifest.DefaultNamespace, objectsToApply, patches)
+	if err != nil {
+		return nil, false, err
+	}
+	// Namespaces are excluded, because they are shared with the other resources of the package.
+	setStatus(&pkg.GetStatus().Hooks, v1alpha1.HookStatus{
+		Name:    hook.Name,
+		Phase:   hook.Phase,
+		Version: version,
+		Resources: slices.DeleteFunc(owned, func(ref v1alpha1.OwnedResourceRef) bool {
+			return ref.Group == "" && ref.Kind == "Namespace"
+		}),
+	})
+	ctrl.LoggerFrom(ctx).Info("hook started", "hook", hook.Name, "phase", hook.Phase)
+	return result.Waiting(fmt.Sprintf("%v hook %v started", hook.Phase, hook.Name), nil), true, nil
+}
+
+// deleteResources deletes all given resources and returns true if none of them exists anymore.
+func (r *Runner) deleteResources(ctx context.Context, refs []v1alpha1.OwnedResourceRef) (bool, error) {
+	gone := true
+	for _, ref := range refs {
+		obj := ownerutils.OwnedResourceRefToObject(ref)
+		// Background propagation is required, otherwise the Pods of a Job would be orphaned.
+		if err := r.Delete(ctx, obj, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil {
+			if !apierrors.IsNotFound(err) {
+				return false, fmt.Errorf("could not delete %v: %w", ref, err)
+			}
+		} else {
+			gone = false
+		}
+	}
+	return gone, nil
+}
@@ -0,0 +1,157 @@
+package hooks
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/glasskube/glasskube/api/v1alpha1"
+	"github.com/glasskube/glasskube/internal/contenttype"
+	"github.com/glasskube/glasskube/internal/manifest/plain"
+	"github.com/glasskube/glasskube/internal/manifest/result"
+	"github.com/glasskube/glasskube/internal/repo/client/fake"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+const jobManifest = `apiVersion: batch/v1
+kind: Job
+metadata:
+  name: migrate
+  namespace: default
+spec:
+  template:
+    spec:
+      restartPolicy: Never
+      containers:
+        - name: migrate
+          image: busybox
+`
+
+var _ = Describe("Runner", func() {
+	var server *httptest.Server
+	var c client.Client
+	var runner *Runner
+	var pkg *v1alpha1.ClusterPackage
+	var pi *v1alpha1.PackageInfo
+	jobKey := types.NamespacedName{Namespace: "default", Name: "migrate"}
+
+	BeforeEach(func() {
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", contenttype.MediaTypeYAML)
+			_, _ = w.Write([]byte(jobManifest))
+		}))
+		DeferCleanup(server.Close)
+
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(v1alpha1.AddToScheme(scheme)).To(Succeed())
+		c = ctrlfake.NewClientBuilder().
+			WithScheme(scheme).
+			WithInterceptorFuncs(interceptor.Funcs{
+				// The fake client does not support server-side apply, so resources are created instead.
+				Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch,
+					opts ...client.PatchOption) error {
+					if patch.Type() == types.ApplyPatchType {
+						return c.Create(ctx, obj)
+					}
+					return c.Patch(ctx, obj, patch, opts...)
+				},
+			}).
+			Build()
+		runner = &Runner{Client: c, adapter: &plain.Adapter{}}
+		Expect(runner.adapter.ControllerInit(nil, c, fake.EmptyClientset(), scheme)).To(Succeed())
+
+		pkg = &v1alpha1.ClusterPackage{
+			TypeMeta:   metav1.TypeMeta{APIVersion: v1alpha1.GroupVersion.String(), Kind: "ClusterPackage"},
+			ObjectMeta: metav1.ObjectMeta{Name: "foo", UID: "foo-uid"},
+		}
+		pi = &v1alpha1.PackageInfo{
+			Status: v1alpha1.PackageInfoStatus{
+				Version: "v1",
+				Manifest: &v1alpha1.PackageManifest{
+					Name: "foo",
+					Hooks: []v1alpha1.PackageHook{{
+						Name:           "migrate",
+						Phase:          v1alpha1.HookPreUpgrade,
+						Manifest:       v1alpha1.PlainManifest{Url: server.URL + "/migrate.yaml"},
+						DeletionPolicy: v1alpha1.HookDeleteOnFailure,
+					}},
+				},
+			},
+		}
+	})
+
+	run := func(version string) (*result.ReconcileResult, bool) {
+		res, changed, err := runner.Run(context.Background(), pkg, pi, v1alpha1.HookPreUpgrade, version, nil)
+		Expect(err).NotTo(HaveOccurred())
+		return res, changed
+	}
+
+	setJobCondition := func(conditionType batchv1.JobConditionType) {
+		var job batchv1.Job
+		Expect(c.Get(context.Background(), jobKey, &job)).To(Succeed())
+		job.Status.Conditions = append(job.Status.Conditions,
+			batchv1.JobCondition{Type: conditionType, Status: corev1.ConditionTrue, Message: "test"})
+		Expect(c.Status().Update(context.Background(), &job)).To(Succeed())
+	}
+
+	It("should wait for a pending Job", func() {
+		res, changed := run("v1")
+		Expect(res.IsWaiting()).To(BeTrue())
+		Expect(changed).To(BeTrue())
+		Expect(c.Get(context.Background(), jobKey, &batchv1.Job{})).To(Succeed())
+		Expect(pkg.Status.Hooks).To(ConsistOf(
+			HaveField("Resources", ConsistOf(HaveField("Name", "migrate")))))
+
+		res, changed = run("v1")
+		Expect(res.IsWaiting()).To(BeTrue())
+		Expect(changed).To(BeFalse())
+	})
+
+	It("should complete when the Job completes", func() {
+		run("v1")
+		setJobCondition(batchv1.JobComplete)
+
+		res, changed := run("v1")
+		Expect(res.IsReady()).To(BeTrue())
+		Expect(changed).To(BeTrue())
+		Expect(IsCompleted(pkg.Status.Hooks, pi.Status.Manifest.Hooks[0], "v1")).To(BeTrue())
+	})
+
+	It("should not run a failed hook again for the same version", func() {
+		run("v1")
+		setJobCondition(batchv1.JobFailed)
+
+		res, changed := run("v1")
+		Expect(res.IsFailed()).To(BeTrue())
+		Expect(changed).To(BeTrue())
+		Expect(pkg.Status.Hooks).To(ConsistOf(And(
+			HaveField("Failed", true),
+			HaveField("Message", ContainSubstring("job failed")))))
+		err := c.Get(context.Background(), jobKey, &batchv1.Job{})
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+
+		res, changed = run("v1")
+		Expect(res.IsFailed()).To(BeTrue())
+		Expect(changed).To(BeFalse())
+		err = c.Get(context.Background(), jobKey, &batchv1.Job{})
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+
+		By("running the hook again for another version")
+		res, _ = run("v2")
+		Expect(res.IsWaiting()).To(BeTrue())
+		Expect(pkg.Status.Hooks).To(ConsistOf(And(HaveField("Version", "v2"), HaveField("Failed", false))))
+		Expect(c.Get(context.Background(), jobKey, &batchv1.Job{})).To(Succeed())
+	})
+})
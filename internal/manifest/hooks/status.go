@@ -0,0 +1,63 @@
+package hooks
+
+import (
+	"github.com/glasskube/glasskube/api/v1alpha1"
+)
+
+// ApplyPhases returns the phases of the hooks that run before and after the resources of version are applied,
+// depending on the currently installed version. If version is already installed, ok is false.
+func ApplyPhases(installedVersion, version string) (pre, post v1alpha1.HookPhase, ok bool) {
+	if installedVersion == "" {
+		return v1alpha1.HookPreInstall, v1alpha1.HookPostInstall, true
+	} else if installedVersion != version {
+		return v1alpha1.HookPreUpgrade, v1alpha1.HookPostUpgrade, true
+	} else {
+		return "", "", false
+	}
+}
+
+// ForPhase returns all hooks of manifest that run in the given phase in the order in which they are declared.
+func ForPhase(manifest *v1alpha1.PackageManifest, phase v1alpha1.HookPhase) []v1alpha1.PackageHook {
+	var result []v1alpha1.PackageHook
+	if manifest != nil {
+		for _, hook := range manifest.Hooks {
+			if hook.Phase == phase {
+				result = append(result, hook)
+			}
+		}
+	}
+	return result
+}
+
+// IsCompleted returns true if hook has already completed for the given version.
+func IsCompleted(statuses []v1alpha1.HookStatus, hook v1alpha1.PackageHook, version string) bool {
+	status := findStatus(statuses, hook)
+	return status != nil && status.Version == version && status.Completed
+}
+
+func findStatus(statuses []v1alpha1.HookStatus, hook v1alpha1.PackageHook) *v1alpha1.HookStatus {
+	for i := range statuses {
+		if statuses[i].Name == hook.Name && statuses[i].Phase == hook.Phase {
+			return &statuses[i]
+		}
+	}
+	return nil
+}
+
+// setStatus replaces the status of the same hook in statuses or appends it, if it does not exist yet.
+func setStatus(statuses *[]v1alpha1.HookStatus, status v1alpha1.HookStatus) {
+	for i := range *statuses {
+		if (*statuses)[i].Name == status.Name && (*statuses)[i].Phase == status.Phase {
+			(*statuses)[i] = status
+			return
+		}
+	}
+	*statuses = append(*statuses, status)
+}
+
+func deletionPolicy(hook v1alpha1.PackageHook) v1alpha1.HookDeletionPolicy {
+	if hook.DeletionPolicy == "" {
+		return v1alpha1.HookDeleteBeforeCreation
+	}
+	return hook.DeletionPolicy
+}
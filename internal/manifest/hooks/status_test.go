@@ -0,0 +1,73 @@
+package hooks
+
+import (
+	"github.com/glasskube/glasskube/api/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ApplyPhases", func() {
+	DescribeTable("should return the phases for the installed version",
+		func(installedVersion string, expectedPre, expectedPost v1alpha1.HookPhase, expectedOk bool) {
+			pre, post, ok := ApplyPhases(installedVersion, "v2")
+			Expect(pre).To(Equal(expectedPre))
+			Expect(post).To(Equal(expectedPost))
+			Expect(ok).To(Equal(expectedOk))
+		},
+		Entry("not installed", "", v1alpha1.HookPreInstall, v1alpha1.HookPostInstall, true),
+		Entry("older version installed", "v1", v1alpha1.HookPreUpgrade, v1alpha1.HookPostUpgrade, true),
+		Entry("same version installed", "v2", v1alpha1.HookPhase(""), v1alpha1.HookPhase(""), false),
+	)
+})
+
+var _ = Describe("ForPhase", func() {
+	manifest := &v1alpha1.PackageManifest{
+		Hooks: []v1alpha1.PackageHook{
+			{Name: "migrate", Phase: v1alpha1.HookPreUpgrade},
+			{Name: "init", Phase: v1alpha1.HookPostInstall},
+			{Name: "backup", Phase: v1alpha1.HookPreUpgrade},
+		},
+	}
+
+	It("should return hooks of the phase in order", func() {
+		hooks := ForPhase(manifest, v1alpha1.HookPreUpgrade)
+		Expect(hooks).To(HaveLen(2))
+		Expect(hooks[0].Name).To(Equal("migrate"))
+		Expect(hooks[1].Name).To(Equal("backup"))
+	})
+	It("should return nothing for a phase without hooks", func() {
+		Expect(ForPhase(manifest, v1alpha1.HookPreDelete)).To(BeEmpty())
+		Expect(ForPhase(nil, v1alpha1.HookPreDelete)).To(BeEmpty())
+	})
+})
+
+var _ = Describe("IsCompleted", func() {
+	hook := v1alpha1.PackageHook{Name: "migrate", Phase: v1alpha1.HookPreUpgrade}
+
+	It("should only be true for a completed run of the same version", func() {
+		statuses := []v1alpha1.HookStatus{
+			{Name: "migrate", Phase: v1alpha1.HookPreInstall, Version: "v2", Completed: true},
+			{Name: "migrate", Phase: v1alpha1.HookPreUpgrade, Version: "v1", Completed: true},
+		}
+		Expect(IsCompleted(statuses, hook, "v1")).To(BeTrue())
+		Expect(IsCompleted(statuses, hook, "v2")).To(BeFalse())
+	})
+	It("should be false for an incomplete run", func() {
+		statuses := []v1alpha1.HookStatus{{Name: "migrate", Phase: v1alpha1.HookPreUpgrade, Version: "v1"}}
+		Expect(IsCompleted(statuses, hook, "v1")).To(BeFalse())
+		Expect(IsCompleted(nil, hook, "v1")).To(BeFalse())
+	})
+})
+
+var _ = Describe("setStatus", func() {
+	It("should replace the status of the same hook and append others", func() {
+		var statuses []v1alpha1.HookStatus
+		setStatus(&statuses, v1alpha1.HookStatus{Name: "a", Phase: v1alpha1.HookPreInstall, Version: "v1"})
+		setStatus(&statuses, v1alpha1.HookStatus{Name: "a", Phase: v1alpha1.HookPreUpgrade, Version: "v1"})
+		setStatus(&statuses, v1alpha1.HookStatus{Name: "a", Phase: v1alpha1.HookPreInstall, Version: "v2"})
+		Expect(statuses).To(Equal([]v1alpha1.HookStatus{
+			{Name: "a", Phase: v1alpha1.HookPreInstall, Version: "v2"},
+			{Name: "a", Phase: v1alpha1.HookPreUpgrade, Version: "v1"},
+		}))
+	})
+})
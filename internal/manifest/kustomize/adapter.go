@@ -0,0 +1,170 @@
+package kustomize
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	packagesv1alpha1 "github.com/glasskube/glasskube/api/v1alpha1"
+	"github.com/glasskube/glasskube/internal/controller/ctrlpkg"
+	"github.com/glasskube/glasskube/internal/kustomize"
+	"github.com/glasskube/glasskube/internal/manifest"
+	"github.com/glasskube/glasskube/internal/manifest/plain"
+	"github.com/glasskube/glasskube/internal/manifest/result"
+	"github.com/glasskube/glasskube/internal/resourcepatch"
+	"github.com/glasskube/glasskube/internal/tracing"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/kustomize/api/konfig"
+	kstypes "sigs.k8s.io/kustomize/api/types"
+)
+
+// packageDir is the directory in which files from the package repository are stored while building.
+const packageDir = "package"
+
+// Adapter builds the kustomization of a package and applies the resulting resources.
+// Applying resources and checking their readiness is delegated to the plain manifest adapter, so that both adapters
+// handle namespaces, owner references, value patches and prefixes in the same way.
+type Adapter struct {
+	*plain.Adapter
+}
+
+func NewAdapter() manifest.ManifestAdapter {
+	return &Adapter{Adapter: &plain.Adapter{}}
+}
+
+// Reconcile implements manifest.ManifestAdapter.
+func (a *Adapter) Reconcile(
+	ctx context.Context,
+	pkg ctrlpkg.Package,
+	pi *packagesv1alpha1.PackageInfo,
+	patches resourcepatch.TargetPatches,
+) (*result.ReconcileResult, error) {
+	manifest := pi.Status.Manifest.Kustomize
+	if objects, err := a.build(ctx, pkg, pi, *manifest); err != nil {
+		return nil, fmt.Errorf("could not build kustomization: %w", err)
+	} else if owned, err := a.ApplyObjects(ctx, pkg, pi, manifest.Namespace, objects, patches); err != nil {
+		return nil, err
+	} else {
+		return a.CheckReadiness(ctx, owned, pi.Status.Manifest.ReadinessChecks)
+	}
+}
+
+// build builds the kustomization of a package in a temporary directory. Local paths are fetched from the package
+// repository and remote git bases are cloned, before the kustomization is built by kustomize.
+func (a *Adapter) build(
+	ctx context.Context,
+	pkg ctrlpkg.Package,
+	pi *packagesv1alpha1.PackageInfo,
+	manifest packagesv1alpha1.KustomizeManifest,
+) (_ []client.Object, err error) {
+	ctx, span := tracing.Start(ctx, "build kustomization", tracing.AttributeURL.String(manifest.Url))
+	defer func() { tracing.End(span, err) }()
+
+	log := ctrl.LoggerFrom(ctx)
+	kustomization := kstypes.Kustomization{Images: toKustomizeImages(manifest.Images)}
+	if !pkg.IsNamespaceScoped() {
+		kustomization.Namespace = manifest.Namespace
+	}
+
+	dir, err := os.MkdirTemp("", "glasskube-kustomize-")
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	loader := newTreeLoader(ctx, dir)
+	if isGitBase(manifest.Url) {
+		if baseDir, err := loader.loadGitBase(manifest.Url); err != nil {
+			return nil, fmt.Errorf("could not load kustomization base %v: %w", manifest.Url, err)
+		} else if rel, err := filepath.Rel(dir, baseDir); err != nil {
+			return nil, err
+		} else {
+			kustomization.Resources = []string{filepath.ToSlash(rel)}
+		}
+	} else if isRemote(manifest.Url) {
+		kustomization.Resources = []string{manifest.Url}
+	} else if resourcePath, err := localPath(".", manifest.Url); err != nil {
+		return nil, err
+	} else {
+		packageTree := tree{
+			root:  filepath.Join(dir, packageDir),
+			fetch: func(filePath string) ([]byte, error) { return a.FetchPackageFile(ctx, pi, filePath) },
+		}
+		if isKustomizationFile(resourcePath) {
+			resourcePath = path.Dir(resourcePath)
+			err = loader.loadKustomization(packageTree, resourcePath)
+		} else if isManifestFile(resourcePath) {
+			err = loader.loadFile(packageTree, resourcePath)
+		} else {
+			err = loader.loadKustomization(packageTree, resourcePath)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("could not load kustomization %v: %w", manifest.Url, err)
+		}
+		kustomization.Resources = []string{path.Join(packageDir, resourcePath)}
+	}
+
+	if objects, err := kustomize.Build(dir, kustomization, nil); err != nil {
+		return nil, err
+	} else {
+		log.V(1).Info("built kustomization", "url", manifest.Url, "objectCount", len(objects))
+		return objects, nil
+	}
+}
+
+// isRemote returns true if urlOrPath should be loaded by kustomize directly.
+// Any other value is treated as a path relative to the "package.yaml" file.
+func isRemote(urlOrPath string) bool {
+	if strings.HasPrefix(urlOrPath, "git@") {
+		return true
+	} else if parsedUrl, err := url.Parse(urlOrPath); err != nil {
+		return false
+	} else {
+		return parsedUrl.Scheme != "" || parsedUrl.Host != ""
+	}
+}
+
+// isManifestFile returns true if the path of urlOrPath has the extension of a manifest file.
+// Remote targets without such an extension are git repositories.
+func isManifestFile(urlOrPath string) bool {
+	switch strings.ToLower(path.Ext(pathOf(urlOrPath))) {
+	case ".yaml", ".yml", ".json":
+		return true
+	default:
+		return false
+	}
+}
+
+// isKustomizationFile returns true if urlOrPath points to a kustomization file instead of a resource file.
+func isKustomizationFile(urlOrPath string) bool {
+	return slices.Contains(konfig.RecognizedKustomizationFileNames(), path.Base(pathOf(urlOrPath)))
+}
+
+func pathOf(urlOrPath string) string {
+	if parsedUrl, err := url.Parse(urlOrPath); err == nil {
+		return parsedUrl.Path
+	}
+	return urlOrPath
+}
+
+func toKustomizeImages(images []packagesv1alpha1.KustomizeImage) []kstypes.Image {
+	if len(images) == 0 {
+		return nil
+	}
+	result := make([]kstypes.Image, len(images))
+	for i, image := range images {
+		result[i] = kstypes.Image{
+			Name:    image.Name,
+			NewName: image.NewName,
+			NewTag:  image.NewTag,
+			Digest:  image.Digest,
+		}
+	}
+	return result
+}
@@ -0,0 +1,311 @@
+package kustomize
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/glasskube/glasskube/api/v1alpha1"
+	"github.com/glasskube/glasskube/internal/contenttype"
+	"github.com/glasskube/glasskube/internal/kustomize"
+	"github.com/glasskube/glasskube/internal/repo/client/fake"
+	"github.com/glasskube/glasskube/internal/resourcepatch"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	kstypes "sigs.k8s.io/kustomize/api/types"
+)
+
+var _ = Describe("isRemote", func() {
+	DescribeTable("should detect remote urls",
+		func(urlOrPath string, expected bool) {
+			Expect(isRemote(urlOrPath)).To(Equal(expected))
+		},
+		Entry("https file", "https://example.com/manifest.yaml", true),
+		Entry("git repo with path and ref", "https://github.com/example/repo//config/default?ref=v1.0.0", true),
+		Entry("ssh git repo", "git@github.com:example/repo.git", true),
+		Entry("relative path", "./kustomize/resources.yaml", false),
+		Entry("plain file name", "resources.yaml", false),
+		Entry("parent path", "../resources.yaml", false),
+	)
+})
+
+var _ = Describe("isManifestFile", func() {
+	DescribeTable("should detect manifest files",
+		func(urlOrPath string, expected bool) {
+			Expect(isManifestFile(urlOrPath)).To(Equal(expected))
+		},
+		Entry("https file", "https://example.com/manifest.yaml", true),
+		Entry("https file with query", "https://example.com/manifest.yml?raw=true", true),
+		Entry("git repo with path and ref", "https://github.com/example/repo//config/default?ref=v1.0.0", false),
+		Entry("ssh git repo", "git@github.com:example/repo.git", false),
+		Entry("relative file", "./kustomize/resources.yaml", true),
+		Entry("relative directory", "./kustomize/base", false),
+	)
+})
+
+var _ = Describe("build", func() {
+	It("should apply namespace and images", func() {
+		deployment := unstructured.Unstructured{}
+		deployment.SetAPIVersion("apps/v1")
+		deployment.SetKind("Deployment")
+		deployment.SetName("test")
+		Expect(unstructured.SetNestedSlice(deployment.Object, []any{
+			map[string]any{"name": "test", "image": "nginx:1.0.0"},
+		}, "spec", "template", "spec", "containers")).To(Succeed())
+
+		kustomization := kstypes.Kustomization{
+			Namespace: "test-ns",
+			Images: toKustomizeImages([]v1alpha1.KustomizeImage{
+				{Name: "nginx", NewName: "example.com/nginx", NewTag: "2.0.0"},
+			}),
+		}
+		result, err := kustomize.Build(GinkgoT().TempDir(), kustomization, []client.Object{&deployment})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].GetNamespace()).To(Equal("test-ns"))
+		containers, _, _ := unstructured.NestedSlice(result[0].(*unstructured.Unstructured).Object,
+			"spec", "template", "spec", "containers")
+		Expect(containers).To(HaveLen(1))
+		Expect(containers[0]).To(HaveKeyWithValue("image", "example.com/nginx:2.0.0"))
+	})
+})
+
+var _ = Describe("parseGitBase", func() {
+	DescribeTable("should split repository, path and ref",
+		func(rawURL string, expected gitBase) {
+			Expect(parseGitBase(rawURL)).To(Equal(&expected))
+		},
+		Entry("path and ref", "https://github.com/example/repo//config/default?ref=v1.0.0",
+			gitBase{repoURL: "https://github.com/example/repo", path: "config/default", ref: "v1.0.0"}),
+		Entry("repository only", "https://github.com/example/repo",
+			gitBase{repoURL: "https://github.com/example/repo", path: "."}),
+		Entry("path after .git", "https://github.com/example/repo.git/config?version=main",
+			gitBase{repoURL: "https://github.com/example/repo.git", path: "config", ref: "main"}),
+		Entry("ssh", "git@github.com:example/repo.git//config",
+			gitBase{repoURL: "git@github.com:example/repo.git", path: "config"}),
+		Entry("go-getter prefix", "git::https://example.com/repo//a/../../b",
+			gitBase{repoURL: "https://example.com/repo", path: "b"}),
+	)
+})
+
+var _ = Describe("findRef", func() {
+	refs := []*plumbing.Reference{
+		plumbing.NewSymbolicReference(plumbing.HEAD, plumbing.NewBranchReferenceName("main")),
+		plumbing.NewHashReference(plumbing.NewBranchReferenceName("main"), plumbing.ZeroHash),
+		plumbing.NewHashReference(plumbing.NewBranchReferenceName("v1"), plumbing.ZeroHash),
+		plumbing.NewHashReference(plumbing.NewTagReferenceName("v1"), plumbing.ZeroHash),
+	}
+	DescribeTable("should find the remote reference",
+		func(ref string, expected plumbing.ReferenceName, expectedFound bool) {
+			name, found := (&gitBase{ref: ref}).findRef(refs)
+			Expect(found).To(Equal(expectedFound))
+			Expect(name).To(Equal(expected))
+		},
+		Entry("default branch", "", plumbing.HEAD, true),
+		Entry("branch", "main", plumbing.NewBranchReferenceName("main"), true),
+		Entry("tag before branch", "v1", plumbing.NewTagReferenceName("v1"), true),
+		Entry("full name", "refs/heads/v1", plumbing.NewBranchReferenceName("v1"), true),
+		Entry("unknown ref", "v2", plumbing.ReferenceName(""), false),
+	)
+})
+
+// createGitBase creates a git repository with a kustomization in the "config" directory and returns its URL.
+func createGitBase() string {
+	dir := GinkgoT().TempDir()
+	repo, err := git.PlainInit(dir, false)
+	Expect(err).NotTo(HaveOccurred())
+	Expect(os.MkdirAll(filepath.Join(dir, "config"), 0o700)).To(Succeed())
+	Expect(os.WriteFile(filepath.Join(dir, "config", "kustomization.yaml"),
+		[]byte("resources:\n  - configmap.yaml\n"), 0o600)).To(Succeed())
+	Expect(os.WriteFile(filepath.Join(dir, "config", "configmap.yaml"),
+		[]byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: remote\n  namespace: default\n"), 0o600)).
+		To(Succeed())
+	worktree, err := repo.Worktree()
+	Expect(err).NotTo(HaveOccurred())
+	Expect(worktree.AddGlob("config/*")).To(Succeed())
+	hash, err := worktree.Commit("init", &git.CommitOptions{
+		Author: &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()},
+	})
+	Expect(err).NotTo(HaveOccurred())
+	// The in-process file transport can only serve the git directory, not the worktree. It also does not support
+	// shallow fetches, so the base refers to a commit, which is fetched in full.
+	return "file://" + filepath.Join(dir, git.GitDirName) + "//config?ref=" + hash.String()
+}
+
+var _ = Describe("Adapter", func() {
+	var files map[string]string
+	var c client.Client
+	var adapter *Adapter
+	var pkg *v1alpha1.ClusterPackage
+	var pi *v1alpha1.PackageInfo
+	var patches resourcepatch.TargetPatches
+	deploymentKey := types.NamespacedName{Namespace: "default", Name: "web"}
+
+	BeforeEach(func() {
+		files = map[string]string{
+			"/foo/v1/kustomize/overlay/kustomization.yaml": "resources:\n  - ../base\n" +
+				"patches:\n  - path: annotation.yaml\n" +
+				"configMapGenerator:\n  - name: settings\n    namespace: default\n    envs:\n      - settings.env\n",
+			"/foo/v1/kustomize/overlay/annotation.yaml": "apiVersion: apps/v1\nkind: Deployment\n" +
+				"metadata:\n  name: web\n  namespace: default\n  annotations:\n    overlay: applied\n",
+			"/foo/v1/kustomize/overlay/settings.env": "FOO=bar\n",
+			"/foo/v1/kustomize/base/kustomization.yaml": "resources:\n  - deployment.yaml\n  - " +
+				createGitBase() + "\n",
+			"/foo/v1/kustomize/base/deployment.yaml": `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: default
+spec:
+  replicas: 1
+  selector:
+    matchLabels:
+      app: web
+  template:
+    metadata:
+      labels:
+        app: web
+    spec:
+      containers:
+        - name: web
+          image: nginx:1.0.0
+`,
+		}
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if content, ok := files[r.URL.Path]; ok {
+				if strings.HasSuffix(r.URL.Path, ".yaml") {
+					w.Header().Set("Content-Type", contenttype.MediaTypeYAML)
+				}
+				_, _ = w.Write([]byte(content))
+			} else {
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		DeferCleanup(server.Close)
+
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(v1alpha1.AddToScheme(scheme)).To(Succeed())
+		c = ctrlfake.NewClientBuilder().
+			WithScheme(scheme).
+			WithStatusSubresource(&appsv1.Deployment{}).
+			WithInterceptorFuncs(interceptor.Funcs{
+				// The fake client does not support server-side apply, so resources are created or updated instead.
+				Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch,
+					opts ...client.PatchOption) error {
+					if patch.Type() != types.ApplyPatchType {
+						return c.Patch(ctx, obj, patch, opts...)
+					}
+					existing := obj.DeepCopyObject().(client.Object)
+					if err := c.Get(ctx, client.ObjectKeyFromObject(obj), existing); apierrors.IsNotFound(err) {
+						return c.Create(ctx, obj)
+					} else if err != nil {
+						return err
+					}
+					obj.SetResourceVersion(existing.GetResourceVersion())
+					return c.Update(ctx, obj)
+				},
+			}).
+			Build()
+		adapter = NewAdapter().(*Adapter)
+		Expect(adapter.ControllerInit(nil, c, fake.ClientsetWithClient(fake.EmptyClient()), scheme)).To(Succeed())
+
+		pkg = &v1alpha1.ClusterPackage{
+			TypeMeta:   metav1.TypeMeta{APIVersion: v1alpha1.GroupVersion.String(), Kind: "ClusterPackage"},
+			ObjectMeta: metav1.ObjectMeta{Name: "foo", UID: "foo-uid"},
+		}
+		pi = &v1alpha1.PackageInfo{
+			Spec: v1alpha1.PackageInfoSpec{Name: "foo", Version: "v1"},
+			Status: v1alpha1.PackageInfoStatus{
+				Version:     "v1",
+				ResolvedUrl: server.URL + "/foo/v1/package.yaml",
+				Manifest: &v1alpha1.PackageManifest{
+					Name: "foo",
+					Kustomize: &v1alpha1.KustomizeManifest{
+						Url:    "./kustomize/overlay",
+						Images: []v1alpha1.KustomizeImage{{Name: "nginx", NewTag: "2.0.0"}},
+					},
+				},
+			},
+		}
+		patch, err := resourcepatch.GenerateTargetPatch(v1alpha1.ValueDefinitionTarget{
+			Resource: &corev1.TypedObjectReference{APIGroup: ptr.To("apps/v1"), Kind: "Deployment", Name: "web"},
+			Patch:    v1alpha1.PartialJsonPatch{Op: "replace", Path: "/spec/replicas"},
+		}, 3)
+		Expect(err).NotTo(HaveOccurred())
+		patches = resourcepatch.TargetPatches{*patch}
+	})
+
+	It("should apply a kustomization from the package repository", func(ctx context.Context) {
+		res, err := adapter.Reconcile(ctx, pkg, pi, patches)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res.IsWaiting()).To(BeTrue())
+		Expect(res.OwnedResources).To(HaveLen(3))
+
+		var deployment appsv1.Deployment
+		Expect(c.Get(ctx, deploymentKey, &deployment)).To(Succeed())
+		Expect(deployment.Spec.Replicas).To(HaveValue(BeEquivalentTo(3)))
+		Expect(deployment.Annotations).To(HaveKeyWithValue("overlay", "applied"))
+		Expect(deployment.Spec.Template.Spec.Containers[0].Image).To(Equal("nginx:2.0.0"))
+		Expect(deployment.OwnerReferences).To(ConsistOf(HaveField("UID", pkg.UID)))
+
+		var configMaps corev1.ConfigMapList
+		Expect(c.List(ctx, &configMaps, client.InNamespace("default"))).To(Succeed())
+		Expect(configMaps.Items).To(ConsistOf(
+			HaveField("Name", "remote"),
+			And(HaveField("Name", HavePrefix("settings-")), HaveField("Data", HaveKeyWithValue("FOO", "bar"))),
+		))
+
+		By("reporting readiness once the Deployment is available")
+		deployment.Status = appsv1.DeploymentStatus{Replicas: 3, UpdatedReplicas: 3, ReadyReplicas: 3, AvailableReplicas: 3}
+		Expect(c.Status().Update(ctx, &deployment)).To(Succeed())
+		res, err = adapter.Reconcile(ctx, pkg, pi, patches)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res.IsReady()).To(BeTrue())
+	})
+
+	It("should apply a remote git base", func(ctx context.Context) {
+		pi.Status.Manifest.Kustomize.Url = createGitBase()
+		res, err := adapter.Reconcile(ctx, pkg, pi, patches)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res.IsReady()).To(BeTrue())
+		var configMap corev1.ConfigMap
+		Expect(c.Get(ctx, types.NamespacedName{Namespace: "default", Name: "remote"}, &configMap)).To(Succeed())
+		Expect(configMap.OwnerReferences).To(ConsistOf(HaveField("UID", pkg.UID)))
+	})
+
+	DescribeTable("should reject paths outside of the package",
+		func(ctx context.Context, url string) {
+			pi.Status.Manifest.Kustomize.Url = url
+			_, err := adapter.Reconcile(ctx, pkg, pi, patches)
+			Expect(err).To(MatchError(ContainSubstring("not inside the kustomization tree")))
+		},
+		Entry("parent directory", "../other"),
+		Entry("absolute path", "/etc/kustomization.yaml"),
+	)
+
+	It("should fail for a missing kustomization", func(ctx context.Context) {
+		pi.Status.Manifest.Kustomize.Url = "./kustomize/missing"
+		_, err := adapter.Reconcile(ctx, pkg, pi, patches)
+		Expect(err).To(MatchError(ContainSubstring("could not find a kustomization in kustomize/missing")))
+	})
+})
@@ -0,0 +1,128 @@
+package kustomize
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/glasskube/glasskube/internal/tracing"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// fetchedRef is the local reference the ref of a gitBase is fetched to.
+const fetchedRef plumbing.ReferenceName = "refs/glasskube/base"
+
+// gitBase is a kustomization base in a remote git repository, using the URL format of kustomize:
+// "<repository>[//<path>][?ref=<ref>]", e.g. "https://github.com/example/repo//config/default?ref=v1.0.0".
+// If the repository URL ends with ".git", the path may also follow it directly, e.g.
+// "https://github.com/example/repo.git/config/default".
+type gitBase struct {
+	repoURL string
+	// path is the path of the kustomization in the repository. It is always relative and never contains "..".
+	path string
+	// ref is a branch, tag or commit. If it is empty, the default branch is used.
+	ref string
+}
+
+// isGitBase returns true if urlOrPath is a remote target that is not a single manifest file.
+func isGitBase(urlOrPath string) bool {
+	return isRemote(urlOrPath) && !isManifestFile(urlOrPath)
+}
+
+func parseGitBase(rawURL string) (*gitBase, error) {
+	s, rawQuery, _ := strings.Cut(strings.TrimPrefix(rawURL, "git::"), "?")
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return nil, fmt.Errorf("invalid kustomization base %v: %w", rawURL, err)
+	}
+	base := gitBase{repoURL: s, ref: query.Get("ref")}
+	if base.ref == "" {
+		base.ref = query.Get("version")
+	}
+
+	pathStart := 0
+	if i := strings.Index(s, "://"); i >= 0 {
+		pathStart = i + len("://")
+	}
+	var repoPath string
+	if i := strings.Index(s[pathStart:], "//"); i >= 0 {
+		base.repoURL, repoPath = s[:pathStart+i], s[pathStart+i+len("//"):]
+	} else if i := strings.Index(s[pathStart:], ".git/"); i >= 0 {
+		base.repoURL, repoPath = s[:pathStart+i+len(".git")], s[pathStart+i+len(".git/"):]
+	}
+	base.path = strings.TrimPrefix(path.Clean("/"+repoPath), "/")
+	if base.path == "" {
+		base.path = "."
+	}
+	return &base, nil
+}
+
+// clone clones the repository of base into dir and checks out its ref. Only the commit of the ref is fetched, unless
+// the ref is a commit hash.
+func (base *gitBase) clone(ctx context.Context, dir string) (err error) {
+	ctx, span := tracing.Start(ctx, "clone kustomization base", tracing.AttributeURL.String(base.repoURL))
+	defer func() { tracing.End(span, err) }()
+
+	repo, err := git.PlainInit(dir, false)
+	if err != nil {
+		return err
+	}
+	remote, err := repo.CreateRemote(&config.RemoteConfig{Name: git.DefaultRemoteName, URLs: []string{base.repoURL}})
+	if err != nil {
+		return err
+	}
+	refs, err := remote.ListContext(ctx, &git.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("could not list refs of %v: %w", base.repoURL, err)
+	}
+
+	options := git.FetchOptions{RemoteName: git.DefaultRemoteName, Tags: git.NoTags}
+	revision := plumbing.Revision(fetchedRef)
+	if name, ok := base.findRef(refs); ok {
+		options.RefSpecs = []config.RefSpec{config.RefSpec(fmt.Sprintf("+%v:%v", name, fetchedRef))}
+		options.Depth = 1
+	} else if plumbing.IsHash(base.ref) {
+		options.RefSpecs = []config.RefSpec{config.RefSpec(fmt.Sprintf("+refs/heads/*:%v/*", fetchedRef))}
+		revision = plumbing.Revision(base.ref)
+	} else {
+		return fmt.Errorf("could not find ref %q in %v", base.ref, base.repoURL)
+	}
+	if err := repo.FetchContext(ctx, &options); err != nil && !errors.Is(err, git.NoErrAlreadyUpToDate) {
+		return fmt.Errorf("could not fetch %v: %w", base.repoURL, err)
+	}
+
+	if hash, err := repo.ResolveRevision(revision); err != nil {
+		return fmt.Errorf("could not resolve ref %q of %v: %w", base.ref, base.repoURL, err)
+	} else if worktree, err := repo.Worktree(); err != nil {
+		return err
+	} else {
+		return worktree.Checkout(&git.CheckoutOptions{Hash: *hash, Force: true})
+	}
+}
+
+// findRef returns the name of the remote reference for the ref of base. Tags take precedence over branches.
+func (base *gitBase) findRef(refs []*plumbing.Reference) (plumbing.ReferenceName, bool) {
+	var candidates []plumbing.ReferenceName
+	if base.ref == "" {
+		candidates = []plumbing.ReferenceName{plumbing.HEAD}
+	} else {
+		candidates = []plumbing.ReferenceName{
+			plumbing.NewTagReferenceName(base.ref),
+			plumbing.NewBranchReferenceName(base.ref),
+			plumbing.ReferenceName(base.ref),
+		}
+	}
+	for _, candidate := range candidates {
+		for _, ref := range refs {
+			if ref.Name() == candidate {
+				return candidate, true
+			}
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,18 @@
+package kustomize
+
+import (
+	"testing"
+
+	"github.com/go-git/go-git/v5/plumbing/transport/client"
+	"github.com/go-git/go-git/v5/plumbing/transport/server"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestKustomizeManifestAdapter(t *testing.T) {
+	RegisterFailHandler(Fail)
+	// The default file transport runs the git executable, which is not available in the package operator image.
+	// Test repositories are served in-process instead, like repositories that are cloned over HTTP or SSH.
+	client.InstallProtocol("file", server.DefaultServer)
+	RunSpecs(t, "KustomizeManifestAdapter Suite")
+}
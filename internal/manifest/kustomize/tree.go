@@ -0,0 +1,225 @@
+package kustomize
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"go.uber.org/multierr"
+	"sigs.k8s.io/kustomize/api/konfig"
+	kstypes "sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/yaml"
+)
+
+// maxGitBases is the maximum number of remote git bases that are cloned for a single kustomization, so that bases
+// that refer to each other can not clone repositories indefinitely.
+const maxGitBases = 10
+
+// resourceFields are the fields of a kustomization that can refer to other kustomizations, including remote bases.
+var resourceFields = []string{"resources", "bases", "components", "generators", "transformers", "validators"}
+
+// tree is a directory on disk that contains a kustomization and the files it refers to.
+type tree struct {
+	root string
+	// fetch returns the file at the given path relative to root. If fetch is nil, all files are already present on
+	// disk, otherwise they are fetched when the kustomizations in the tree are loaded.
+	fetch func(path string) ([]byte, error)
+}
+
+// treeLoader copies kustomizations into a directory on disk, so that kustomize can build them without access to the
+// package repository and without the git executable:
+//   - Kustomizations in the package repository are fetched together with all local files they refer to.
+//   - Remote git bases are cloned using go-git and references to them are replaced with the path of the clone.
+type treeLoader struct {
+	ctx    context.Context
+	dir    string
+	bases  int
+	loaded map[string]bool
+}
+
+func newTreeLoader(ctx context.Context, dir string) *treeLoader {
+	return &treeLoader{ctx: ctx, dir: dir, loaded: make(map[string]bool)}
+}
+
+// loadGitBase clones the git base at rawURL and returns the directory of its kustomization.
+func (l *treeLoader) loadGitBase(rawURL string) (string, error) {
+	base, err := parseGitBase(rawURL)
+	if err != nil {
+		return "", err
+	}
+	if l.bases++; l.bases > maxGitBases {
+		return "", fmt.Errorf("kustomization refers to more than %v git bases", maxGitBases)
+	}
+	t := tree{root: filepath.Join(l.dir, "bases", strconv.Itoa(l.bases))}
+	if err := base.clone(l.ctx, t.root); err != nil {
+		return "", err
+	} else if err := l.loadKustomization(t, base.path); err != nil {
+		return "", err
+	} else {
+		return filepath.Join(t.root, filepath.FromSlash(base.path)), nil
+	}
+}
+
+// loadKustomization loads the kustomization in the directory dir of t and everything it refers to.
+func (l *treeLoader) loadKustomization(t tree, dir string) error {
+	dirOnDisk := filepath.Join(t.root, filepath.FromSlash(dir))
+	if l.loaded[dirOnDisk] {
+		return nil
+	}
+	l.loaded[dirOnDisk] = true
+
+	fileName, data, err := l.readKustomization(t, dir)
+	if err != nil {
+		return err
+	}
+	var kustomization kstypes.Kustomization
+	var raw map[string]any
+	if err := yaml.Unmarshal(data, &kustomization); err != nil {
+		return fmt.Errorf("invalid kustomization in %v: %w", dir, err)
+	} else if err := yaml.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("invalid kustomization in %v: %w", dir, err)
+	}
+
+	changed := false
+	for _, field := range resourceFields {
+		entries, _ := raw[field].([]any)
+		for i, entry := range entries {
+			if s, ok := entry.(string); !ok {
+				continue
+			} else if replacement, err := l.loadResource(t, dir, s); err != nil {
+				return err
+			} else if replacement != s {
+				entries[i] = replacement
+				changed = true
+			}
+		}
+	}
+	for _, file := range referencedFiles(kustomization) {
+		if filePath, err := localPath(dir, file); err != nil {
+			return err
+		} else if err := l.loadFile(t, filePath); err != nil {
+			return err
+		}
+	}
+
+	if changed {
+		if data, err = yaml.Marshal(raw); err != nil {
+			return err
+		}
+	} else if t.fetch == nil {
+		return nil
+	}
+	return writeFile(filepath.Join(dirOnDisk, fileName), data)
+}
+
+// loadResource loads a single entry of a resource field and returns the value it must be replaced with.
+func (l *treeLoader) loadResource(t tree, dir string, entry string) (string, error) {
+	if isGitBase(entry) {
+		if baseDir, err := l.loadGitBase(entry); err != nil {
+			return "", err
+		} else if rel, err := filepath.Rel(filepath.Join(t.root, filepath.FromSlash(dir)), baseDir); err != nil {
+			return "", err
+		} else {
+			return filepath.ToSlash(rel), nil
+		}
+	} else if isRemote(entry) {
+		// Remote manifest files are loaded by kustomize directly.
+		return entry, nil
+	} else if resourcePath, err := localPath(dir, entry); err != nil {
+		return "", err
+	} else if isManifestFile(entry) {
+		return entry, l.loadFile(t, resourcePath)
+	} else {
+		return entry, l.loadKustomization(t, resourcePath)
+	}
+}
+
+func (l *treeLoader) readKustomization(t tree, dir string) (string, []byte, error) {
+	var errs error
+	for _, fileName := range konfig.RecognizedKustomizationFileNames() {
+		filePath := path.Join(dir, fileName)
+		var data []byte
+		var err error
+		if t.fetch != nil {
+			data, err = t.fetch(filePath)
+		} else {
+			data, err = os.ReadFile(filepath.Join(t.root, filepath.FromSlash(filePath)))
+		}
+		if err == nil {
+			return fileName, data, nil
+		}
+		errs = multierr.Append(errs, err)
+	}
+	return "", nil, fmt.Errorf("could not find a kustomization in %v: %w", dir, errs)
+}
+
+func (l *treeLoader) loadFile(t tree, filePath string) error {
+	fileOnDisk := filepath.Join(t.root, filepath.FromSlash(filePath))
+	if t.fetch == nil || l.loaded[fileOnDisk] {
+		return nil
+	}
+	l.loaded[fileOnDisk] = true
+	if data, err := t.fetch(filePath); err != nil {
+		return err
+	} else {
+		return writeFile(fileOnDisk, data)
+	}
+}
+
+// referencedFiles returns all local files a kustomization refers to, except for the entries of resourceFields.
+func referencedFiles(k kstypes.Kustomization) []string {
+	files := append(append([]string{}, k.Crds...), k.Configurations...)
+	for _, patch := range k.PatchesStrategicMerge {
+		// Strategic merge patches can either be inline or refer to a file.
+		if s := string(patch); !strings.Contains(s, "\n") && isManifestFile(s) {
+			files = append(files, s)
+		}
+	}
+	for _, patch := range append(append([]kstypes.Patch{}, k.Patches...), k.PatchesJson6902...) {
+		files = append(files, patch.Path)
+	}
+	for _, replacement := range k.Replacements {
+		files = append(files, replacement.Path)
+	}
+	var generatorArgs []kstypes.GeneratorArgs
+	for _, args := range k.ConfigMapGenerator {
+		generatorArgs = append(generatorArgs, args.GeneratorArgs)
+	}
+	for _, args := range k.SecretGenerator {
+		generatorArgs = append(generatorArgs, args.GeneratorArgs)
+	}
+	for _, args := range generatorArgs {
+		for _, source := range args.FileSources {
+			// File sources have the format "[key=]path".
+			_, file, found := strings.Cut(source, "=")
+			if !found {
+				file = source
+			}
+			files = append(files, file)
+		}
+		files = append(append(files, args.EnvSources...), args.EnvSource)
+	}
+	files = append(files, k.OpenAPI["path"])
+	return slices.DeleteFunc(files, func(file string) bool { return file == "" || isRemote(file) })
+}
+
+// localPath resolves entry relative to dir. Paths outside of the tree are rejected.
+func localPath(dir, entry string) (string, error) {
+	p := path.Join(dir, entry)
+	if path.IsAbs(entry) || p == ".." || strings.HasPrefix(p, "../") {
+		return "", fmt.Errorf("%v is not inside the kustomization tree", entry)
+	}
+	return p, nil
+}
+
+func writeFile(name string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(name), 0o700); err != nil {
+		return err
+	}
+	return os.WriteFile(name, data, 0o600)
+}
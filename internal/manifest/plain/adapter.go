@@ -3,20 +3,23 @@ package plain
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strings"
 
 	packagesv1alpha1 "github.com/glasskube/glasskube/api/v1alpha1"
-	"github.com/glasskube/glasskube/internal/clientutils"
 	"github.com/glasskube/glasskube/internal/constants"
 	"github.com/glasskube/glasskube/internal/controller/ctrlpkg"
 	"github.com/glasskube/glasskube/internal/controller/owners"
 	ownerutils "github.com/glasskube/glasskube/internal/controller/owners/utils"
 	"github.com/glasskube/glasskube/internal/manifest"
+	"github.com/glasskube/glasskube/internal/manifest/drift"
+	"github.com/glasskube/glasskube/internal/manifest/readiness"
 	"github.com/glasskube/glasskube/internal/manifest/result"
 	repoclient "github.com/glasskube/glasskube/internal/repo/client"
 	"github.com/glasskube/glasskube/internal/resourcepatch"
-	appsv1 "k8s.io/api/apps/v1"
+	"github.com/glasskube/glasskube/internal/tracing"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -67,67 +70,97 @@ func (a *Adapter) Reconcile(
 	pi *packagesv1alpha1.PackageInfo,
 	patches resourcepatch.TargetPatches,
 ) (*result.ReconcileResult, error) {
-	var allOwned []packagesv1alpha1.OwnedResourceRef
+	var allOwned, missing []packagesv1alpha1.OwnedResourceRef
+	var allDrift []packagesv1alpha1.ResourceDrift
 	for _, manifest := range pi.Status.Manifest.Manifests {
-		if owned, err := a.reconcilePlainManifest(ctx, pkg, pi, manifest, patches); err != nil {
+		if owned, resourceDrift, err := a.reconcilePlainManifest(ctx, pkg, pi, manifest, patches); err != nil {
 			return nil, err
 		} else {
 			allOwned = append(allOwned, owned...)
+			allDrift = append(allDrift, resourceDrift...)
 		}
 	}
 
-	var notReady []packagesv1alpha1.OwnedResourceRef
-	var notReadyNames []string
-	// of all owned deployments and stateful sets, check their readiness
+	// Deleted resources are only recreated if drift is corrected, so they can not be checked for readiness.
+	for _, resourceDrift := range allDrift {
+		if resourceDrift.Missing {
+			missing = append(missing, resourceDrift.OwnedResourceRef)
+		}
+	}
+	toCheck := slices.DeleteFunc(slices.Clone(allOwned), func(ref packagesv1alpha1.OwnedResourceRef) bool {
+		return slices.ContainsFunc(missing, func(missingRef packagesv1alpha1.OwnedResourceRef) bool {
+			return ownerutils.RefersToSameResource(ref, missingRef)
+		})
+	})
+
+	if res, err := a.CheckReadiness(ctx, toCheck, pi.Status.Manifest.ReadinessChecks); err != nil {
+		return nil, err
+	} else {
+		res.OwnedResources = allOwned
+		res.Drift = allDrift
+		return res, nil
+	}
+}
+
+// CheckReadiness checks the readiness of all given owned resources.
+// Resources for which checks exist are evaluated using these checks, all other resources using the builtin rules
+// of the readiness package. If at least one resource failed, a failed result is returned. If at least one of them
+// is not ready, a waiting result is returned.
+func (a *Adapter) CheckReadiness(
+	ctx context.Context,
+	allOwned []packagesv1alpha1.OwnedResourceRef,
+	checks []packagesv1alpha1.ReadinessCheck,
+) (*result.ReconcileResult, error) {
+	var notReady, failed []string
 	for _, ownedResourceRef := range allOwned {
+		gvk := schema.GroupVersionKind(ownedResourceRef.GroupVersionKind)
+		resourceChecks := readiness.ChecksFor(checks, gvk, ownedResourceRef.Name)
+		if len(resourceChecks) == 0 && !readiness.HasStatus(gvk.GroupKind()) {
+			continue
+		}
 		namespacedName := types.NamespacedName{Namespace: ownedResourceRef.Namespace, Name: ownedResourceRef.Name}
-		switch ownedResourceRef.Kind {
-		case constants.Deployment:
-			deployment := appsv1.Deployment{}
-			if err := a.Get(ctx, namespacedName, &deployment); err != nil {
-				return nil, fmt.Errorf("failed to get Deployment %v for status check: %w", namespacedName, err)
-			}
-			if !isReady(deployment.Status.ReadyReplicas, deployment.Spec.Replicas) {
-				notReady = append(notReady, ownedResourceRef)
-				notReadyNames = append(notReadyNames, namespacedName.String())
-			}
-		case constants.StatefulSet:
-			statefulSet := appsv1.StatefulSet{}
-			if err := a.Get(ctx, namespacedName, &statefulSet); err != nil {
-				return nil, fmt.Errorf("failed to get StatefulSet for status check: %w", err)
-			}
-			if !isReady(statefulSet.Status.ReadyReplicas, statefulSet.Spec.Replicas) {
-				notReady = append(notReady, ownedResourceRef)
-				notReadyNames = append(notReadyNames, namespacedName.String())
-			}
+		var obj unstructured.Unstructured
+		obj.SetGroupVersionKind(gvk)
+		if err := a.Get(ctx, namespacedName, &obj); err != nil {
+			return nil, fmt.Errorf("failed to get %v %v for status check: %w", gvk.Kind, namespacedName, err)
+		}
+		var status readiness.Result
+		if len(resourceChecks) > 0 {
+			status = readiness.EvaluateChecks(&obj, resourceChecks)
+		} else {
+			status = readiness.Evaluate(&obj)
+		}
+		description := fmt.Sprintf("%v %v (%v)", gvk.Kind, namespacedName, status.Message)
+		switch status.Status {
+		case readiness.Failed:
+			failed = append(failed, description)
+		case readiness.InProgress:
+			notReady = append(notReady, description)
 		}
 	}
 
-	if len(notReady) > 0 {
+	if len(failed) > 0 {
+		return result.Failed(fmt.Sprintf("%v resources failed: %v", len(failed),
+			strings.Join(failed, ", ")), allOwned), nil
+	} else if len(notReady) > 0 {
 		return result.Waiting(fmt.Sprintf("%v resources not ready: %v", len(notReady),
-			strings.Join(notReadyNames, ",")), allOwned), nil
+			strings.Join(notReady, ", ")), allOwned), nil
 	} else {
 		return result.Ready(fmt.Sprintf("%v manifests reconciled", len(allOwned)), allOwned), nil
 	}
 }
 
-func isReady(readyReplicas int32, specReplicas *int32) bool {
-	return (specReplicas != nil && readyReplicas == *specReplicas) || (specReplicas == nil && readyReplicas > 0)
-}
-
 func (r *Adapter) reconcilePlainManifest(
 	ctx context.Context,
 	pkg ctrlpkg.Package,
 	pi *packagesv1alpha1.PackageInfo,
 	manifest packagesv1alpha1.PlainManifest,
 	patches resourcepatch.TargetPatches,
-) ([]packagesv1alpha1.OwnedResourceRef, error) {
+) ([]packagesv1alpha1.OwnedResourceRef, []packagesv1alpha1.ResourceDrift, error) {
 	log := ctrl.LoggerFrom(ctx)
 	var objectsToApply []client.Object
-	if request, err := r.newManifestRequest(pi, manifest.Url); err != nil {
-		return nil, err
-	} else if unstructured, err := clientutils.FetchResources(request); err != nil {
-		return nil, err
+	if unstructured, err := r.FetchManifestResources(pi, manifest.Url, manifest.Digest); err != nil {
+		return nil, nil, err
 	} else {
 		// Unstructured implements client.Object but we need it as a reference so the interface is fulfilled.
 		objectsToApply = make([]client.Object, len(unstructured))
@@ -135,10 +168,47 @@ func (r *Adapter) reconcilePlainManifest(
 			objectsToApply[i] = &unstructured[i]
 		}
 		log.V(1).Info("fetched manifest resources",
-			"url", request.URL.Redacted(),
+			"url", manifest.Url,
 			"objectCount", len(objectsToApply))
 	}
 
+	if objs, err := r.prepareObjects(ctx, pkg, pi, manifest.DefaultNamespace, objectsToApply, patches); err != nil {
+		return nil, nil, err
+	} else {
+		return r.applyObjects(ctx, pkg, objs, pkg.GetSpec().DriftPolicy)
+	}
+}
+
+// ApplyObjects prepares the given objects for installation as part of pkg and applies them using server-side apply.
+// This includes setting the namespace, owner references and package labels, as well as applying the value patches.
+// If defaultNamespace is empty, the default namespace from the package manifest is used for cluster-scoped packages.
+// Drift detection is not performed for these objects.
+func (r *Adapter) ApplyObjects(
+	ctx context.Context,
+	pkg ctrlpkg.Package,
+	pi *packagesv1alpha1.PackageInfo,
+	defaultNamespace string,
+	objectsToApply []client.Object,
+	patches resourcepatch.TargetPatches,
+) ([]packagesv1alpha1.OwnedResourceRef, error) {
+	if objs, err := r.prepareObjects(ctx, pkg, pi, defaultNamespace, objectsToApply, patches); err != nil {
+		return nil, err
+	} else {
+		owned, _, err := r.applyObjects(ctx, pkg, objs, "")
+		return owned, err
+	}
+}
+
+func (r *Adapter) prepareObjects(
+	ctx context.Context,
+	pkg ctrlpkg.Package,
+	pi *packagesv1alpha1.PackageInfo,
+	defaultNamespace string,
+	objectsToApply []client.Object,
+	patches resourcepatch.TargetPatches,
+) ([]client.Object, error) {
+	log := ctrl.LoggerFrom(ctx)
+
 	if pkg.IsNamespaceScoped() {
 		for _, obj := range objectsToApply {
 			if isNamespaced, err := r.IsObjectNamespaced(obj); err != nil {
@@ -150,8 +220,8 @@ func (r *Adapter) reconcilePlainManifest(
 	} else {
 		// Determine the name of the default namespace. The more specific name takes precedence
 		defaultNamespaceName := pi.Status.Manifest.DefaultNamespace
-		if len(manifest.DefaultNamespace) > 0 {
-			defaultNamespaceName = manifest.DefaultNamespace
+		if len(defaultNamespace) > 0 {
+			defaultNamespaceName = defaultNamespace
 		}
 
 		if len(defaultNamespaceName) > 0 {
@@ -215,24 +285,97 @@ func (r *Adapter) reconcilePlainManifest(
 		}
 	}
 
-	if objs, err := prefixAndUpdateReferences(pkg, pi.Status.Manifest, objectsToApply); err != nil {
-		return nil, err
-	} else {
-		objectsToApply = objs
-	}
+	return prefixAndUpdateReferences(pkg, pi.Status.Manifest, objectsToApply)
+}
 
+// applyObjects applies the given objects using server-side apply.
+// If driftPolicy is not empty, every object is checked for drift first. Objects that are up to date are not applied
+// again and drifted objects are only applied if the policy is DriftCorrect.
+func (r *Adapter) applyObjects(
+	ctx context.Context,
+	pkg ctrlpkg.Package,
+	objectsToApply []client.Object,
+	driftPolicy packagesv1alpha1.DriftPolicy,
+) (_ []packagesv1alpha1.OwnedResourceRef, _ []packagesv1alpha1.ResourceDrift, err error) {
+	ctx, span := tracing.Start(ctx, "apply objects", tracing.AttributeObjectCount.Int(len(objectsToApply)))
+	defer func() { tracing.End(span, err) }()
+
+	log := ctrl.LoggerFrom(ctx)
 	ownedResources := make([]packagesv1alpha1.OwnedResourceRef, 0, len(objectsToApply))
+	var allDrift []packagesv1alpha1.ResourceDrift
 	for _, obj := range objectsToApply {
-		if err := r.Patch(ctx, obj, client.Apply, fieldOwner, client.ForceOwnership); err != nil {
-			return nil, fmt.Errorf("could not apply resource: %w", err)
+		shouldApply := true
+		if driftPolicy != "" {
+			if resourceDrift, changed, err := r.detectDrift(ctx, pkg, obj); err != nil {
+				return nil, nil, err
+			} else if resourceDrift != nil {
+				log.Info("resource drifted", "drift", resourceDrift)
+				allDrift = append(allDrift, *resourceDrift)
+				shouldApply = driftPolicy == packagesv1alpha1.DriftCorrect
+			} else {
+				shouldApply = changed
+			}
+		}
+		if shouldApply {
+			if err := r.Patch(ctx, obj, client.Apply, fieldOwner, client.ForceOwnership); err != nil {
+				return nil, nil, fmt.Errorf("could not apply resource: %w", err)
+			}
+			log.V(1).Info("applied resource",
+				"kind", obj.GetObjectKind().GroupVersionKind(), "namespace", obj.GetNamespace(), "name", obj.GetName())
 		}
-		log.V(1).Info("applied resource",
-			"kind", obj.GetObjectKind().GroupVersionKind(), "namespace", obj.GetNamespace(), "name", obj.GetName())
 		if _, err := ownerutils.AddOwnedResourceRef(r.Scheme(), &ownedResources, obj); err != nil {
-			return nil, err
+			return nil, nil, err
+		}
+	}
+	return ownedResources, allDrift, nil
+}
+
+// detectDrift compares obj with the live resource and returns its drift, if any. Otherwise, the returned bool is true
+// if the desired state of obj has changed since it was last applied.
+// As a side effect, the desired state hash annotation is set on obj.
+func (r *Adapter) detectDrift(
+	ctx context.Context,
+	pkg ctrlpkg.Package,
+	obj client.Object,
+) (*packagesv1alpha1.ResourceDrift, bool, error) {
+	desired, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		// Only the implicit default namespace is not unstructured. There is nothing about it that could drift.
+		return nil, true, nil
+	}
+	hash, err := drift.Hash(desired)
+	if err != nil {
+		return nil, false, err
+	}
+	drift.SetHash(desired, hash)
+
+	ref, err := ownerutils.ToOwnedResourceRef(r.Scheme(), desired)
+	if err != nil {
+		return nil, false, err
+	}
+	var live unstructured.Unstructured
+	live.SetGroupVersionKind(desired.GroupVersionKind())
+	if err := r.Get(ctx, client.ObjectKeyFromObject(desired), &live); apierrors.IsNotFound(err) {
+		// Resources that were already applied before must have been deleted. All others are new.
+		if slices.ContainsFunc(pkg.GetStatus().OwnedResources, func(owned packagesv1alpha1.OwnedResourceRef) bool {
+			return ownerutils.RefersToSameResource(owned, ref)
+		}) {
+			return &packagesv1alpha1.ResourceDrift{OwnedResourceRef: ref, Missing: true}, true, nil
 		}
+		return nil, true, nil
+	} else if err != nil {
+		return nil, false, fmt.Errorf("could not get resource for drift detection: %w", err)
+	} else if drift.GetHash(&live) != hash {
+		return nil, true, nil
+	}
+
+	dryRun := desired.DeepCopy()
+	if err := r.Patch(ctx, dryRun, client.Apply, fieldOwner, client.ForceOwnership, client.DryRunAll); err != nil {
+		return nil, false, fmt.Errorf("could not apply resource for drift detection: %w", err)
+	} else if fields := drift.Compare(dryRun, &live); len(fields) > 0 {
+		return &packagesv1alpha1.ResourceDrift{OwnedResourceRef: ref, Fields: fields}, true, nil
 	}
-	return ownedResources, nil
+	return nil, false, nil
 }
 
 // if the obj kind is Deployment or StatefulSet annotateWithSpecHash sets the AnnotationPackageSpecHashed annotation of the
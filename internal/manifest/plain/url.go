@@ -1,14 +1,65 @@
 package plain
 
 import (
+	"context"
 	"net/http"
 	"net/url"
 
 	packagesv1alpha1 "github.com/glasskube/glasskube/api/v1alpha1"
 	"github.com/glasskube/glasskube/internal/clientutils"
+	repoclient "github.com/glasskube/glasskube/internal/repo/client"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
-func (r *Adapter) newManifestRequest(pi *packagesv1alpha1.PackageInfo, urlOrPath string) (*http.Request, error) {
+// FetchManifestResources fetches all resources from the manifest at urlOrPath.
+// A relative path is resolved relative to the packages "package.yaml" file. If the package repository does not serve
+// files over HTTP, the file is fetched from the repository client instead.
+// If digest is not empty, the contents of the manifest must match it.
+func (r *Adapter) FetchManifestResources(
+	pi *packagesv1alpha1.PackageInfo,
+	urlOrPath string,
+	digest string,
+) ([]unstructured.Unstructured, error) {
+	if parsedUrl, err := url.Parse(urlOrPath); err != nil {
+		return nil, err
+	} else if parsedUrl.Scheme == "" && parsedUrl.Host == "" {
+		repoClient := r.repo.ForRepoWithName(pi.Spec.RepositoryName)
+		if fetcher, ok := repoClient.(repoclient.PackageFileFetcher); ok {
+			if data, err := fetcher.FetchPackageFile(pi.Spec.Name, pi.Status.Version, urlOrPath); err != nil {
+				return nil, err
+			} else {
+				return clientutils.DecodeResourcesWithDigest(data, digest, urlOrPath)
+			}
+		}
+	}
+
+	if request, err := r.NewManifestRequest(pi, urlOrPath); err != nil {
+		return nil, err
+	} else {
+		return clientutils.FetchResourcesWithDigest(request, digest)
+	}
+}
+
+// FetchPackageFile returns the contents of the file at filePath, which is relative to the packages "package.yaml" file.
+// If the package repository does not serve files over HTTP, the file is fetched from the repository client instead.
+func (r *Adapter) FetchPackageFile(
+	ctx context.Context,
+	pi *packagesv1alpha1.PackageInfo,
+	filePath string,
+) ([]byte, error) {
+	repoClient := repoclient.WithContext(ctx, r.repo.ForRepoWithName(pi.Spec.RepositoryName))
+	if fetcher, ok := repoClient.(repoclient.PackageFileFetcher); ok {
+		return fetcher.FetchPackageFile(pi.Spec.Name, pi.Status.Version, filePath)
+	} else if request, err := r.NewManifestRequest(pi, filePath); err != nil {
+		return nil, err
+	} else {
+		return clientutils.FetchFile(request.WithContext(ctx))
+	}
+}
+
+// NewManifestRequest creates a request for the manifest at urlOrPath. A relative path is resolved relative to the
+// packages "package.yaml" file and the request is authenticated for the packages repository.
+func (r *Adapter) NewManifestRequest(pi *packagesv1alpha1.PackageInfo, urlOrPath string) (*http.Request, error) {
 	if parsedUrl, err := url.Parse(urlOrPath); err != nil {
 		return nil, err
 	} else if parsedUrl.Scheme == "" && parsedUrl.Host == "" {
@@ -1,7 +1,13 @@
 package plain
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+
 	"github.com/glasskube/glasskube/api/v1alpha1"
+	"github.com/glasskube/glasskube/internal/clientutils"
 	"github.com/glasskube/glasskube/internal/repo/client/auth"
 	"github.com/glasskube/glasskube/internal/repo/client/fake"
 	. "github.com/onsi/ginkgo/v2"
@@ -16,7 +22,7 @@ var _ = Describe("getActualManifestUrl", func() {
 	})
 
 	It("should handle relative url", func() {
-		result, err := adapter.newManifestRequest(
+		result, err := adapter.NewManifestRequest(
 			&v1alpha1.PackageInfo{Status: v1alpha1.PackageInfoStatus{ResolvedUrl: "http://localhost/packages/foo/package.yaml"}},
 			"./manifest.yaml",
 		)
@@ -24,7 +30,7 @@ var _ = Describe("getActualManifestUrl", func() {
 		Expect(result.URL.String()).To(Equal("http://localhost/packages/foo/manifest.yaml"))
 	})
 	It("should handle plain file name", func() {
-		result, err := adapter.newManifestRequest(
+		result, err := adapter.NewManifestRequest(
 			&v1alpha1.PackageInfo{Status: v1alpha1.PackageInfoStatus{ResolvedUrl: "http://localhost/packages/foo/package.yaml"}},
 			"manifest.yaml",
 		)
@@ -32,7 +38,7 @@ var _ = Describe("getActualManifestUrl", func() {
 		Expect(result.URL.String()).To(Equal("http://localhost/packages/foo/manifest.yaml"))
 	})
 	It("should handle relative url with \"..\"", func() {
-		result, err := adapter.newManifestRequest(
+		result, err := adapter.NewManifestRequest(
 			&v1alpha1.PackageInfo{Status: v1alpha1.PackageInfoStatus{ResolvedUrl: "http://localhost/packages/foo/package.yaml"}},
 			"../manifest.yaml",
 		)
@@ -40,7 +46,7 @@ var _ = Describe("getActualManifestUrl", func() {
 		Expect(result.URL.String()).To(Equal("http://localhost/packages/manifest.yaml"))
 	})
 	It("should handle absolute path", func() {
-		result, err := adapter.newManifestRequest(
+		result, err := adapter.NewManifestRequest(
 			&v1alpha1.PackageInfo{Status: v1alpha1.PackageInfoStatus{ResolvedUrl: "http://localhost/packages/foo/package.yaml"}},
 			"/manifest.yaml",
 		)
@@ -48,7 +54,7 @@ var _ = Describe("getActualManifestUrl", func() {
 		Expect(result.URL.String()).To(Equal("http://localhost/manifest.yaml"))
 	})
 	It("should handle real url", func() {
-		result, err := adapter.newManifestRequest(
+		result, err := adapter.NewManifestRequest(
 			&v1alpha1.PackageInfo{Status: v1alpha1.PackageInfoStatus{ResolvedUrl: "http://localhost/packages/foo/package.yaml"}},
 			"https://github.com/glasskube/glasskube/manifest.yaml",
 		)
@@ -62,7 +68,7 @@ var _ = Describe("getActualManifestUrl", func() {
 		})
 
 		It("should add auth header for relative url", func() {
-			result, err := adapter.newManifestRequest(
+			result, err := adapter.NewManifestRequest(
 				&v1alpha1.PackageInfo{Status: v1alpha1.PackageInfoStatus{ResolvedUrl: "http://localhost/packages/foo/package.yaml"}},
 				"manifest.yaml",
 			)
@@ -75,7 +81,7 @@ var _ = Describe("getActualManifestUrl", func() {
 		})
 
 		It("should NOT add auth header for absolute url", func() {
-			result, err := adapter.newManifestRequest(
+			result, err := adapter.NewManifestRequest(
 				&v1alpha1.PackageInfo{Status: v1alpha1.PackageInfoStatus{ResolvedUrl: "http://localhost/packages/foo/package.yaml"}},
 				"https://github.com/glasskube/glasskube/manifest.yaml",
 			)
@@ -88,3 +94,43 @@ var _ = Describe("getActualManifestUrl", func() {
 		})
 	})
 })
+
+var _ = Describe("FetchManifestResources", func() {
+	const manifest = "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: foo\n"
+	var pi *v1alpha1.PackageInfo
+
+	BeforeEach(func() {
+		adapter.repo = fake.EmptyClientset()
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/yaml")
+			_, _ = w.Write([]byte(manifest))
+		}))
+		DeferCleanup(server.Close)
+		pi = &v1alpha1.PackageInfo{Status: v1alpha1.PackageInfoStatus{ResolvedUrl: server.URL + "/foo/package.yaml"}}
+	})
+
+	It("should apply a manifest without digest", func() {
+		resources, err := adapter.FetchManifestResources(pi, "manifest.yaml", "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resources).To(HaveLen(1))
+	})
+
+	It("should apply a manifest with a matching digest", func() {
+		sum := sha256.Sum256([]byte(manifest))
+		resources, err := adapter.FetchManifestResources(pi, "manifest.yaml", "sha256:"+hex.EncodeToString(sum[:]))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resources).To(HaveLen(1))
+		Expect(resources[0].GetName()).To(Equal("foo"))
+	})
+
+	It("should fail for a manifest with a different digest", func() {
+		sum := sha256.Sum256([]byte("something else"))
+		_, err := adapter.FetchManifestResources(pi, "manifest.yaml", "sha256:"+hex.EncodeToString(sum[:]))
+		Expect(err).To(MatchError(clientutils.ErrDigestMismatch))
+	})
+
+	It("should fail for an unsupported digest", func() {
+		_, err := adapter.FetchManifestResources(pi, "manifest.yaml", "md5:d41d8cd98f00b204e9800998ecf8427e")
+		Expect(err).To(HaveOccurred())
+	})
+})
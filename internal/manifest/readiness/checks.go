@@ -0,0 +1,62 @@
+package readiness
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/glasskube/glasskube/api/v1alpha1"
+	"github.com/glasskube/glasskube/internal/manifesttransformations"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// ChecksFor returns all checks that apply to the resource with the given GroupVersionKind and name.
+func ChecksFor(checks []v1alpha1.ReadinessCheck, gvk schema.GroupVersionKind, name string) []v1alpha1.ReadinessCheck {
+	var result []v1alpha1.ReadinessCheck
+	for _, check := range checks {
+		if gv, err := schema.ParseGroupVersion(check.APIVersion); err == nil && gv.WithKind(check.Kind) == gvk &&
+			(check.Name == "" || check.Name == name) {
+			result = append(result, check)
+		}
+	}
+	return result
+}
+
+// EvaluateChecks evaluates all checks on obj. The result is InProgress if at least one check does not pass and the
+// message contains all checks that did not pass.
+func EvaluateChecks(obj *unstructured.Unstructured, checks []v1alpha1.ReadinessCheck) Result {
+	var pending []string
+	for _, check := range checks {
+		if ok, actual := evaluateCheck(obj, check); !ok {
+			pending = append(pending, describeCheck(check, actual))
+		}
+	}
+	if len(pending) > 0 {
+		return inProgress("pending checks: %v", strings.Join(pending, ", "))
+	}
+	return current()
+}
+
+func evaluateCheck(obj *unstructured.Unstructured, check v1alpha1.ReadinessCheck) (bool, string) {
+	result, err := manifesttransformations.FindSingleResult(check.Path, obj.Object)
+	if err != nil {
+		// a missing field is the most common reason for an error here, e.g. if the status is not yet populated
+		return false, "<none>"
+	}
+	actual := fmt.Sprint(result)
+	if check.Equals != nil {
+		return actual == *check.Equals, actual
+	} else if b, err := strconv.ParseBool(actual); err == nil {
+		return b, actual
+	} else {
+		return result != nil && actual != "", actual
+	}
+}
+
+func describeCheck(check v1alpha1.ReadinessCheck, actual string) string {
+	if check.Equals != nil {
+		return fmt.Sprintf("%v == %q (actual: %q)", check.Path, *check.Equals, actual)
+	}
+	return fmt.Sprintf("%v (actual: %q)", check.Path, actual)
+}
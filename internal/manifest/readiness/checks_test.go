@@ -0,0 +1,48 @@
+package readiness
+
+import (
+	"github.com/glasskube/glasskube/api/v1alpha1"
+	"github.com/glasskube/glasskube/internal/util"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var _ = Describe("ChecksFor", func() {
+	checks := []v1alpha1.ReadinessCheck{
+		{APIVersion: "example.com/v1", Kind: "Database", Path: "{.status.phase}"},
+		{APIVersion: "example.com/v1", Kind: "Database", Name: "main", Path: "{.status.ready}"},
+		{APIVersion: "example.com/v1", Kind: "Cache", Path: "{.status.ready}"},
+	}
+	gvk := schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Database"}
+
+	It("should return checks matching kind and name", func() {
+		Expect(ChecksFor(checks, gvk, "main")).To(ConsistOf(checks[0], checks[1]))
+	})
+	It("should skip checks for other names", func() {
+		Expect(ChecksFor(checks, gvk, "other")).To(ConsistOf(checks[0]))
+	})
+})
+
+var _ = Describe("EvaluateChecks", func() {
+	obj := newUnstructured(`{"apiVersion":"example.com/v1","kind":"Database",
+		"status":{"phase":"Pending","ready":false,"endpoint":"db:5432"}}`)
+
+	It("should pass if all checks pass", func() {
+		Expect(EvaluateChecks(obj, []v1alpha1.ReadinessCheck{
+			{Path: "{.status.phase}", Equals: util.Pointer("Pending")},
+			{Path: "{.status.endpoint}"},
+		}).Status).To(Equal(Current))
+	})
+	It("should list all pending checks", func() {
+		result := EvaluateChecks(obj, []v1alpha1.ReadinessCheck{
+			{Path: "{.status.phase}", Equals: util.Pointer("Running")},
+			{Path: "{.status.ready}"},
+			{Path: "{.status.missing}"},
+			{Path: "{.status.endpoint}"},
+		})
+		Expect(result.Status).To(Equal(InProgress))
+		Expect(result.Message).To(Equal(`pending checks: {.status.phase} == "Running" (actual: "Pending"), ` +
+			`{.status.ready} (actual: "false"), {.status.missing} (actual: "<none>")`))
+	})
+})
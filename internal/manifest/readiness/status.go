@@ -0,0 +1,270 @@
+// Package readiness evaluates whether resources applied by a manifest adapter are ready.
+// The builtin rules follow the conventions of kstatus (sigs.k8s.io/cli-utils/pkg/kstatus): well-known workload kinds
+// are checked by their specific status fields and all other resources by their observedGeneration and conditions.
+package readiness
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+type Status int
+
+const (
+	// Current means that the resource is ready.
+	Current Status = iota
+	// InProgress means that the resource is not ready yet, but is expected to become ready eventually.
+	InProgress
+	// Failed means that the resource is not ready and is not expected to become ready without intervention.
+	Failed
+)
+
+type Result struct {
+	Status  Status
+	Message string
+}
+
+func current() Result {
+	return Result{Status: Current}
+}
+
+func inProgress(format string, a ...any) Result {
+	return Result{Status: InProgress, Message: fmt.Sprintf(format, a...)}
+}
+
+func failed(format string, a ...any) Result {
+	return Result{Status: Failed, Message: fmt.Sprintf(format, a...)}
+}
+
+// statuslessKinds are kinds that do not have a status and are therefore always ready.
+var statuslessKinds = map[schema.GroupKind]struct{}{
+	{Kind: "ConfigMap"}:                                                             {},
+	{Kind: "Secret"}:                                                                {},
+	{Kind: "ServiceAccount"}:                                                        {},
+	{Kind: "LimitRange"}:                                                            {},
+	{Kind: "ResourceQuota"}:                                                         {},
+	{Group: "rbac.authorization.k8s.io", Kind: "Role"}:                              {},
+	{Group: "rbac.authorization.k8s.io", Kind: "RoleBinding"}:                       {},
+	{Group: "rbac.authorization.k8s.io", Kind: "ClusterRole"}:                       {},
+	{Group: "rbac.authorization.k8s.io", Kind: "ClusterRoleBinding"}:                {},
+	{Group: "networking.k8s.io", Kind: "NetworkPolicy"}:                             {},
+	{Group: "admissionregistration.k8s.io", Kind: "ValidatingWebhookConfiguration"}: {},
+	{Group: "admissionregistration.k8s.io", Kind: "MutatingWebhookConfiguration"}:   {},
+	{Group: "scheduling.k8s.io", Kind: "PriorityClass"}:                             {},
+	{Group: "storage.k8s.io", Kind: "StorageClass"}:                                 {},
+}
+
+// HasStatus returns false for kinds that are known to have no status. Resources of these kinds are always ready and
+// don't have to be fetched for evaluation.
+func HasStatus(gk schema.GroupKind) bool {
+	_, ok := statuslessKinds[gk]
+	return !ok
+}
+
+// Evaluate returns the readiness of obj according to the builtin rules.
+func Evaluate(obj *unstructured.Unstructured) Result {
+	if r := checkGeneration(obj); r.Status != Current {
+		return r
+	}
+	gk := obj.GroupVersionKind().GroupKind()
+	switch gk {
+	case schema.GroupKind{Group: "apps", Kind: "Deployment"}:
+		return deploymentStatus(obj)
+	case schema.GroupKind{Group: "apps", Kind: "StatefulSet"}:
+		return statefulSetStatus(obj)
+	case schema.GroupKind{Group: "apps", Kind: "DaemonSet"}:
+		return daemonSetStatus(obj)
+	case schema.GroupKind{Group: "apps", Kind: "ReplicaSet"}:
+		return replicaSetStatus(obj)
+	case schema.GroupKind{Group: "batch", Kind: "Job"}:
+		return jobStatus(obj)
+	case schema.GroupKind{Kind: "Pod"}:
+		return podStatus(obj)
+	case schema.GroupKind{Kind: "PersistentVolumeClaim"}:
+		return phaseStatus(obj, "Bound")
+	case schema.GroupKind{Kind: "Namespace"}:
+		return phaseStatus(obj, "Active")
+	case schema.GroupKind{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"}:
+		return crdStatus(obj)
+	default:
+		return conditionsStatus(obj)
+	}
+}
+
+// checkGeneration returns InProgress if the controller of obj has not yet observed its latest generation.
+func checkGeneration(obj *unstructured.Unstructured) Result {
+	observed, found, err := unstructured.NestedInt64(obj.Object, "status", "observedGeneration")
+	if err == nil && found && observed < obj.GetGeneration() {
+		return inProgress("generation %v not yet observed", obj.GetGeneration())
+	}
+	return current()
+}
+
+func deploymentStatus(obj *unstructured.Unstructured) Result {
+	replicas := specReplicas(obj)
+	if cond, ok := findCondition(obj, "Progressing"); ok && cond.status == "False" &&
+		cond.reason == "ProgressDeadlineExceeded" {
+		return failed("progress deadline exceeded")
+	}
+	if updated := statusInt(obj, "updatedReplicas"); updated < replicas {
+		return inProgress("%v/%v replicas updated", updated, replicas)
+	}
+	if ready := statusInt(obj, "readyReplicas"); ready < replicas {
+		return inProgress("%v/%v replicas ready", ready, replicas)
+	}
+	if available := statusInt(obj, "availableReplicas"); available < replicas {
+		return inProgress("%v/%v replicas available", available, replicas)
+	}
+	if total := statusInt(obj, "replicas"); total > replicas {
+		return inProgress("%v old replicas pending termination", total-replicas)
+	}
+	return current()
+}
+
+func statefulSetStatus(obj *unstructured.Unstructured) Result {
+	replicas := specReplicas(obj)
+	if ready := statusInt(obj, "readyReplicas"); ready < replicas {
+		return inProgress("%v/%v replicas ready", ready, replicas)
+	}
+	strategy, _, _ := unstructured.NestedString(obj.Object, "spec", "updateStrategy", "type")
+	if strategy == "" || strategy == "RollingUpdate" {
+		if updated := statusInt(obj, "updatedReplicas"); updated < replicas {
+			return inProgress("%v/%v replicas updated", updated, replicas)
+		}
+		currentRevision, _, _ := unstructured.NestedString(obj.Object, "status", "currentRevision")
+		updateRevision, _, _ := unstructured.NestedString(obj.Object, "status", "updateRevision")
+		if currentRevision != updateRevision {
+			return inProgress("rolling update to revision %v in progress", updateRevision)
+		}
+	}
+	return current()
+}
+
+func daemonSetStatus(obj *unstructured.Unstructured) Result {
+	desired := statusInt(obj, "desiredNumberScheduled")
+	if updated := statusInt(obj, "updatedNumberScheduled"); updated < desired {
+		return inProgress("%v/%v pods updated", updated, desired)
+	}
+	if ready := statusInt(obj, "numberReady"); ready < desired {
+		return inProgress("%v/%v pods ready", ready, desired)
+	}
+	if available := statusInt(obj, "numberAvailable"); available < desired {
+		return inProgress("%v/%v pods available", available, desired)
+	}
+	return current()
+}
+
+func replicaSetStatus(obj *unstructured.Unstructured) Result {
+	replicas := specReplicas(obj)
+	if ready := statusInt(obj, "readyReplicas"); ready < replicas {
+		return inProgress("%v/%v replicas ready", ready, replicas)
+	}
+	return current()
+}
+
+func jobStatus(obj *unstructured.Unstructured) Result {
+	if cond, ok := findCondition(obj, "Failed"); ok && cond.status == "True" {
+		return failed("job failed: %v", cond.message)
+	}
+	if cond, ok := findCondition(obj, "Complete"); ok && cond.status == "True" {
+		return current()
+	}
+	return inProgress("job not completed")
+}
+
+func podStatus(obj *unstructured.Unstructured) Result {
+	phase, _, _ := unstructured.NestedString(obj.Object, "status", "phase")
+	switch phase {
+	case "Succeeded":
+		return current()
+	case "Failed":
+		return failed("pod failed")
+	case "Running":
+		if cond, ok := findCondition(obj, "Ready"); ok && cond.status == "True" {
+			return current()
+		}
+		return inProgress("pod not ready")
+	default:
+		return inProgress("pod phase is %v", phaseOrUnknown(phase))
+	}
+}
+
+func phaseStatus(obj *unstructured.Unstructured, expected string) Result {
+	if phase, _, _ := unstructured.NestedString(obj.Object, "status", "phase"); phase != expected {
+		return inProgress("phase is %v", phaseOrUnknown(phase))
+	}
+	return current()
+}
+
+func crdStatus(obj *unstructured.Unstructured) Result {
+	if cond, ok := findCondition(obj, "Established"); ok && cond.status == "True" {
+		return current()
+	}
+	return inProgress("not established")
+}
+
+// conditionsStatus evaluates the well-known conditions Ready, Stalled and Reconciling.
+// Resources without any of these conditions are considered ready.
+func conditionsStatus(obj *unstructured.Unstructured) Result {
+	if cond, ok := findCondition(obj, "Stalled"); ok && cond.status == "True" {
+		return failed("stalled: %v", cond.describe())
+	}
+	if cond, ok := findCondition(obj, "Reconciling"); ok && cond.status == "True" {
+		return inProgress("reconciling: %v", cond.describe())
+	}
+	if cond, ok := findCondition(obj, "Ready"); ok && cond.status != "True" {
+		return inProgress("not ready: %v", cond.describe())
+	}
+	return current()
+}
+
+type condition struct {
+	status  string
+	reason  string
+	message string
+}
+
+func (c condition) describe() string {
+	if c.message != "" {
+		return c.message
+	} else if c.reason != "" {
+		return c.reason
+	} else {
+		return "no reason given"
+	}
+}
+
+func findCondition(obj *unstructured.Unstructured, conditionType string) (condition, bool) {
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, c := range conditions {
+		if m, ok := c.(map[string]any); ok && m["type"] == conditionType {
+			status, _ := m["status"].(string)
+			reason, _ := m["reason"].(string)
+			message, _ := m["message"].(string)
+			return condition{status: status, reason: reason, message: strings.TrimSpace(message)}, true
+		}
+	}
+	return condition{}, false
+}
+
+func specReplicas(obj *unstructured.Unstructured) int64 {
+	if replicas, found, err := unstructured.NestedInt64(obj.Object, "spec", "replicas"); err == nil && found {
+		return replicas
+	}
+	return 1
+}
+
+func statusInt(obj *unstructured.Unstructured, field string) int64 {
+	value, _, _ := unstructured.NestedInt64(obj.Object, "status", field)
+	return value
+}
+
+func phaseOrUnknown(phase string) string {
+	if phase == "" {
+		return "unknown"
+	}
+	return phase
+}
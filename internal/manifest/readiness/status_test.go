@@ -0,0 +1,90 @@
+package readiness
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func newUnstructured(s string) *unstructured.Unstructured {
+	var u unstructured.Unstructured
+	Expect(u.UnmarshalJSON([]byte(s))).To(Succeed())
+	return &u
+}
+
+var _ = Describe("Evaluate", func() {
+	DescribeTable("should evaluate builtin rules",
+		func(obj string, expected Status) {
+			Expect(Evaluate(newUnstructured(obj)).Status).To(Equal(expected))
+		},
+		Entry("ready Deployment", `{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"generation":1},
+			"spec":{"replicas":2},
+			"status":{"observedGeneration":1,"replicas":2,"updatedReplicas":2,"readyReplicas":2,"availableReplicas":2}}`,
+			Current),
+		Entry("Deployment with unobserved generation", `{"apiVersion":"apps/v1","kind":"Deployment",
+			"metadata":{"generation":2},"spec":{"replicas":1},
+			"status":{"observedGeneration":1,"replicas":1,"updatedReplicas":1,"readyReplicas":1,"availableReplicas":1}}`,
+			InProgress),
+		Entry("Deployment with unready replicas", `{"apiVersion":"apps/v1","kind":"Deployment",
+			"status":{"replicas":1,"updatedReplicas":1,"readyReplicas":0}}`,
+			InProgress),
+		Entry("Deployment with exceeded progress deadline", `{"apiVersion":"apps/v1","kind":"Deployment",
+			"status":{"conditions":[{"type":"Progressing","status":"False","reason":"ProgressDeadlineExceeded"}]}}`,
+			Failed),
+		Entry("ready DaemonSet", `{"apiVersion":"apps/v1","kind":"DaemonSet",
+			"status":{"desiredNumberScheduled":3,"updatedNumberScheduled":3,"numberReady":3,"numberAvailable":3}}`,
+			Current),
+		Entry("unready DaemonSet", `{"apiVersion":"apps/v1","kind":"DaemonSet",
+			"status":{"desiredNumberScheduled":3,"updatedNumberScheduled":3,"numberReady":2,"numberAvailable":2}}`,
+			InProgress),
+		Entry("StatefulSet during rolling update", `{"apiVersion":"apps/v1","kind":"StatefulSet","spec":{"replicas":1},
+			"status":{"readyReplicas":1,"updatedReplicas":1,"currentRevision":"a","updateRevision":"b"}}`,
+			InProgress),
+		Entry("completed Job", `{"apiVersion":"batch/v1","kind":"Job",
+			"status":{"conditions":[{"type":"Complete","status":"True"}]}}`,
+			Current),
+		Entry("running Job", `{"apiVersion":"batch/v1","kind":"Job","status":{"active":1}}`,
+			InProgress),
+		Entry("failed Job", `{"apiVersion":"batch/v1","kind":"Job",
+			"status":{"conditions":[{"type":"Failed","status":"True","message":"BackoffLimitExceeded"}]}}`,
+			Failed),
+		Entry("bound PVC", `{"apiVersion":"v1","kind":"PersistentVolumeClaim","status":{"phase":"Bound"}}`,
+			Current),
+		Entry("pending PVC", `{"apiVersion":"v1","kind":"PersistentVolumeClaim","status":{"phase":"Pending"}}`,
+			InProgress),
+		// Clusters without a load balancer provider never provision an ingress. Packages that depend on it can use a
+		// readinessCheck for ".status.loadBalancer.ingress".
+		Entry("LoadBalancer Service without ingress", `{"apiVersion":"v1","kind":"Service",
+			"spec":{"type":"LoadBalancer"},"status":{"loadBalancer":{}}}`,
+			Current),
+		Entry("ClusterIP Service", `{"apiVersion":"v1","kind":"Service","spec":{"type":"ClusterIP"}}`,
+			Current),
+		Entry("established CRD", `{"apiVersion":"apiextensions.k8s.io/v1","kind":"CustomResourceDefinition",
+			"status":{"conditions":[{"type":"Established","status":"True"}]}}`,
+			Current),
+		Entry("custom resource without status", `{"apiVersion":"example.com/v1","kind":"Foo"}`,
+			Current),
+		Entry("custom resource with Ready=False", `{"apiVersion":"example.com/v1","kind":"Foo",
+			"status":{"conditions":[{"type":"Ready","status":"False","message":"waiting for database"}]}}`,
+			InProgress),
+		Entry("stalled custom resource", `{"apiVersion":"example.com/v1","kind":"Foo",
+			"status":{"conditions":[{"type":"Stalled","status":"True"}]}}`,
+			Failed),
+	)
+
+	It("should include a message for resources that are not ready", func() {
+		result := Evaluate(newUnstructured(`{"apiVersion":"apps/v1","kind":"Deployment","spec":{"replicas":3},
+			"status":{"updatedReplicas":3,"readyReplicas":1}}`))
+		Expect(result.Message).To(Equal("1/3 replicas ready"))
+	})
+})
+
+var _ = Describe("HasStatus", func() {
+	It("should be false for ConfigMaps", func() {
+		Expect(HasStatus(schema.GroupKind{Kind: "ConfigMap"})).To(BeFalse())
+	})
+	It("should be true for Deployments", func() {
+		Expect(HasStatus(schema.GroupKind{Group: "apps", Kind: "Deployment"})).To(BeTrue())
+	})
+})
@@ -14,6 +14,8 @@ type ReconcileResult struct {
 	kind           resultKind
 	Message        string
 	OwnedResources []v1alpha1.OwnedResourceRef
+	// Drift contains the resources that were found to differ from their desired state, if drift detection is enabled.
+	Drift []v1alpha1.ResourceDrift
 }
 
 func Ready(message string, ownedResources []v1alpha1.OwnedResourceRef) *ReconcileResult {
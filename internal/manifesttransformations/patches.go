@@ -2,6 +2,7 @@ package manifesttransformations
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/glasskube/glasskube/api/v1alpha1"
 	"github.com/glasskube/glasskube/internal/controller/ctrlpkg"
@@ -13,7 +14,7 @@ func GeneratePatches(tr v1alpha1.TransformationDefinition, resolvedValue any) ([
 	result := make([]resourcepatch.TargetPatch, len(tr.Targets))
 	for i, t := range tr.Targets {
 		if patch, err := resourcepatch.GenerateTargetPatch(t, resolvedValue); err != nil {
-			return nil, err
+			return nil, fmt.Errorf("transformation of %v: %w", tr.Source.Path, err)
 		} else {
 			result[i] = *patch
 		}
@@ -64,11 +64,6 @@ func (resolver *SourceResolver) Resolve(
 	pkg ctrlpkg.Package,
 	source v1alpha1.TransformationSource,
 ) (any, error) {
-	jp := jsonpath.New("")
-	if err := jp.Parse(source.Path); err != nil {
-		return nil, err
-	}
-
 	var resource *unstructured.Unstructured
 	if source.Resource != nil {
 		ref := refWithNamespace(*source.Resource, pkg.GetNamespace())
@@ -86,7 +81,16 @@ func (resolver *SourceResolver) Resolve(
 		}
 	}
 
-	if results, err := jp.FindResults(resource.UnstructuredContent()); err != nil {
+	return FindSingleResult(source.Path, resource.UnstructuredContent())
+}
+
+// FindSingleResult evaluates the JSONPath expression path on obj.
+// It is an error if the expression does not produce exactly one result.
+func FindSingleResult(path string, obj map[string]any) (any, error) {
+	jp := jsonpath.New("")
+	if err := jp.Parse(path); err != nil {
+		return nil, err
+	} else if results, err := jp.FindResults(obj); err != nil {
 		return nil, err
 	} else if len(results) != 1 {
 		return nil, fmt.Errorf("jsonpath produced unexpected number of results (%v)", len(results))
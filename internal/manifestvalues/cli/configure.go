@@ -11,7 +11,6 @@ import (
 	"github.com/glasskube/glasskube/api/v1alpha1"
 	"github.com/glasskube/glasskube/internal/cliutils"
 	"github.com/glasskube/glasskube/internal/manifestvalues"
-	"github.com/glasskube/glasskube/internal/maputils"
 	"github.com/glasskube/glasskube/internal/util"
 	"github.com/yuin/goldmark"
 	"github.com/yuin/goldmark/extension"
@@ -26,7 +25,8 @@ var (
 	configMapStr        = "ConfigMap"
 	secretStr           = "Secret"
 	packageStr          = "Package"
-	referenceValueKinds = []string{configMapStr, secretStr, packageStr}
+	resourceStr         = "Resource"
+	referenceValueKinds = []string{configMapStr, secretStr, packageStr, resourceStr}
 )
 
 var (
@@ -72,14 +72,21 @@ func Configure(
 	//  so even if we would use an alternative map implementation that preserves the
 	//  order of keys, they would still be different from the original.
 	//  Related issue: https://github.com/kubernetes-sigs/yaml/issues/88
-	for i, name := range maputils.KeysSorted(manifest.ValueDefinitions) {
+	// Values are ordered such that conditions of a value can be evaluated before it is configured.
+	for i, name := range manifestvalues.OrderedNames(manifest) {
 		def := manifest.ValueDefinitions[name]
 		var oldValuePtr *v1alpha1.ValueConfiguration
 		if oldValue, ok := options.oldValues[name]; ok {
 			oldValuePtr = &oldValue
 		}
-		if options.ShouldUseDefault(name, def) {
-			fmt.Fprintf(os.Stderr, "Using default value for %v: %v\n", name, def.DefaultValue)
+		if !manifestvalues.IsApplicable(manifest, name, newValues) {
+			fmt.Fprintln(os.Stderr, faint("Skipping %v because it does not apply to this configuration", name))
+		} else if options.ShouldUseDefault(name, def) {
+			if def.Type == v1alpha1.ValueTypeSecret {
+				fmt.Fprintf(os.Stderr, "Using default value for %v\n", name)
+			} else {
+				fmt.Fprintf(os.Stderr, "Using default value for %v: %v\n", name, def.DefaultValue)
+			}
 			newValues[name] = v1alpha1.ValueConfiguration{
 				InlineValueConfiguration: v1alpha1.InlineValueConfiguration{Value: util.Pointer(def.DefaultValue)},
 			}
@@ -107,7 +114,7 @@ func ConfigureSingle(
 		printHeader(name, def)
 
 		if oldValue != nil {
-			fmt.Fprintln(os.Stderr, "Old value:", manifestvalues.ValueAsString(*oldValue))
+			fmt.Fprintln(os.Stderr, "Old value:", valueAsString(def, *oldValue))
 			if cliutils.YesNoPrompt("Keep?", true) {
 				return oldValue, nil
 			}
@@ -118,7 +125,11 @@ func ConfigureSingle(
 
 		useDefault := len(def.DefaultValue) > 0
 		if useDefault {
-			fmt.Fprintln(os.Stderr, "Default:", def.DefaultValue)
+			if def.Type == v1alpha1.ValueTypeSecret {
+				fmt.Fprintln(os.Stderr, "Default:", manifestvalues.RedactedValue)
+			} else {
+				fmt.Fprintln(os.Stderr, "Default:", def.DefaultValue)
+			}
 			useDefault = cliutils.YesNoPrompt("Use default?", true)
 		}
 
@@ -198,6 +209,36 @@ func getLiteralValue(name string, def v1alpha1.ValueDefinition) (*string, error)
 		} else {
 			return &v, nil
 		}
+	case v1alpha1.ValueTypeList:
+		fmt.Fprintln(os.Stderr, "Please enter the items, one per line (enter an empty line to finish):")
+		var items []string
+		for {
+			if item := getInput(manifestvalues.ItemDefinition(def).Type); item == "" {
+				break
+			} else {
+				items = append(items, item)
+			}
+		}
+		v := manifestvalues.FormatList(items)
+		return &v, nil
+	case v1alpha1.ValueTypeMap:
+		fmt.Fprintln(os.Stderr, "Please enter the items as key=value, one per line (enter an empty line to finish):")
+		items := make(map[string]string)
+		for {
+			if item := getInput(manifestvalues.ItemDefinition(def).Type); item == "" {
+				break
+			} else if key, value, ok := strings.Cut(item, "="); !ok {
+				return nil, fmt.Errorf("invalid item %v: expected key=value", item)
+			} else {
+				items[key] = value
+			}
+		}
+		v := manifestvalues.FormatMap(items)
+		return &v, nil
+	case v1alpha1.ValueTypeSecret:
+		fmt.Fprintln(os.Stderr, "Please enter a value (input is hidden):")
+		v := cliutils.GetSecretInputStr(string(def.Type))
+		return &v, nil
 	default:
 		fmt.Fprintln(os.Stderr, "Please enter a value:")
 		v := getInput(def.Type)
@@ -205,6 +246,14 @@ func getLiteralValue(name string, def v1alpha1.ValueDefinition) (*string, error)
 	}
 }
 
+// valueAsString returns value as string, but hides inline secret values.
+func valueAsString(def v1alpha1.ValueDefinition, value v1alpha1.ValueConfiguration) string {
+	if def.Type == v1alpha1.ValueTypeSecret && value.Value != nil {
+		return manifestvalues.RedactedValue
+	}
+	return manifestvalues.ValueAsString(value)
+}
+
 func getReferenceValue() (*v1alpha1.ValueReference, error) {
 	if opt, err := getOption(referenceValueKinds); err != nil {
 		return nil, err
@@ -219,6 +268,10 @@ func getReferenceValue() (*v1alpha1.ValueReference, error) {
 		case packageStr:
 			fmt.Fprintln(os.Stderr, "Specify the name and value of the Package")
 			return &v1alpha1.ValueReference{PackageRef: getPackageValueSource()}, nil
+		case resourceStr:
+			fmt.Fprintln(os.Stderr, "Specify the apiVersion, kind, namespace (empty for cluster-scoped resources), "+
+				"name and JSONPath (e.g. {.spec.clusterIP}) of the resource field")
+			return &v1alpha1.ValueReference{ResourceRef: getResourceValueSource()}, nil
 		default:
 			return nil, fmt.Errorf("invalid option: %v (this is a bug)", opt)
 		}
@@ -240,6 +293,16 @@ func getPackageValueSource() *v1alpha1.PackageValueSource {
 	return &ref
 }
 
+func getResourceValueSource() *v1alpha1.ResourceValueSource {
+	var ref v1alpha1.ResourceValueSource
+	ref.APIVersion = cliutils.GetInputStr("apiVersion")
+	ref.Kind = cliutils.GetInputStr("kind")
+	ref.Namespace = cliutils.GetInputStr("namespace")
+	ref.Name = cliutils.GetInputStr("name")
+	ref.Path = cliutils.GetInputStr("path")
+	return &ref
+}
+
 func getInput(t v1alpha1.ValueType) string {
 	return cliutils.GetInputStr(string(t))
 }
@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/glasskube/glasskube/api/v1alpha1"
+	"github.com/glasskube/glasskube/internal/manifestvalues"
 	"github.com/glasskube/glasskube/internal/util"
 	"github.com/spf13/cobra"
 )
@@ -45,7 +46,12 @@ func (opts *ValuesOptions) AddFlagsToCommand(cmd *cobra.Command) {
 			"For example:\n"+
 			" * Reference a ConfigMap key: --value \"name=$ConfigMapRef$namespace,name,key\"\n"+
 			" * Reference a Secret key: --value \"name=$SecretRef$namespace,name,key\"\n"+
-			" * Reference another Package value: --value \"name=$PackageRef$name,value\"\n")
+			" * Reference another Package value: --value \"name=$PackageRef$name,value\"\n"+
+			" * Reference a field of any resource: --value \"name=$ResourceRef$apiVersion,kind,namespace,name,jsonpath\"\n"+
+			"Items of list and map values can be set with the following syntax, which replaces the whole value:\n"+
+			" * Add an item to a list: --value \"name[]=item\"\n"+
+			" * Set an item of a map: --value \"name[key]=item\"\n"+
+			"Alternatively, a list or map value can be set as JSON: --value 'name=[\"item\"]'\n")
 	if opts.KeepOldValuesDefault != nil {
 		flags.BoolVar(&opts.KeepOldValues, "keep-old-values", *opts.KeepOldValuesDefault,
 			"Set this to false in order to erase any values not specified via --value")
@@ -72,6 +78,8 @@ func (opts *ValuesOptions) ParseValues(
 	if opts.KeepOldValues {
 		maps.Copy(newValues, oldValues)
 	}
+	listItems := make(map[string][]string)
+	mapItems := make(map[string]map[string]string)
 	for _, s := range opts.Values {
 		split := strings.SplitN(s, "=", 2)
 		if len(split) != 2 {
@@ -79,6 +87,27 @@ func (opts *ValuesOptions) ParseValues(
 		}
 		key, value := split[0], split[1]
 
+		if name, itemKey, ok := parseItemKey(key); ok {
+			switch manifest.ValueDefinitions[name].Type {
+			case v1alpha1.ValueTypeList:
+				if itemKey != "" {
+					return nil, fmt.Errorf("value %v is a list, items must be added with %v[]", name, name)
+				}
+				listItems[name] = append(listItems[name], value)
+			case v1alpha1.ValueTypeMap:
+				if itemKey == "" {
+					return nil, fmt.Errorf("value %v is a map, items must be set with %v[<key>]", name, name)
+				}
+				if mapItems[name] == nil {
+					mapItems[name] = make(map[string]string)
+				}
+				mapItems[name][itemKey] = value
+			default:
+				return nil, fmt.Errorf("value %v is neither a list nor a map", name)
+			}
+			continue
+		}
+
 		var valueConfiguration v1alpha1.ValueConfiguration
 		if strings.HasPrefix(value, "$ConfigMapRef$") {
 			if source, err := parseObjectKeyValueSource(value, "$ConfigMapRef$"); err != nil {
@@ -98,14 +127,38 @@ func (opts *ValuesOptions) ParseValues(
 			} else {
 				valueConfiguration.ValueFrom = &v1alpha1.ValueReference{PackageRef: source}
 			}
+		} else if strings.HasPrefix(value, "$ResourceRef$") {
+			if source, err := parseResourceValueSource(value); err != nil {
+				return nil, fmt.Errorf("value %v is invalid: %v", key, err)
+			} else {
+				valueConfiguration.ValueFrom = &v1alpha1.ValueReference{ResourceRef: source}
+			}
 		} else {
 			valueConfiguration.Value = &value
 		}
 		newValues[key] = valueConfiguration
 	}
+	for name, items := range listItems {
+		newValues[name] = v1alpha1.ValueConfiguration{
+			InlineValueConfiguration: v1alpha1.InlineValueConfiguration{Value: util.Pointer(manifestvalues.FormatList(items))},
+		}
+	}
+	for name, items := range mapItems {
+		newValues[name] = v1alpha1.ValueConfiguration{
+			InlineValueConfiguration: v1alpha1.InlineValueConfiguration{Value: util.Pointer(manifestvalues.FormatMap(items))},
+		}
+	}
 	return newValues, nil
 }
 
+// parseItemKey splits a key with the format "name[item]" into name and item.
+func parseItemKey(key string) (string, string, bool) {
+	if name, rest, ok := strings.Cut(key, "["); ok && strings.HasSuffix(rest, "]") {
+		return name, strings.TrimSuffix(rest, "]"), true
+	}
+	return "", "", false
+}
+
 func parseObjectKeyValueSource(value, prefix string) (*v1alpha1.ObjectKeyValueSource, error) {
 	if parts, err := parseSourceParts(value, prefix, 3); err != nil {
 		return nil, err
@@ -122,6 +175,20 @@ func parsePackageValueSource(value string) (*v1alpha1.PackageValueSource, error)
 	}
 }
 
+func parseResourceValueSource(value string) (*v1alpha1.ResourceValueSource, error) {
+	if parts, err := parseSourceParts(value, "$ResourceRef$", 5); err != nil {
+		return nil, err
+	} else {
+		return &v1alpha1.ResourceValueSource{
+			APIVersion: parts[0],
+			Kind:       parts[1],
+			Namespace:  parts[2],
+			Name:       parts[3],
+			Path:       parts[4],
+		}, nil
+	}
+}
+
 func parseSourceParts(value, prefix string, n int) ([]string, error) {
 	if parts := strings.SplitN(strings.TrimPrefix(value, prefix), ",", n); len(parts) != n {
 		return nil, fmt.Errorf("%v requires %v parameters, got %v", prefix, n, len(parts))
@@ -70,6 +70,20 @@ var _ = Describe("ParseValues", func() {
 			}),
 
 		Entry("when there is an invalid PackageRef (too few args)", false, []string{"foo=$PackageRef$foo"}, nil, true, nil),
+
+		Entry("when there is a valid ResourceRef", false,
+			[]string{"foo=$ResourceRef$networking.k8s.io/v1,Ingress,bar,baz,{.spec.rules[0].host}"}, nil, false,
+			map[string]v1alpha1.ValueConfiguration{
+				"foo": {ValueFrom: &v1alpha1.ValueReference{ResourceRef: &v1alpha1.ResourceValueSource{
+					APIVersion: "networking.k8s.io/v1",
+					Kind:       "Ingress",
+					Namespace:  "bar",
+					Name:       "baz",
+					Path:       "{.spec.rules[0].host}",
+				}}},
+			}),
+
+		Entry("when there is an invalid ResourceRef (too few args)", false, []string{"foo=$ResourceRef$v1,Service,foo"}, nil, true, nil),
 	)
 	It("should handle defaults", func() {
 		opts.KeepOldValues = false
@@ -109,4 +123,35 @@ var _ = Describe("ParseValues", func() {
 		}
 		Expect(newValues).To(Equal(expectedResult))
 	})
+	Describe("list and map items", func() {
+		manifest := &v1alpha1.PackageManifest{
+			ValueDefinitions: map[string]v1alpha1.ValueDefinition{
+				"hosts":  {Type: v1alpha1.ValueTypeList},
+				"labels": {Type: v1alpha1.ValueTypeMap},
+				"name":   {Type: v1alpha1.ValueTypeText},
+			},
+		}
+		It("should collect list and map items", func() {
+			opts.Values = []string{"hosts[]=a", "labels[team]=x", "hosts[]=b", "labels[tier]=y"}
+			newValues, err := opts.ParseValues(manifest, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(newValues).To(Equal(map[string]v1alpha1.ValueConfiguration{
+				"hosts": {InlineValueConfiguration: v1alpha1.InlineValueConfiguration{
+					Value: util.Pointer(`["a","b"]`)}},
+				"labels": {InlineValueConfiguration: v1alpha1.InlineValueConfiguration{
+					Value: util.Pointer(`{"team":"x","tier":"y"}`)}},
+			}))
+		})
+		DescribeTable("should reject invalid item keys",
+			func(value string) {
+				opts.Values = []string{value}
+				_, err := opts.ParseValues(manifest, nil)
+				Expect(err).To(HaveOccurred())
+			},
+			Entry("when list item has a key", "hosts[a]=b"),
+			Entry("when map item has no key", "labels[]=b"),
+			Entry("when value is text", "name[]=b"),
+			Entry("when value has no definition", "other[]=b"),
+		)
+	})
 })
@@ -0,0 +1,97 @@
+package manifestvalues
+
+import (
+	"slices"
+	"strconv"
+
+	"github.com/glasskube/glasskube/api/v1alpha1"
+	"github.com/glasskube/glasskube/internal/maputils"
+)
+
+// IsApplicable returns false if the value definition with the given name has a condition that does not hold for
+// values. Conditions that depend on reference values can not be evaluated without resolving them, so in this case
+// the value definition is considered applicable.
+func IsApplicable(
+	manifest v1alpha1.PackageManifest,
+	name string,
+	values map[string]v1alpha1.ValueConfiguration,
+) bool {
+	holds, known := conditionHolds(manifest, name, targetsForValues(values), nil)
+	return holds || !known
+}
+
+// ApplicableValues returns all resolved values whose value definitions are applicable.
+func ApplicableValues(manifest v1alpha1.PackageManifest, values map[string]string) map[string]string {
+	targets := targetsForResolvedValues(values)
+	result := make(map[string]string, len(values))
+	for name, value := range values {
+		if holds, _ := conditionHolds(manifest, name, targets, nil); holds {
+			result[name] = value
+		}
+	}
+	return result
+}
+
+// OrderedNames returns the names of all value definitions of manifest, sorted by name, except that each value
+// definition comes after the value definition its condition depends on.
+func OrderedNames(manifest v1alpha1.PackageManifest) []string {
+	result := make([]string, 0, len(manifest.ValueDefinitions))
+	var add func(name string, visiting []string)
+	add = func(name string, visiting []string) {
+		if slices.Contains(result, name) || slices.Contains(visiting, name) {
+			return
+		}
+		if def, ok := manifest.ValueDefinitions[name]; !ok {
+			return
+		} else if def.When != nil {
+			add(def.When.Value, append(visiting, name))
+		}
+		result = append(result, name)
+	}
+	for _, name := range maputils.KeysSorted(manifest.ValueDefinitions) {
+		add(name, nil)
+	}
+	return result
+}
+
+// conditionHolds evaluates the condition of the value definition with the given name. A condition only holds if
+// the value definition it depends on is applicable as well.
+// known is false if the condition depends on a value that can not be evaluated.
+func conditionHolds(
+	manifest v1alpha1.PackageManifest,
+	name string,
+	values map[string]validationTarget,
+	visiting []string,
+) (holds bool, known bool) {
+	def, ok := manifest.ValueDefinitions[name]
+	if !ok || def.When == nil {
+		return true, true
+	} else if slices.Contains(visiting, name) {
+		// conditions must not be cyclic
+		return false, true
+	} else if _, ok := manifest.ValueDefinitions[def.When.Value]; !ok {
+		return false, true
+	}
+	if depHolds, depKnown := conditionHolds(manifest, def.When.Value, values, append(visiting, name)); !depHolds {
+		return false, depKnown
+	}
+	if target, ok := values[def.When.Value]; !ok {
+		return conditionMatches(def.When, "", false), true
+	} else if target.Skip() {
+		return false, false
+	} else {
+		return conditionMatches(def.When, target.Get(), true), true
+	}
+}
+
+func conditionMatches(condition *v1alpha1.ValueCondition, value string, isSet bool) bool {
+	if condition.Equals != nil {
+		return isSet && value == *condition.Equals
+	} else if condition.In != nil {
+		return isSet && slices.Contains(condition.In, value)
+	} else if b, err := strconv.ParseBool(value); err == nil {
+		return isSet && b
+	} else {
+		return isSet && value != "" && value != "[]" && value != "{}"
+	}
+}
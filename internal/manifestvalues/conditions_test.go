@@ -0,0 +1,88 @@
+package manifestvalues
+
+import (
+	"github.com/glasskube/glasskube/api/v1alpha1"
+	"github.com/glasskube/glasskube/internal/util"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("conditions", func() {
+	manifest := v1alpha1.PackageManifest{
+		ValueDefinitions: map[string]v1alpha1.ValueDefinition{
+			"tls": {Type: v1alpha1.ValueTypeBoolean},
+			"cert": {
+				Type:        v1alpha1.ValueTypeText,
+				Constraints: v1alpha1.ValueDefinitionConstraints{Required: true},
+				When:        &v1alpha1.ValueCondition{Value: "tls"},
+			},
+			"acmeEmail": {
+				Type:        v1alpha1.ValueTypeText,
+				Constraints: v1alpha1.ValueDefinitionConstraints{Required: true},
+				When:        &v1alpha1.ValueCondition{Value: "issuer", Equals: util.Pointer("acme")},
+			},
+			"issuer": {
+				Type:    v1alpha1.ValueTypeOptions,
+				Options: []string{"acme", "custom"},
+				When:    &v1alpha1.ValueCondition{Value: "tls"},
+			},
+			"mode": {
+				Type: v1alpha1.ValueTypeText,
+				When: &v1alpha1.ValueCondition{Value: "issuer", In: []string{"custom"}},
+			},
+		},
+	}
+	inline := func(value string) v1alpha1.ValueConfiguration {
+		return v1alpha1.ValueConfiguration{InlineValueConfiguration: v1alpha1.InlineValueConfiguration{Value: &value}}
+	}
+
+	DescribeTable("IsApplicable",
+		func(name string, values map[string]v1alpha1.ValueConfiguration, expected bool) {
+			Expect(IsApplicable(manifest, name, values)).To(Equal(expected))
+		},
+		Entry("without condition", "tls", nil, true),
+		Entry("when boolean is true", "cert", map[string]v1alpha1.ValueConfiguration{"tls": inline("true")}, true),
+		Entry("when boolean is false", "cert", map[string]v1alpha1.ValueConfiguration{"tls": inline("false")}, false),
+		Entry("when value is missing", "cert", map[string]v1alpha1.ValueConfiguration{}, false),
+		Entry("when equals matches", "acmeEmail",
+			map[string]v1alpha1.ValueConfiguration{"tls": inline("true"), "issuer": inline("acme")}, true),
+		Entry("when equals does not match", "acmeEmail",
+			map[string]v1alpha1.ValueConfiguration{"tls": inline("true"), "issuer": inline("custom")}, false),
+		Entry("when in matches", "mode",
+			map[string]v1alpha1.ValueConfiguration{"tls": inline("true"), "issuer": inline("custom")}, true),
+		Entry("when dependency does not apply", "acmeEmail",
+			map[string]v1alpha1.ValueConfiguration{"tls": inline("false"), "issuer": inline("acme")}, false),
+		Entry("when dependency is a reference", "cert",
+			map[string]v1alpha1.ValueConfiguration{"tls": {ValueFrom: &v1alpha1.ValueReference{
+				ConfigMapRef: &v1alpha1.ObjectKeyValueSource{Name: "a", Namespace: "b", Key: "c"}}}}, true),
+	)
+
+	It("should skip values that do not apply in validation", func() {
+		Expect(ValidateResolvedValues(manifest, map[string]string{"tls": "false"})).To(Succeed())
+		Expect(ValidateResolvedValues(manifest, map[string]string{"tls": "true"})).NotTo(Succeed())
+		Expect(ValidateResolvedValues(manifest,
+			map[string]string{"tls": "true", "cert": "x", "issuer": "custom"})).To(Succeed())
+		Expect(ValidateResolvedValues(manifest,
+			map[string]string{"tls": "true", "cert": "x", "issuer": "acme"})).NotTo(Succeed())
+	})
+
+	It("should return only applicable values", func() {
+		Expect(ApplicableValues(manifest, map[string]string{"tls": "false", "cert": "x", "issuer": "acme"})).
+			To(Equal(map[string]string{"tls": "false"}))
+	})
+
+	It("should order names by condition", func() {
+		Expect(OrderedNames(manifest)).To(Equal([]string{"tls", "issuer", "acmeEmail", "cert", "mode"}))
+	})
+
+	It("should not loop on cyclic conditions", func() {
+		cyclic := v1alpha1.PackageManifest{
+			ValueDefinitions: map[string]v1alpha1.ValueDefinition{
+				"a": {Type: v1alpha1.ValueTypeText, When: &v1alpha1.ValueCondition{Value: "b"}},
+				"b": {Type: v1alpha1.ValueTypeText, When: &v1alpha1.ValueCondition{Value: "a"}},
+			},
+		}
+		Expect(IsApplicable(cyclic, "a", map[string]v1alpha1.ValueConfiguration{"b": inline("x")})).To(BeFalse())
+		Expect(OrderedNames(cyclic)).To(ConsistOf("a", "b"))
+	})
+})
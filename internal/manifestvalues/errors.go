@@ -51,6 +51,11 @@ func NewPackageRefError(source v1alpha1.PackageValueSource, cause error) error {
 	return fmt.Errorf("cannot resolve reference to value %v in Package %v: %w", source.Value, source.Name, cause)
 }
 
+func NewResourceRefError(source v1alpha1.ResourceValueSource, cause error) error {
+	return fmt.Errorf("cannot resolve path %v in %v %v.%v: %w",
+		source.Path, source.Kind, source.Name, source.Namespace, cause)
+}
+
 func NewOptionsError(options []string) error {
 	return fmt.Errorf("value must be one of: %v", strings.Join(options, ", "))
 }
@@ -71,4 +76,6 @@ var (
 	ErrConstraintMax       = fmt.Errorf("%w: Max", ErrConstraint)
 	ErrConstraintMinLength = fmt.Errorf("%w: MinLength", ErrConstraint)
 	ErrConstraintMaxLength = fmt.Errorf("%w: MaxLength", ErrConstraint)
+	ErrConstraintMinItems  = fmt.Errorf("%w: MinItems", ErrConstraint)
+	ErrConstraintMaxItems  = fmt.Errorf("%w: MaxItems", ErrConstraint)
 )
@@ -0,0 +1,46 @@
+package manifestvalues
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/glasskube/glasskube/api/v1alpha1"
+)
+
+// Hash returns a hex encoded SHA-256 hash of the given resolved values.
+// The result does not depend on the order of the values.
+//
+// Because the hash is published in the package status and in events, secret values are not part of it: Values that
+// have the type v1alpha1.ValueTypeSecret or are resolved from a Secret are replaced with the reference they are
+// resolved from, or with RedactedValue if they are inline. Changes of secret values are therefore only reflected in
+// the hash if the reference changes, as it does for secret values stored by glasskube.
+func Hash(
+	manifest *v1alpha1.PackageManifest,
+	values map[string]v1alpha1.ValueConfiguration,
+	resolved map[string]string,
+) (string, error) {
+	hashed := make(map[string]string, len(resolved))
+	for name, value := range resolved {
+		configuration := values[name]
+		def, ok := manifest.ValueDefinitions[name]
+		if (ok && def.Type == v1alpha1.ValueTypeSecret) ||
+			(configuration.ValueFrom != nil && configuration.ValueFrom.SecretRef != nil) {
+			if configuration.ValueFrom == nil {
+				value = RedactedValue
+			} else if ref, err := json.Marshal(configuration.ValueFrom); err != nil {
+				return "", err
+			} else {
+				value = string(ref)
+			}
+		}
+		hashed[name] = value
+	}
+	// json.Marshal sorts map keys, so the encoding is deterministic
+	if data, err := json.Marshal(hashed); err != nil {
+		return "", err
+	} else {
+		sum := sha256.Sum256(data)
+		return hex.EncodeToString(sum[:]), nil
+	}
+}
@@ -0,0 +1,45 @@
+package manifestvalues
+
+import (
+	"github.com/glasskube/glasskube/api/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Hash", func() {
+	manifest := &v1alpha1.PackageManifest{
+		ValueDefinitions: map[string]v1alpha1.ValueDefinition{
+			"password": {Type: v1alpha1.ValueTypeSecret},
+			"username": {Type: v1alpha1.ValueTypeText},
+		},
+	}
+	secretRef := func(key string) v1alpha1.ValueConfiguration {
+		return v1alpha1.ValueConfiguration{ValueFrom: &v1alpha1.ValueReference{
+			SecretRef: &v1alpha1.ObjectKeyValueSource{Name: "values", Namespace: "default", Key: key},
+		}}
+	}
+	hash := func(values map[string]v1alpha1.ValueConfiguration, resolved map[string]string) string {
+		result, err := Hash(manifest, values, resolved)
+		Expect(err).NotTo(HaveOccurred())
+		return result
+	}
+
+	It("should be equal for equal values", func() {
+		Expect(hash(nil, map[string]string{"foo": "1", "bar": "2"})).
+			To(Equal(hash(nil, map[string]string{"bar": "2", "foo": "1"})))
+	})
+	It("should differ for different values", func() {
+		Expect(hash(nil, map[string]string{"foo": "1"})).NotTo(Equal(hash(nil, map[string]string{"foo": "2"})))
+	})
+	It("should not depend on secret values", func() {
+		values := map[string]v1alpha1.ValueConfiguration{"password": secretRef("password.1"), "token": secretRef("token")}
+		Expect(hash(values, map[string]string{"password": "a", "token": "a", "username": "admin"})).
+			To(Equal(hash(values, map[string]string{"password": "b", "token": "b", "username": "admin"})))
+		Expect(hash(nil, map[string]string{"password": "a"})).To(Equal(hash(nil, map[string]string{"password": "b"})))
+	})
+	It("should differ for different secret references", func() {
+		resolved := map[string]string{"password": "a"}
+		Expect(hash(map[string]v1alpha1.ValueConfiguration{"password": secretRef("password.1")}, resolved)).
+			NotTo(Equal(hash(map[string]v1alpha1.ValueConfiguration{"password": secretRef("password.2")}, resolved)))
+	})
+})
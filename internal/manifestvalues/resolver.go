@@ -2,12 +2,16 @@ package manifestvalues
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 
 	"github.com/glasskube/glasskube/api/v1alpha1"
 	"github.com/glasskube/glasskube/internal/adapter"
+	"github.com/glasskube/glasskube/internal/controller/ctrlpkg"
+	"github.com/glasskube/glasskube/internal/manifesttransformations"
 	"go.uber.org/multierr"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
 type Resolver struct {
@@ -35,6 +39,31 @@ func (r *Resolver) Resolve(ctx context.Context, values map[string]v1alpha1.Value
 	return resolvedValues, errComposite
 }
 
+// ResolvePackage resolves all values of pkg. References to resources without a namespace are resolved in the
+// namespace of pkg (see WithDefaultNamespace).
+func (r *Resolver) ResolvePackage(ctx context.Context, pkg ctrlpkg.Package) (map[string]string, error) {
+	return r.Resolve(ctx, WithDefaultNamespace(pkg.GetSpec().Values, pkg.GetNamespace()))
+}
+
+// WithDefaultNamespace returns a copy of values where every resourceRef without a namespace uses namespace instead.
+// This is the same namespace that is used for resources of transformations. For cluster scoped resources, the
+// namespace is ignored.
+func WithDefaultNamespace(
+	values map[string]v1alpha1.ValueConfiguration,
+	namespace string,
+) map[string]v1alpha1.ValueConfiguration {
+	result := make(map[string]v1alpha1.ValueConfiguration, len(values))
+	for name, value := range values {
+		if namespace != "" && value.ValueFrom != nil && value.ValueFrom.ResourceRef != nil &&
+			value.ValueFrom.ResourceRef.Namespace == "" {
+			value = *value.DeepCopy()
+			value.ValueFrom.ResourceRef.Namespace = namespace
+		}
+		result[name] = value
+	}
+	return result
+}
+
 func (r *Resolver) ResolveValue(ctx context.Context, value v1alpha1.ValueConfiguration) (string, error) {
 	if value.Value != nil {
 		return *value.Value, nil
@@ -56,6 +85,8 @@ func (r *Resolver) resolveReference(ctx context.Context, ref v1alpha1.ValueRefer
 		return r.resolveSecretRef(ctx, *ref.SecretRef)
 	} else if ref.PackageRef != nil {
 		return r.resolvePackageRef(ctx, *ref.PackageRef)
+	} else if ref.ResourceRef != nil {
+		return r.resolveResourceRef(ctx, *ref.ResourceRef)
 	} else {
 		return "", errors.New("cannot resolve empty reference")
 	}
@@ -92,3 +123,19 @@ func (r *Resolver) resolvePackageRef(ctx context.Context, ref v1alpha1.PackageVa
 		return resolved, nil
 	}
 }
+
+func (r *Resolver) resolveResourceRef(ctx context.Context, ref v1alpha1.ResourceValueSource) (string, error) {
+	if gv, err := schema.ParseGroupVersion(ref.APIVersion); err != nil {
+		return "", NewResourceRefError(ref, err)
+	} else if obj, err := r.client.GetResource(ctx, gv.WithKind(ref.Kind), ref.Name, ref.Namespace); err != nil {
+		return "", NewResourceRefError(ref, err)
+	} else if result, err := manifesttransformations.FindSingleResult(ref.Path, obj.UnstructuredContent()); err != nil {
+		return "", NewResourceRefError(ref, err)
+	} else if s, ok := result.(string); ok {
+		return s, nil
+	} else if data, err := json.Marshal(result); err != nil {
+		return "", NewResourceRefError(ref, err)
+	} else {
+		return string(data), nil
+	}
+}
@@ -101,4 +101,74 @@ var _ = Describe("resolver", func() {
 		Expect(err).NotTo(HaveOccurred())
 		Expect(result).To(Equal(map[string]string{"test": "test"}))
 	})
+
+	It("should resolve resource reference value", func(ctx context.Context) {
+		resolver := newTestResolver(&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test"},
+			Spec: corev1.ServiceSpec{
+				ClusterIP: "10.0.0.1",
+				Ports:     []corev1.ServicePort{{Port: 80}, {Port: 443}},
+			},
+		})
+		result, err := resolver.Resolve(ctx, map[string]v1alpha1.ValueConfiguration{
+			"ip": {
+				ValueFrom: &v1alpha1.ValueReference{
+					ResourceRef: &v1alpha1.ResourceValueSource{
+						APIVersion: "v1", Kind: "Service", Name: "test", Namespace: "test", Path: "{.spec.clusterIP}",
+					},
+				},
+			},
+			"ports": {
+				ValueFrom: &v1alpha1.ValueReference{
+					ResourceRef: &v1alpha1.ResourceValueSource{
+						APIVersion: "v1", Kind: "Service", Name: "test", Namespace: "test", Path: "{.spec.ports[*].port}",
+					},
+				},
+			},
+		})
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("cannot resolve value ports"))
+		Expect(result).To(Equal(map[string]string{"ip": "10.0.0.1"}))
+	})
+
+	It("should resolve resource reference to JSON for non-string fields", func(ctx context.Context) {
+		resolver := newTestResolver(&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test"},
+			Spec:       corev1.ServiceSpec{Ports: []corev1.ServicePort{{Port: 80}}},
+		})
+		result, err := resolver.Resolve(ctx, map[string]v1alpha1.ValueConfiguration{
+			"port": {
+				ValueFrom: &v1alpha1.ValueReference{
+					ResourceRef: &v1alpha1.ResourceValueSource{
+						APIVersion: "v1", Kind: "Service", Name: "test", Namespace: "test", Path: "{.spec.ports[0].port}",
+					},
+				},
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result).To(Equal(map[string]string{"port": "80"}))
+	})
+	It("should resolve resource references without namespace in the namespace of the package", func(ctx context.Context) {
+		resolver := newTestResolver(&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test"},
+			Spec:       corev1.ServiceSpec{ClusterIP: "10.0.0.1"},
+		})
+		values := map[string]v1alpha1.ValueConfiguration{
+			"ip": {
+				ValueFrom: &v1alpha1.ValueReference{
+					ResourceRef: &v1alpha1.ResourceValueSource{
+						APIVersion: "v1", Kind: "Service", Name: "test", Path: "{.spec.clusterIP}",
+					},
+				},
+			},
+		}
+		pkg := &v1alpha1.Package{
+			ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test"},
+			Spec:       v1alpha1.PackageSpec{Values: values},
+		}
+		result, err := resolver.ResolvePackage(ctx, pkg)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result).To(Equal(map[string]string{"ip": "10.0.0.1"}))
+		Expect(values["ip"].ValueFrom.ResourceRef.Namespace).To(BeEmpty())
+	})
 })
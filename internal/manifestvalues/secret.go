@@ -0,0 +1,168 @@
+package manifestvalues
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"strconv"
+	"strings"
+
+	"github.com/glasskube/glasskube/api/v1alpha1"
+	"github.com/glasskube/glasskube/internal/controller/ctrlpkg"
+	"github.com/glasskube/glasskube/internal/names"
+	"github.com/glasskube/glasskube/internal/revisions"
+	"github.com/glasskube/glasskube/internal/util"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// RedactedValue replaces the values of secret values in output.
+const RedactedValue = "<redacted>"
+
+// clusterPackageSecretNamespace is the namespace of Secrets for values of a ClusterPackage.
+const clusterPackageSecretNamespace = "glasskube-system"
+
+// ValuesSecretNamespace returns the namespace of the Secret that stores the secret values of pkg.
+func ValuesSecretNamespace(pkg ctrlpkg.Package) string {
+	if pkg.IsNamespaceScoped() {
+		return pkg.GetNamespace()
+	}
+	return clusterPackageSecretNamespace
+}
+
+// IsValuesSecretRef returns true if ref refers to the Secret that stores the secret values of pkg.
+func IsValuesSecretRef(pkg ctrlpkg.Package, ref *v1alpha1.ValueReference) bool {
+	return ref != nil && ref.SecretRef != nil &&
+		ref.SecretRef.Name == names.ValuesSecretName(pkg) &&
+		ref.SecretRef.Namespace == ValuesSecretNamespace(pkg)
+}
+
+// ExtractSecretValues moves all inline values of pkg that have the type v1alpha1.ValueTypeSecret into a Secret and
+// replaces them with a reference to this Secret.
+// The keys in the Secret are suffixed with the number of the revision that is recorded for the new values (e.g.
+// "password.3"), so that existing keys are never overwritten and every revision can be rolled back including its
+// secret values.
+// It returns nil if pkg has no inline secret values. The returned Secret must be created with ApplySecretValues.
+func ExtractSecretValues(pkg ctrlpkg.Package, manifest *v1alpha1.PackageManifest) *corev1.Secret {
+	spec := pkg.GetSpec()
+	revision := revisions.Next(pkg.GetStatus().Revisions)
+	secret := corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: names.ValuesSecretName(pkg), Namespace: ValuesSecretNamespace(pkg)},
+		Type:       corev1.SecretTypeOpaque,
+		Data:       make(map[string][]byte),
+	}
+	for name, value := range spec.Values {
+		if def, ok := manifest.ValueDefinitions[name]; !ok || def.Type != v1alpha1.ValueTypeSecret || value.Value == nil {
+			continue
+		}
+		key := fmt.Sprintf("%v.%v", name, revision)
+		secret.Data[key] = []byte(*value.Value)
+		spec.Values[name] = v1alpha1.ValueConfiguration{
+			ValueFrom: &v1alpha1.ValueReference{
+				SecretRef: &v1alpha1.ObjectKeyValueSource{Name: secret.Name, Namespace: secret.Namespace, Key: key},
+			},
+		}
+	}
+	if len(secret.Data) == 0 {
+		return nil
+	}
+	return &secret
+}
+
+// ApplySecretValues creates secret or adds its data to the existing Secret. Keys that exist only in the existing
+// Secret are kept while they may still be referenced by the package, i.e. by its spec or one of its revisions.
+// Keys of revisions that have been dropped from the history of owner are removed.
+// If owner has already been created, it is set as owner of the Secret, so that the Secret is deleted with it.
+func ApplySecretValues(
+	ctx context.Context,
+	client kubernetes.Interface,
+	secret *corev1.Secret,
+	owner ctrlpkg.Package,
+	dryRun []string,
+) error {
+	if owner != nil && owner.GetUID() != "" {
+		kind := "ClusterPackage"
+		if owner.IsNamespaceScoped() {
+			kind = "Package"
+		}
+		secret.OwnerReferences = []metav1.OwnerReference{{
+			APIVersion:         v1alpha1.GroupVersion.String(),
+			Kind:               kind,
+			Name:               owner.GetName(),
+			UID:                owner.GetUID(),
+			BlockOwnerDeletion: util.Pointer(true),
+		}}
+	}
+	secrets := client.CoreV1().Secrets(secret.Namespace)
+	if existing, err := secrets.Get(ctx, secret.Name, metav1.GetOptions{}); apierrors.IsNotFound(err) {
+		_, err = secrets.Create(ctx, secret, metav1.CreateOptions{DryRun: dryRun})
+		return err
+	} else if err != nil {
+		return err
+	} else {
+		if existing.Data == nil {
+			existing.Data = make(map[string][]byte)
+		}
+		if owner != nil {
+			removeUnusedKeys(existing, owner)
+		}
+		maps.Copy(existing.Data, secret.Data)
+		if len(secret.OwnerReferences) > 0 {
+			existing.OwnerReferences = secret.OwnerReferences
+		}
+		_, err = secrets.Update(ctx, existing, metav1.UpdateOptions{DryRun: dryRun})
+		return err
+	}
+}
+
+// removeUnusedKeys removes the keys of secret that belong to a revision older than the oldest revision of pkg and
+// are no longer referenced by pkg. Keys of newer revisions are always kept, because they may belong to values that
+// have not been recorded yet.
+func removeUnusedKeys(secret *corev1.Secret, pkg ctrlpkg.Package) {
+	history := pkg.GetStatus().Revisions
+	if len(history) == 0 {
+		return
+	}
+	used := make(map[string]bool)
+	addUsed := func(values map[string]v1alpha1.ValueConfiguration) {
+		for _, value := range values {
+			if IsValuesSecretRef(pkg, value.ValueFrom) {
+				used[value.ValueFrom.SecretRef.Key] = true
+			}
+		}
+	}
+	addUsed(pkg.GetSpec().Values)
+	for _, revision := range history {
+		addUsed(revision.Values)
+	}
+	for key := range secret.Data {
+		if i := strings.LastIndex(key, "."); i < 0 || used[key] {
+			continue
+		} else if revision, err := strconv.ParseInt(key[i+1:], 10, 64); err == nil && revision < history[0].Revision {
+			delete(secret.Data, key)
+		}
+	}
+}
+
+// Redact returns a copy of values where all inline values that have the type v1alpha1.ValueTypeSecret are replaced
+// with RedactedValue.
+func Redact(
+	values map[string]v1alpha1.ValueConfiguration,
+	manifest *v1alpha1.PackageManifest,
+) map[string]v1alpha1.ValueConfiguration {
+	if values == nil {
+		return nil
+	}
+	result := make(map[string]v1alpha1.ValueConfiguration, len(values))
+	for name, value := range values {
+		if def, ok := manifest.ValueDefinitions[name]; ok && def.Type == v1alpha1.ValueTypeSecret && value.Value != nil {
+			value = v1alpha1.ValueConfiguration{
+				InlineValueConfiguration: v1alpha1.InlineValueConfiguration{Value: util.Pointer(RedactedValue)},
+			}
+		}
+		result[name] = value
+	}
+	return result
+}
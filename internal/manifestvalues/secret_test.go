@@ -0,0 +1,154 @@
+package manifestvalues
+
+import (
+	"context"
+
+	"github.com/glasskube/glasskube/api/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+var _ = Describe("secret values", func() {
+	manifest := v1alpha1.PackageManifest{
+		ValueDefinitions: map[string]v1alpha1.ValueDefinition{
+			"password": {Type: v1alpha1.ValueTypeSecret},
+			"username": {Type: v1alpha1.ValueTypeText},
+		},
+	}
+	inline := func(value string) v1alpha1.ValueConfiguration {
+		return v1alpha1.ValueConfiguration{InlineValueConfiguration: v1alpha1.InlineValueConfiguration{Value: &value}}
+	}
+	newPackage := func() *v1alpha1.Package {
+		return &v1alpha1.Package{
+			ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default", UID: "test-uid"},
+			Spec: v1alpha1.PackageSpec{
+				Values: map[string]v1alpha1.ValueConfiguration{
+					"password": inline("secret"),
+					"username": inline("admin"),
+				},
+			},
+		}
+	}
+
+	Describe("ExtractSecretValues", func() {
+		It("should replace inline secret values with a reference", func() {
+			pkg := newPackage()
+			secret := ExtractSecretValues(pkg, &manifest)
+			Expect(secret).NotTo(BeNil())
+			Expect(secret.Namespace).To(Equal("default"))
+			Expect(secret.Data).To(Equal(map[string][]byte{"password.1": []byte("secret")}))
+			Expect(pkg.Spec.Values["username"]).To(Equal(inline("admin")))
+			Expect(pkg.Spec.Values["password"].Value).To(BeNil())
+			Expect(pkg.Spec.Values["password"].ValueFrom.SecretRef).To(Equal(&v1alpha1.ObjectKeyValueSource{
+				Name: secret.Name, Namespace: "default", Key: "password.1",
+			}))
+			Expect(IsValuesSecretRef(pkg, pkg.Spec.Values["password"].ValueFrom)).To(BeTrue())
+		})
+		It("should use the number of the next revision as key suffix", func() {
+			pkg := newPackage()
+			pkg.Status.Revisions = []v1alpha1.PackageRevision{{Revision: 3}, {Revision: 4}}
+			secret := ExtractSecretValues(pkg, &manifest)
+			Expect(secret.Data).To(HaveKeyWithValue("password.5", []byte("secret")))
+			Expect(pkg.Spec.Values["password"].ValueFrom.SecretRef.Key).To(Equal("password.5"))
+		})
+		It("should return nil without inline secret values", func() {
+			pkg := newPackage()
+			delete(pkg.Spec.Values, "password")
+			Expect(ExtractSecretValues(pkg, &manifest)).To(BeNil())
+		})
+		It("should use the glasskube-system namespace for cluster packages", func() {
+			pkg := &v1alpha1.ClusterPackage{
+				ObjectMeta: metav1.ObjectMeta{Name: "test"},
+				Spec:       v1alpha1.PackageSpec{Values: map[string]v1alpha1.ValueConfiguration{"password": inline("secret")}},
+			}
+			secret := ExtractSecretValues(pkg, &manifest)
+			Expect(secret).NotTo(BeNil())
+			Expect(secret.Namespace).To(Equal("glasskube-system"))
+		})
+	})
+
+	Describe("ApplySecretValues", func() {
+		It("should create the secret with an owner reference", func(ctx context.Context) {
+			pkg := newPackage()
+			client := fake.NewSimpleClientset()
+			secret := ExtractSecretValues(pkg, &manifest)
+			Expect(ApplySecretValues(ctx, client, secret, pkg, nil)).To(Succeed())
+			created, err := client.CoreV1().Secrets("default").Get(ctx, secret.Name, metav1.GetOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(created.Data).To(HaveKeyWithValue("password.1", []byte("secret")))
+			Expect(created.OwnerReferences).To(HaveLen(1))
+			Expect(created.OwnerReferences[0].Kind).To(Equal("Package"))
+		})
+		It("should keep existing keys", func(ctx context.Context) {
+			pkg := newPackage()
+			secret := ExtractSecretValues(pkg, &manifest)
+			client := fake.NewSimpleClientset(&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: secret.Name, Namespace: secret.Namespace},
+				Data:       map[string][]byte{"password": []byte("old"), "other": []byte("other")},
+			})
+			Expect(ApplySecretValues(ctx, client, secret, pkg, nil)).To(Succeed())
+			updated, err := client.CoreV1().Secrets("default").Get(ctx, secret.Name, metav1.GetOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(updated.Data).To(Equal(map[string][]byte{
+				"password.1": []byte("secret"),
+				"password":   []byte("old"),
+				"other":      []byte("other"),
+			}))
+		})
+		It("should remove keys of dropped revisions", func(ctx context.Context) {
+			pkg := newPackage()
+			ref := func(key string) v1alpha1.ValueConfiguration {
+				return v1alpha1.ValueConfiguration{ValueFrom: &v1alpha1.ValueReference{
+					SecretRef: &v1alpha1.ObjectKeyValueSource{Name: "package-test-values", Namespace: "default", Key: key},
+				}}
+			}
+			pkg.Status.Revisions = []v1alpha1.PackageRevision{
+				{Revision: 3, Values: map[string]v1alpha1.ValueConfiguration{"password": ref("password.2")}},
+				{Revision: 4, Values: map[string]v1alpha1.ValueConfiguration{"password": ref("password.4")}},
+			}
+			secret := ExtractSecretValues(pkg, &manifest)
+			Expect(secret.Name).To(Equal("package-test-values"))
+			client := fake.NewSimpleClientset(&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: secret.Name, Namespace: secret.Namespace},
+				Data: map[string][]byte{
+					"password.1": []byte("dropped"),
+					"password.2": []byte("old"),
+					"password.4": []byte("current"),
+					"password.6": []byte("concurrent"),
+					"other":      []byte("other"),
+				},
+			})
+			Expect(ApplySecretValues(ctx, client, secret, pkg, nil)).To(Succeed())
+			updated, err := client.CoreV1().Secrets("default").Get(ctx, secret.Name, metav1.GetOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(updated.Data).To(Equal(map[string][]byte{
+				"password.2": []byte("old"),
+				"password.4": []byte("current"),
+				"password.5": []byte("secret"),
+				"password.6": []byte("concurrent"),
+				"other":      []byte("other"),
+			}))
+		})
+	})
+
+	Describe("Redact", func() {
+		It("should redact inline secret values", func() {
+			pkg := newPackage()
+			redacted := Redact(pkg.Spec.Values, &manifest)
+			Expect(redacted["password"]).To(Equal(inline(RedactedValue)))
+			Expect(redacted["username"]).To(Equal(inline("admin")))
+			Expect(pkg.Spec.Values["password"]).To(Equal(inline("secret")))
+		})
+		It("should keep references", func() {
+			values := map[string]v1alpha1.ValueConfiguration{
+				"password": {ValueFrom: &v1alpha1.ValueReference{
+					SecretRef: &v1alpha1.ObjectKeyValueSource{Name: "a", Namespace: "b", Key: "c"},
+				}},
+			}
+			Expect(Redact(values, &manifest)).To(Equal(values))
+		})
+	})
+})
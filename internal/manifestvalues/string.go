@@ -17,6 +17,12 @@ func ValueAsString(value v1alpha1.ValueConfiguration) string {
 		} else if value.ValueFrom.PackageRef != nil {
 			return fmt.Sprintf("reference to value '%v' of Package %v",
 				value.ValueFrom.PackageRef.Value, value.ValueFrom.PackageRef.Name)
+		} else if value.ValueFrom.ResourceRef != nil {
+			ref := value.ValueFrom.ResourceRef
+			if ref.Namespace != "" {
+				return fmt.Sprintf("reference to '%v' of %v %v in namespace %v", ref.Path, ref.Kind, ref.Name, ref.Namespace)
+			}
+			return fmt.Sprintf("reference to '%v' of %v %v", ref.Path, ref.Kind, ref.Name)
 		}
 	} else if value.Value != nil {
 		return *value.Value
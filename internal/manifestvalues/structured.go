@@ -0,0 +1,120 @@
+package manifestvalues
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/glasskube/glasskube/api/v1alpha1"
+	"github.com/glasskube/glasskube/internal/maputils"
+	"go.uber.org/multierr"
+)
+
+// ParseList parses the value of a list value, which must be a JSON array of strings.
+// An empty string is parsed as empty list.
+func ParseList(value string) ([]string, error) {
+	var items []string
+	if value == "" {
+		return items, nil
+	} else if err := json.Unmarshal([]byte(value), &items); err != nil {
+		return nil, NewFormatError("JSON array of strings", err)
+	}
+	return items, nil
+}
+
+// ParseMap parses the value of a map value, which must be a JSON object with string values.
+// An empty string is parsed as empty map.
+func ParseMap(value string) (map[string]string, error) {
+	items := make(map[string]string)
+	if value == "" {
+		return items, nil
+	} else if err := json.Unmarshal([]byte(value), &items); err != nil {
+		return nil, NewFormatError("JSON object of strings", err)
+	}
+	return items, nil
+}
+
+// FormatList returns the value of a list value with the given items.
+func FormatList(items []string) string {
+	if items == nil {
+		items = []string{}
+	}
+	data, _ := json.Marshal(items)
+	return string(data)
+}
+
+// FormatMap returns the value of a map value with the given items.
+func FormatMap(items map[string]string) string {
+	if items == nil {
+		items = map[string]string{}
+	}
+	data, _ := json.Marshal(items)
+	return string(data)
+}
+
+// ItemDefinition returns a value definition for the items of the list or map value definition def.
+func ItemDefinition(def v1alpha1.ValueDefinition) v1alpha1.ValueDefinition {
+	if def.Items == nil {
+		return v1alpha1.ValueDefinition{Type: v1alpha1.ValueTypeText}
+	}
+	return v1alpha1.ValueDefinition{Type: def.Items.Type, Options: def.Items.Options, Constraints: def.Items.Constraints}
+}
+
+func validateStructured(def v1alpha1.ValueDefinition, value string) error {
+	itemDef := ItemDefinition(def)
+	if itemDef.Type == v1alpha1.ValueTypeList || itemDef.Type == v1alpha1.ValueTypeMap ||
+		itemDef.Type == v1alpha1.ValueTypeSecret {
+		return NewValueTypeError(itemDef.Type)
+	}
+	var err error
+	switch def.Type {
+	case v1alpha1.ValueTypeList:
+		if items, parseErr := ParseList(value); parseErr != nil {
+			return parseErr
+		} else {
+			err = validateItemCount(def, len(items))
+			for i, item := range items {
+				multierr.AppendInto(&err, newItemError(fmt.Sprint(i), validateItem(itemDef, item)))
+			}
+		}
+	case v1alpha1.ValueTypeMap:
+		if items, parseErr := ParseMap(value); parseErr != nil {
+			return parseErr
+		} else {
+			err = validateItemCount(def, len(items))
+			for _, key := range maputils.KeysSorted(items) {
+				if key == "" {
+					multierr.AppendInto(&err, errors.New("map keys must not be empty"))
+				}
+				multierr.AppendInto(&err, newItemError(key, validateItem(itemDef, items[key])))
+			}
+		}
+	default:
+		return NewValueTypeError(def.Type)
+	}
+	return err
+}
+
+func validateItemCount(def v1alpha1.ValueDefinition, count int) error {
+	if def.Constraints.MinItems != nil && count < *def.Constraints.MinItems {
+		return fmt.Errorf("%w: %v", ErrConstraintMinItems, *def.Constraints.MinItems)
+	} else if def.Constraints.MaxItems != nil && count > *def.Constraints.MaxItems {
+		return fmt.Errorf("%w: %v", ErrConstraintMaxItems, *def.Constraints.MaxItems)
+	}
+	return nil
+}
+
+func validateItem(itemDef v1alpha1.ValueDefinition, item string) error {
+	if validators, ok := validatorsForType[itemDef.Type]; !ok {
+		return NewValueTypeError(itemDef.Type)
+	} else {
+		return validators.validate(itemDef, item)
+	}
+}
+
+func newItemError(item string, cause error) error {
+	if cause == nil {
+		return nil
+	}
+	return fmt.Errorf("item %v: %w", item, cause)
+}
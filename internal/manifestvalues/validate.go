@@ -98,17 +98,23 @@ var validatorsForType = map[v1alpha1.ValueType]validateFns{
 	v1alpha1.ValueTypeNumber:  {validateFormatNumber, validateMin, validateMax, validatePattern},
 	v1alpha1.ValueTypeOptions: {validateOptions},
 	v1alpha1.ValueTypeBoolean: {validateFormatBoolean},
+	v1alpha1.ValueTypeSecret:  {validateMaxLength, validateMinLength, validatePattern},
 }
 
 func validate(manifest v1alpha1.PackageManifest, values map[string]validationTarget) (err error) {
 	namesFromDef := make(map[string]struct{})
 	for name, def := range manifest.ValueDefinitions {
 		namesFromDef[name] = struct{}{}
+		holds, known := conditionHolds(manifest, name, values, nil)
+		if !holds && known {
+			// values that do not apply are ignored
+			continue
+		}
 		if value, ok := values[name]; ok {
 			if !value.Skip() {
 				multierr.AppendInto(&err, ValidateSingle(name, def, value.Get()))
 			}
-		} else if def.Constraints.Required {
+		} else if def.Constraints.Required && known {
 			multierr.AppendInto(&err, NewValidationError(name, ErrConstraintRequired))
 		}
 	}
@@ -122,7 +128,9 @@ func validate(manifest v1alpha1.PackageManifest, values map[string]validationTar
 }
 
 func ValidateSingle(name string, def v1alpha1.ValueDefinition, value string) (err error) {
-	if validators, ok := validatorsForType[def.Type]; !ok {
+	if def.Type == v1alpha1.ValueTypeList || def.Type == v1alpha1.ValueTypeMap {
+		multierr.AppendInto(&err, NewValidationError(name, validateStructured(def, value)))
+	} else if validators, ok := validatorsForType[def.Type]; !ok {
 		multierr.AppendInto(&err, NewValidationError(name, NewValueTypeError(def.Type)))
 	} else {
 		multierr.AppendInto(&err, NewValidationError(name, validators.validate(def, value)))
@@ -164,8 +172,12 @@ func targetsForResolvedValues(values map[string]string) map[string]validationTar
 }
 
 func targetsForPackage(pkg ctrlpkg.Package) map[string]validationTarget {
+	return targetsForValues(pkg.GetSpec().Values)
+}
+
+func targetsForValues(values map[string]v1alpha1.ValueConfiguration) map[string]validationTarget {
 	result := make(map[string]validationTarget)
-	for name, value := range pkg.GetSpec().Values {
+	for name, value := range values {
 		if value.Value != nil {
 			result[name] = acutalValue(*value.Value)
 		} else {
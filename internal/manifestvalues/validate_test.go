@@ -46,6 +46,25 @@ var _ = Describe("ValidateResolvedValues", func() {
 				Options: []string{"foo", "bar"},
 			},
 			"bool": {Type: v1alpha1.ValueTypeBoolean},
+			"secret": {
+				Type:        v1alpha1.ValueTypeSecret,
+				Constraints: v1alpha1.ValueDefinitionConstraints{MinLength: &five},
+			},
+			"list": {
+				Type:        v1alpha1.ValueTypeList,
+				Constraints: v1alpha1.ValueDefinitionConstraints{MaxItems: &five},
+				Items: &v1alpha1.ValueItemDefinition{
+					Type:        v1alpha1.ValueTypeNumber,
+					Constraints: v1alpha1.ValueDefinitionConstraints{Max: &ten},
+				},
+			},
+			"map": {
+				Type: v1alpha1.ValueTypeMap,
+				Items: &v1alpha1.ValueItemDefinition{
+					Type:    v1alpha1.ValueTypeOptions,
+					Options: []string{"foo", "bar"},
+				},
+			},
 		},
 	}
 	DescribeTable("Validating values",
@@ -77,5 +96,17 @@ var _ = Describe("ValidateResolvedValues", func() {
 		Entry("When correct bool format: true", manifestWithConstraints, map[string]string{"bool": "true"}, true),
 		Entry("When correct bool format: false", manifestWithConstraints, map[string]string{"bool": "false"}, true),
 		Entry("When correct bool format: 1", manifestWithConstraints, map[string]string{"bool": "1"}, true),
+		Entry("When secret MinLength violated", manifestWithConstraints, map[string]string{"secret": "aaa"}, false),
+		Entry("When list is valid", manifestWithConstraints, map[string]string{"list": `["1","10"]`}, true),
+		Entry("When list is empty", manifestWithConstraints, map[string]string{"list": `[]`}, true),
+		Entry("When list is not JSON", manifestWithConstraints, map[string]string{"list": "1,2"}, false),
+		Entry("When list MaxItems violated",
+			manifestWithConstraints, map[string]string{"list": `["1","2","3","4","5","6"]`}, false),
+		Entry("When list item constraint violated", manifestWithConstraints, map[string]string{"list": `["11"]`}, false),
+		Entry("When list item format invalid", manifestWithConstraints, map[string]string{"list": `["a"]`}, false),
+		Entry("When map is valid", manifestWithConstraints, map[string]string{"map": `{"a":"foo","b":"bar"}`}, true),
+		Entry("When map item not in Options", manifestWithConstraints, map[string]string{"map": `{"a":"baz"}`}, false),
+		Entry("When map key is empty", manifestWithConstraints, map[string]string{"map": `{"":"foo"}`}, false),
+		Entry("When secret MinLength not violated", manifestWithConstraints, map[string]string{"secret": "aaaaa"}, true),
 	)
 })
@@ -0,0 +1,155 @@
+// Package metrics defines the custom Prometheus metrics of Glasskube.
+// All metrics are registered with the controller-runtime registry, so the operator exposes them on its metrics
+// endpoint together with the default controller-runtime metrics.
+package metrics
+
+import (
+	"time"
+
+	"github.com/glasskube/glasskube/internal/controller/ctrlpkg"
+	"github.com/glasskube/glasskube/pkg/condition"
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const namespace = "glasskube"
+
+var packageLabels = []string{"kind", "namespace", "name", "package"}
+
+var (
+	PackageReady = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "package_ready",
+		Help:      "Whether the Ready condition of a package is True (1) or not (0).",
+	}, packageLabels)
+	PackageFailed = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "package_failed",
+		Help:      "Whether the Failed condition of a package is True (1) or not (0).",
+	}, packageLabels)
+	PackageOutdated = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "package_outdated",
+		Help: "Whether a newer version than the installed version of a package is available (1) or not (0). " +
+			"Updated by the auto-update controller at the auto-update interval, or hourly if automatic updates are " +
+			"disabled.",
+	}, append(packageLabels, "installed_version", "latest_version"))
+	RepositoryReady = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "repository_ready",
+		Help:      "Whether the last synchronization of a PackageRepository succeeded (1) or not (0).",
+	}, []string{"repository"})
+	RepositorySyncTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "repository_sync_total",
+		Help:      "Total number of PackageRepository synchronizations by result.",
+	}, []string{"repository", "result"})
+	RepositorySyncDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "repository_sync_duration_seconds",
+		Help:      "Duration of PackageRepository synchronizations.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"repository"})
+	AdapterReconcileDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "adapter_reconcile_duration_seconds",
+		Help:      "Duration of manifest adapter reconciliations by adapter type.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"adapter"})
+	RepositoryFetchTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "repository_fetch_total",
+		Help:      "Total number of HTTP requests to package repositories by result.",
+	}, []string{"url", "result"})
+	RepositoryCacheHitsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "repository_cache_hits_total",
+		Help:      "Total number of package repository requests that were served from the cache.",
+	}, []string{"url"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(
+		PackageReady,
+		PackageFailed,
+		PackageOutdated,
+		RepositoryReady,
+		RepositorySyncTotal,
+		RepositorySyncDuration,
+		AdapterReconcileDuration,
+		RepositoryFetchTotal,
+		RepositoryCacheHitsTotal,
+	)
+}
+
+// SetPackageStatus sets the ready and failed gauges of pkg according to its conditions.
+func SetPackageStatus(pkg ctrlpkg.Package) {
+	labels := packageLabelValues(pkg)
+	conditions := pkg.GetStatus().Conditions
+	PackageReady.WithLabelValues(labels...).Set(boolValue(meta.IsStatusConditionTrue(conditions,
+		string(condition.Ready))))
+	PackageFailed.WithLabelValues(labels...).Set(boolValue(meta.IsStatusConditionTrue(conditions,
+		string(condition.Failed))))
+}
+
+// SetPackageOutdated sets the outdated gauge of pkg. If latestVersion is empty, the installed version is assumed to
+// be the latest version.
+func SetPackageOutdated(pkg ctrlpkg.Package, latestVersion string) {
+	installedVersion := pkg.GetSpec().PackageInfo.Version
+	if latestVersion == "" {
+		latestVersion = installedVersion
+	}
+	PackageOutdated.DeletePartialMatch(packageLabelMap(pkg))
+	PackageOutdated.WithLabelValues(append(packageLabelValues(pkg), installedVersion, latestVersion)...).
+		Set(boolValue(latestVersion != installedVersion))
+}
+
+// DeletePackage removes all series of pkg.
+func DeletePackage(pkg ctrlpkg.Package) {
+	labels := packageLabelMap(pkg)
+	PackageReady.DeletePartialMatch(labels)
+	PackageFailed.DeletePartialMatch(labels)
+	PackageOutdated.DeletePartialMatch(labels)
+}
+
+// ObserveRepositorySync records the result of a synchronization of the PackageRepository with the given name.
+func ObserveRepositorySync(name string, duration time.Duration, err error) {
+	result := "success"
+	if err != nil {
+		result = "failure"
+	}
+	RepositorySyncTotal.WithLabelValues(name, result).Inc()
+	RepositorySyncDuration.WithLabelValues(name).Observe(duration.Seconds())
+	RepositoryReady.WithLabelValues(name).Set(boolValue(err == nil))
+}
+
+// DeleteRepository removes all series of the PackageRepository with the given name.
+func DeleteRepository(name string) {
+	labels := prometheus.Labels{"repository": name}
+	RepositoryReady.DeletePartialMatch(labels)
+	RepositorySyncTotal.DeletePartialMatch(labels)
+	RepositorySyncDuration.DeletePartialMatch(labels)
+}
+
+func packageLabelValues(pkg ctrlpkg.Package) []string {
+	return []string{packageKind(pkg), pkg.GetNamespace(), pkg.GetName(), pkg.GetSpec().PackageInfo.Name}
+}
+
+func packageLabelMap(pkg ctrlpkg.Package) prometheus.Labels {
+	return prometheus.Labels{"kind": packageKind(pkg), "namespace": pkg.GetNamespace(), "name": pkg.GetName()}
+}
+
+func packageKind(pkg ctrlpkg.Package) string {
+	if pkg.IsNamespaceScoped() {
+		return "Package"
+	}
+	return "ClusterPackage"
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,75 @@
+package metrics
+
+import (
+	"errors"
+	"time"
+
+	"github.com/glasskube/glasskube/api/v1alpha1"
+	"github.com/glasskube/glasskube/pkg/condition"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Package metrics", func() {
+	var pkg *v1alpha1.Package
+
+	BeforeEach(func() {
+		pkg = &v1alpha1.Package{
+			ObjectMeta: metav1.ObjectMeta{Name: "my-pkg", Namespace: "ns"},
+			Spec: v1alpha1.PackageSpec{
+				PackageInfo: v1alpha1.PackageInfoTemplate{Name: "cert-manager", Version: "v1.0.0"},
+			},
+			Status: v1alpha1.PackageStatus{Conditions: []metav1.Condition{
+				{Type: string(condition.Ready), Status: metav1.ConditionFalse},
+				{Type: string(condition.Failed), Status: metav1.ConditionTrue},
+			}},
+		}
+		DeferCleanup(func() { DeletePackage(pkg) })
+	})
+
+	It("should set the status gauges", func() {
+		SetPackageStatus(pkg)
+		Expect(testutil.ToFloat64(PackageReady.WithLabelValues("Package", "ns", "my-pkg", "cert-manager"))).
+			To(Equal(0.0))
+		Expect(testutil.ToFloat64(PackageFailed.WithLabelValues("Package", "ns", "my-pkg", "cert-manager"))).
+			To(Equal(1.0))
+	})
+
+	It("should replace the outdated series when the versions change", func() {
+		SetPackageOutdated(pkg, "v2.0.0")
+		Expect(testutil.CollectAndCount(PackageOutdated)).To(Equal(1))
+		Expect(testutil.ToFloat64(
+			PackageOutdated.WithLabelValues("Package", "ns", "my-pkg", "cert-manager", "v1.0.0", "v2.0.0"))).
+			To(Equal(1.0))
+
+		pkg.Spec.PackageInfo.Version = "v2.0.0"
+		SetPackageOutdated(pkg, "")
+		Expect(testutil.CollectAndCount(PackageOutdated)).To(Equal(1))
+		Expect(testutil.ToFloat64(
+			PackageOutdated.WithLabelValues("Package", "ns", "my-pkg", "cert-manager", "v2.0.0", "v2.0.0"))).
+			To(Equal(0.0))
+	})
+
+	It("should delete all series of a package", func() {
+		SetPackageStatus(pkg)
+		SetPackageOutdated(pkg, "v2.0.0")
+		DeletePackage(pkg)
+		Expect(testutil.CollectAndCount(PackageReady)).To(Equal(0))
+		Expect(testutil.CollectAndCount(PackageFailed)).To(Equal(0))
+		Expect(testutil.CollectAndCount(PackageOutdated)).To(Equal(0))
+	})
+})
+
+var _ = Describe("Repository metrics", func() {
+	AfterEach(func() { DeleteRepository("repo") })
+
+	It("should record synchronizations", func() {
+		ObserveRepositorySync("repo", time.Second, nil)
+		ObserveRepositorySync("repo", time.Second, errors.New("failed"))
+		Expect(testutil.ToFloat64(RepositorySyncTotal.WithLabelValues("repo", "success"))).To(Equal(1.0))
+		Expect(testutil.ToFloat64(RepositorySyncTotal.WithLabelValues("repo", "failure"))).To(Equal(1.0))
+		Expect(testutil.ToFloat64(RepositoryReady.WithLabelValues("repo"))).To(Equal(0.0))
+	})
+})
@@ -23,3 +23,12 @@ func HelmResourceName(pkg ctrlpkg.Package, manifest *v1alpha1.PackageManifest) s
 func HelmResourceNameWithChart(pkg ctrlpkg.Package, manifest *v1alpha1.PackageManifest, chartName string) string {
 	return strings.Join([]string{HelmResourceName(pkg, manifest), chartName}, "-")
 }
+
+// ValuesSecretName returns the name of the Secret that stores the secret values of pkg.
+func ValuesSecretName(pkg ctrlpkg.Package) string {
+	kind := "clusterpackage"
+	if pkg.IsNamespaceScoped() {
+		kind = "package"
+	}
+	return escapeResourceName(strings.Join([]string{kind, pkg.GetName(), "values"}, "-"))
+}
@@ -0,0 +1,210 @@
+// Package notification sends package lifecycle events to the HTTP endpoints configured by NotificationChannels.
+package notification
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/glasskube/glasskube/api/v1alpha1"
+	"github.com/glasskube/glasskube/internal/controller/ctrlpkg"
+	"github.com/glasskube/glasskube/pkg/condition"
+	"go.uber.org/multierr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+const (
+	HeaderEvent     = "X-Glasskube-Event"
+	HeaderSignature = "X-Glasskube-Signature"
+	// SignaturePrefix is the prefix of the value of the signature header, followed by the hex encoded HMAC.
+	SignaturePrefix = "sha256="
+)
+
+// Event is the JSON payload sent to a NotificationChannel.
+type Event struct {
+	Type      v1alpha1.NotificationEventType `json:"type"`
+	Timestamp time.Time                      `json:"timestamp"`
+	Package   PackageRef                     `json:"package"`
+	Reason    string                         `json:"reason,omitempty"`
+	Message   string                         `json:"message,omitempty"`
+}
+
+type PackageRef struct {
+	Kind        string `json:"kind"`
+	Name        string `json:"name"`
+	Namespace   string `json:"namespace,omitempty"`
+	PackageName string `json:"packageName"`
+	Version     string `json:"version"`
+}
+
+// NewEvent creates an event of type eventType for pkg.
+func NewEvent(
+	eventType v1alpha1.NotificationEventType, pkg ctrlpkg.Package, reason condition.Reason, message string,
+) Event {
+	kind := "ClusterPackage"
+	if pkg.IsNamespaceScoped() {
+		kind = "Package"
+	}
+	return Event{
+		Type:      eventType,
+		Timestamp: time.Now().UTC(),
+		Package: PackageRef{
+			Kind:        kind,
+			Name:        pkg.GetName(),
+			Namespace:   pkg.GetNamespace(),
+			PackageName: pkg.GetSpec().PackageInfo.Name,
+			Version:     pkg.GetSpec().PackageInfo.Version,
+		},
+		Reason:  string(reason),
+		Message: message,
+	}
+}
+
+// Sign returns the value of the signature header for body.
+func Sign(key, body []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(body)
+	return SignaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+//+kubebuilder:rbac:groups=packages.glasskube.dev,resources=notificationchannels,verbs=get;list;watch
+//+kubebuilder:rbac:groups=packages.glasskube.dev,resources=notificationchannels/status,verbs=get;update;patch
+
+type Notifier struct {
+	client.Client
+	HttpClient *http.Client
+	// Namespace contains the secrets referenced by NotificationChannels.
+	Namespace string
+}
+
+var operatorInstance *Notifier
+
+func InitWithManager(mgr manager.Manager) {
+	operatorInstance = &Notifier{
+		Client:     mgr.GetClient(),
+		HttpClient: &http.Client{Timeout: 10 * time.Second},
+		Namespace:  "glasskube-system",
+	}
+}
+
+// ForOperator returns the Notifier of the operator. It is nil if InitWithManager was not called.
+func ForOperator() *Notifier {
+	return operatorInstance
+}
+
+// Notify sends an event of type eventType for pkg to all matching NotificationChannels in the background.
+// It does nothing if n is nil or obj is not a Package or ClusterPackage.
+func (n *Notifier) Notify(
+	obj client.Object, eventType v1alpha1.NotificationEventType, reason condition.Reason, message string,
+) {
+	if n == nil {
+		return
+	}
+	if pkg, ok := obj.(ctrlpkg.Package); ok {
+		event := NewEvent(eventType, pkg, reason, message)
+		go func() {
+			ctx := log.IntoContext(context.Background(), log.Log.WithName("notification"))
+			if err := n.Send(ctx, event); err != nil {
+				log.FromContext(ctx).Error(err, "failed to send notification", "type", eventType, "name", pkg.GetName())
+			}
+		}()
+	}
+}
+
+// Send sends event to all matching NotificationChannels and records the result in their Ready condition.
+// A failed status update does not stop the delivery to the remaining channels. All errors are returned combined.
+func (n *Notifier) Send(ctx context.Context, event Event) (err error) {
+	var channels v1alpha1.NotificationChannelList
+	if err := n.List(ctx, &channels); err != nil {
+		return fmt.Errorf("could not list NotificationChannels: %w", err)
+	}
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("could not encode event: %w", err)
+	}
+	for i := range channels.Items {
+		ch := &channels.Items[i]
+		if !ch.Matches(event.Type, event.Package.Name, event.Package.PackageName, event.Package.Namespace) {
+			continue
+		}
+		cond := metav1.Condition{
+			Type:    string(condition.Ready),
+			Status:  metav1.ConditionTrue,
+			Reason:  string(condition.DeliverySucceeded),
+			Message: fmt.Sprintf("last %v event delivered successfully", event.Type),
+		}
+		if err := n.deliver(ctx, ch, event.Type, body); err != nil {
+			log.FromContext(ctx).Info("notification delivery failed", "channel", ch.Name, "error", err)
+			cond.Status = metav1.ConditionFalse
+			cond.Reason = string(condition.DeliveryFailed)
+			cond.Message = err.Error()
+		}
+		if updateErr := n.setReadyCondition(ctx, ch, cond); updateErr != nil {
+			multierr.AppendInto(&err,
+				fmt.Errorf("could not update status of NotificationChannel %v: %w", ch.Name, updateErr))
+		}
+	}
+	return err
+}
+
+// setReadyCondition sets cond on ch and updates its status. On conflicts, the update is retried with the latest
+// version of ch.
+func (n *Notifier) setReadyCondition(
+	ctx context.Context, ch *v1alpha1.NotificationChannel, cond metav1.Condition,
+) error {
+	refetch := false
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if refetch {
+			if err := n.Get(ctx, client.ObjectKeyFromObject(ch), ch); err != nil {
+				return err
+			}
+		}
+		refetch = true
+		if !meta.SetStatusCondition(&ch.Status.Conditions, cond) {
+			return nil
+		}
+		return n.Status().Update(ctx, ch)
+	})
+}
+
+func (n *Notifier) deliver(
+	ctx context.Context, ch *v1alpha1.NotificationChannel, eventType v1alpha1.NotificationEventType, body []byte,
+) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, ch.Spec.Url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(HeaderEvent, string(eventType))
+	if ref := ch.Spec.SigningKeySecretRef; ref != nil {
+		var secret corev1.Secret
+		if err := n.Get(ctx, client.ObjectKey{Name: ref.Name, Namespace: n.Namespace}, &secret); err != nil {
+			return fmt.Errorf("could not get signing key: %w", err)
+		} else if key, ok := secret.Data[ref.Key]; !ok {
+			return fmt.Errorf("signing key secret %v has no key %v", ref.Name, ref.Key)
+		} else {
+			request.Header.Set(HeaderSignature, Sign(key, body))
+		}
+	}
+	response, err := n.HttpClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = response.Body.Close() }()
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return fmt.Errorf("%v responded with status %v", ch.Spec.Url, response.Status)
+	}
+	return nil
+}
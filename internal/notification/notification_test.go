@@ -0,0 +1,193 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/glasskube/glasskube/api/v1alpha1"
+	"github.com/glasskube/glasskube/pkg/condition"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+type request struct {
+	header http.Header
+	body   []byte
+}
+
+var _ = Describe("NotificationChannel.Matches", func() {
+	DescribeTable("filters",
+		func(spec v1alpha1.NotificationChannelSpec, namespace string, expected bool) {
+			ch := v1alpha1.NotificationChannel{Spec: spec}
+			Expect(ch.Matches(v1alpha1.NotificationEventFailed, "my-pkg", "cert-manager", namespace)).
+				To(Equal(expected))
+		},
+		Entry("no filters", v1alpha1.NotificationChannelSpec{}, "", true),
+		Entry("matching event", v1alpha1.NotificationChannelSpec{
+			Events: []v1alpha1.NotificationEventType{v1alpha1.NotificationEventFailed}}, "", true),
+		Entry("other event", v1alpha1.NotificationChannelSpec{
+			Events: []v1alpha1.NotificationEventType{v1alpha1.NotificationEventInstalled}}, "", false),
+		Entry("matching name", v1alpha1.NotificationChannelSpec{Packages: []string{"my-pkg"}}, "", true),
+		Entry("matching package name", v1alpha1.NotificationChannelSpec{Packages: []string{"cert-manager"}}, "", true),
+		Entry("other package", v1alpha1.NotificationChannelSpec{Packages: []string{"other"}}, "", false),
+		Entry("matching namespace", v1alpha1.NotificationChannelSpec{Namespaces: []string{"ns"}}, "ns", true),
+		Entry("other namespace", v1alpha1.NotificationChannelSpec{Namespaces: []string{"ns"}}, "other", false),
+		Entry("cluster package with namespace filter", v1alpha1.NotificationChannelSpec{
+			Namespaces: []string{"ns"}}, "", false),
+	)
+})
+
+var _ = Describe("Notifier", func() {
+	var server *httptest.Server
+	var requests chan request
+	var status int
+	var cl client.Client
+	var notifier *Notifier
+
+	pkg := &v1alpha1.Package{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-pkg", Namespace: "ns"},
+		Spec: v1alpha1.PackageSpec{
+			PackageInfo: v1alpha1.PackageInfoTemplate{Name: "cert-manager", Version: "v1.0.0"},
+		},
+	}
+
+	BeforeEach(func() {
+		requests = make(chan request, 10)
+		status = http.StatusOK
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			requests <- request{header: r.Header, body: body}
+			w.WriteHeader(status)
+		}))
+		DeferCleanup(server.Close)
+
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(v1alpha1.AddToScheme(scheme)).To(Succeed())
+		cl = fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithStatusSubresource(&v1alpha1.NotificationChannel{}).
+			WithObjects(
+				&v1alpha1.NotificationChannel{
+					ObjectMeta: metav1.ObjectMeta{Name: "signed"},
+					Spec: v1alpha1.NotificationChannelSpec{
+						Url: server.URL,
+						SigningKeySecretRef: &corev1.SecretKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "key"},
+							Key:                  "hmac",
+						},
+					},
+				},
+				&v1alpha1.NotificationChannel{
+					ObjectMeta: metav1.ObjectMeta{Name: "filtered"},
+					Spec: v1alpha1.NotificationChannelSpec{
+						Url:    server.URL,
+						Events: []v1alpha1.NotificationEventType{v1alpha1.NotificationEventInstalled},
+					},
+				},
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Name: "key", Namespace: "glasskube-system"},
+					Data:       map[string][]byte{"hmac": []byte("secret")},
+				},
+			).
+			Build()
+		notifier = &Notifier{Client: cl, HttpClient: server.Client(), Namespace: "glasskube-system"}
+	})
+
+	It("should send signed events to matching channels", func(ctx context.Context) {
+		event := NewEvent(v1alpha1.NotificationEventFailed, pkg, condition.InstallationFailed, "boom")
+		Expect(notifier.Send(ctx, event)).To(Succeed())
+		Expect(requests).To(HaveLen(1))
+
+		req := <-requests
+		Expect(req.header.Get(HeaderEvent)).To(Equal("Failed"))
+		Expect(req.header.Get(HeaderSignature)).To(Equal(Sign([]byte("secret"), req.body)))
+		var received Event
+		Expect(json.Unmarshal(req.body, &received)).To(Succeed())
+		Expect(received.Type).To(Equal(v1alpha1.NotificationEventFailed))
+		Expect(received.Package).To(Equal(PackageRef{
+			Kind: "Package", Name: "my-pkg", Namespace: "ns", PackageName: "cert-manager", Version: "v1.0.0",
+		}))
+		Expect(received.Message).To(Equal("boom"))
+
+		var ch v1alpha1.NotificationChannel
+		Expect(cl.Get(ctx, client.ObjectKey{Name: "signed"}, &ch)).To(Succeed())
+		Expect(meta.IsStatusConditionTrue(ch.Status.Conditions, string(condition.Ready))).To(BeTrue())
+	})
+
+	It("should record failed deliveries", func(ctx context.Context) {
+		status = http.StatusInternalServerError
+		event := NewEvent(v1alpha1.NotificationEventInstalled, pkg, condition.InstallationSucceeded, "")
+		Expect(notifier.Send(ctx, event)).To(Succeed())
+		Expect(requests).To(HaveLen(2))
+
+		var ch v1alpha1.NotificationChannel
+		Expect(cl.Get(ctx, client.ObjectKey{Name: "filtered"}, &ch)).To(Succeed())
+		cond := meta.FindStatusCondition(ch.Status.Conditions, string(condition.Ready))
+		Expect(cond).NotTo(BeNil())
+		Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+		Expect(cond.Reason).To(Equal(string(condition.DeliveryFailed)))
+	})
+
+	It("should deliver to all channels if a status update fails", func(ctx context.Context) {
+		var updated []string
+		notifier.Client = interceptor.NewClient(cl.(client.WithWatch), interceptor.Funcs{
+			SubResourceUpdate: func(ctx context.Context, c client.Client, subResourceName string, obj client.Object,
+				opts ...client.SubResourceUpdateOption) error {
+				updated = append(updated, obj.GetName())
+				if obj.GetName() == "signed" {
+					return errors.New("boom")
+				}
+				return c.SubResource(subResourceName).Update(ctx, obj, opts...)
+			},
+		})
+		event := NewEvent(v1alpha1.NotificationEventInstalled, pkg, condition.InstallationSucceeded, "")
+		err := notifier.Send(ctx, event)
+		Expect(err).To(MatchError(ContainSubstring("NotificationChannel signed")))
+		Expect(requests).To(HaveLen(2))
+		Expect(updated).To(ConsistOf("signed", "filtered"))
+
+		var ch v1alpha1.NotificationChannel
+		Expect(cl.Get(ctx, client.ObjectKey{Name: "filtered"}, &ch)).To(Succeed())
+		Expect(meta.IsStatusConditionTrue(ch.Status.Conditions, string(condition.Ready))).To(BeTrue())
+	})
+
+	It("should retry status updates on conflicts", func(ctx context.Context) {
+		conflicts := 1
+		notifier.Client = interceptor.NewClient(cl.(client.WithWatch), interceptor.Funcs{
+			SubResourceUpdate: func(ctx context.Context, c client.Client, subResourceName string, obj client.Object,
+				opts ...client.SubResourceUpdateOption) error {
+				if conflicts > 0 {
+					conflicts--
+					return apierrors.NewConflict(v1alpha1.GroupVersion.WithResource("notificationchannels").GroupResource(),
+						obj.GetName(), errors.New("conflict"))
+				}
+				return c.SubResource(subResourceName).Update(ctx, obj, opts...)
+			},
+		})
+		event := NewEvent(v1alpha1.NotificationEventFailed, pkg, condition.InstallationFailed, "boom")
+		Expect(notifier.Send(ctx, event)).To(Succeed())
+
+		var ch v1alpha1.NotificationChannel
+		Expect(cl.Get(ctx, client.ObjectKey{Name: "signed"}, &ch)).To(Succeed())
+		Expect(meta.IsStatusConditionTrue(ch.Status.Conditions, string(condition.Ready))).To(BeTrue())
+	})
+
+	It("should do nothing if not initialized", func() {
+		var n *Notifier
+		Expect(func() { n.Notify(pkg, v1alpha1.NotificationEventFailed, "", "") }).NotTo(Panic())
+	})
+})
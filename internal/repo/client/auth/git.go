@@ -0,0 +1,116 @@
+package auth
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"slices"
+
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	gitssh "github.com/go-git/go-git/v5/plumbing/transport/ssh"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+type gitHTTPAuthenticator struct {
+	Authenticator
+}
+
+// Name implements transport.AuthMethod.
+func (a *gitHTTPAuthenticator) Name() string {
+	return "http-authenticator"
+}
+
+// String implements transport.AuthMethod.
+func (a *gitHTTPAuthenticator) String() string {
+	return a.Name()
+}
+
+// SetAuth implements githttp.AuthMethod.
+func (a *gitHTTPAuthenticator) SetAuth(r *http.Request) {
+	a.Authenticate(r)
+}
+
+// GitHTTP returns an AuthMethod for git operations over HTTP that uses the given Authenticator.
+func GitHTTP(authenticator Authenticator) transport.AuthMethod {
+	return &gitHTTPAuthenticator{Authenticator: authenticator}
+}
+
+// GitBasic returns an AuthMethod for git operations over HTTP that uses basic authentication.
+func GitBasic(username, password string) transport.AuthMethod {
+	return &githttp.BasicAuth{Username: username, Password: password}
+}
+
+// GitIdentity returns a string that identifies the credentials of method without revealing any secrets.
+// It is empty if method is nil.
+func GitIdentity(method transport.AuthMethod) string {
+	switch m := method.(type) {
+	case nil:
+		return ""
+	case *gitssh.PublicKeys:
+		return fmt.Sprintf("ssh:%v:%v", m.User, ssh.FingerprintSHA256(m.Signer.PublicKey()))
+	case *githttp.BasicAuth:
+		return "basic:" + m.Username
+	case *gitHTTPAuthenticator:
+		if basic, ok := m.Authenticator.(*basicAuthenticator); ok {
+			return "basic:" + basic.username
+		}
+		return fmt.Sprintf("%T", m.Authenticator)
+	default:
+		return method.String()
+	}
+}
+
+// GitSSH returns an AuthMethod for git operations over SSH, using the given PEM encoded private key.
+// The host key of the remote is verified against knownHosts, which must use the format of an OpenSSH known_hosts file.
+func GitSSH(identity, knownHosts []byte) (transport.AuthMethod, error) {
+	if len(knownHosts) == 0 {
+		return nil, errors.New("known_hosts must not be empty")
+	}
+	if publicKeys, err := gitssh.NewPublicKeys("git", identity, ""); err != nil {
+		return nil, fmt.Errorf("invalid identity: %w", err)
+	} else if callback, err := knownHostsCallback(knownHosts); err != nil {
+		return nil, fmt.Errorf("invalid known_hosts: %w", err)
+	} else {
+		publicKeys.HostKeyCallback = callback
+		return publicKeys, nil
+	}
+}
+
+type knownHost struct {
+	hosts []string
+	key   ssh.PublicKey
+}
+
+func knownHostsCallback(data []byte) (ssh.HostKeyCallback, error) {
+	var entries []knownHost
+	for len(data) > 0 {
+		_, hosts, key, _, rest, err := ssh.ParseKnownHosts(data)
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		entries = append(entries, knownHost{hosts: hosts, key: key})
+		data = rest
+	}
+	return func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+		addresses := []string{knownhosts.Normalize(hostname)}
+		if remote != nil {
+			addresses = append(addresses, knownhosts.Normalize(remote.String()))
+		}
+		for _, entry := range entries {
+			for _, host := range entry.hosts {
+				if slices.Contains(addresses, knownhosts.Normalize(host)) &&
+					bytes.Equal(entry.key.Marshal(), key.Marshal()) {
+					return nil
+				}
+			}
+		}
+		return fmt.Errorf("host key for %v not found in known_hosts", hostname)
+	}, nil
+}
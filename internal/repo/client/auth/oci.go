@@ -0,0 +1,19 @@
+package auth
+
+import (
+	"github.com/google/go-containerregistry/pkg/authn"
+)
+
+// OCI returns an authn.Authenticator for OCI registries with the same credentials as the given Authenticator.
+// The registry client needs the credentials themselves instead of an Authenticator, because it uses them to obtain
+// a registry token first.
+func OCI(authenticator Authenticator) authn.Authenticator {
+	switch a := authenticator.(type) {
+	case *basicAuthenticator:
+		return &authn.Basic{Username: a.username, Password: a.password}
+	case *bearerAuthenticator:
+		return &authn.Bearer{Token: a.token}
+	default:
+		return authn.Anonymous
+	}
+}
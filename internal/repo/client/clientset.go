@@ -13,7 +13,9 @@ import (
 	"github.com/glasskube/glasskube/internal/adapter"
 	"github.com/glasskube/glasskube/internal/controller/ctrlpkg"
 	"github.com/glasskube/glasskube/internal/repo/client/auth"
+	"github.com/go-git/go-git/v5/plumbing/transport"
 	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
 )
 
 type defaultClientsetClient struct {
@@ -28,6 +30,7 @@ type defaultClientset struct {
 	repoMutex               sync.Mutex
 	maxCacheAge             time.Duration
 	clientInfoCheckInterval time.Duration
+	gitCacheDir             string
 }
 
 var _ RepoClientset = &defaultClientset{}
@@ -58,9 +61,21 @@ func NewClientsetWithMaxCacheAge(pkgClient adapter.PackageClientAdapter, k8sClie
 		clients:                 make(map[string]repoClientWithState),
 		maxCacheAge:             maxCacheAge,
 		clientInfoCheckInterval: clientInfoCheckInterval,
+		gitCacheDir:             DefaultGitCacheDir(),
 	}
 }
 
+// NewClientsetWithGitCacheDir returns a RepoClientset that stores clones of git repositories in gitCacheDir.
+func NewClientsetWithGitCacheDir(
+	pkgClient adapter.PackageClientAdapter,
+	k8sClient adapter.KubernetesClientAdapter,
+	gitCacheDir string,
+) RepoClientset {
+	clientset := NewClientset(pkgClient, k8sClient).(*defaultClientset)
+	clientset.gitCacheDir = gitCacheDir
+	return clientset
+}
+
 // ForPackage implements RepoClientset.
 func (d *defaultClientset) ForPackage(pkg ctrlpkg.Package) RepoClient {
 	return d.ForRepoWithName(pkg.GetSpec().PackageInfo.RepositoryName)
@@ -106,10 +121,12 @@ func (d *defaultClientset) ForRepo(repo v1alpha1.PackageRepository) RepoClient {
 		clientState.lastCheckedRepoSpec = time.Now()
 		return clientState.client
 	} else {
-		if auth, err := d.newAuthenticator(repo); err != nil {
-			return &errorclient{err: fmt.Errorf("invalid auth config: %w", err)}
+		if ok {
+			d.evict(repo.Name)
+		}
+		if client, err := d.newClient(repo); err != nil {
+			return &errorclient{err: err}
 		} else {
-			client := New(repo.Spec.Url, auth, d.maxCacheAge)
 			d.clients[repo.Name] = repoClientWithState{
 				client:              client,
 				lastCheckedRepoSpec: time.Now(),
@@ -120,6 +137,61 @@ func (d *defaultClientset) ForRepo(repo v1alpha1.PackageRepository) RepoClient {
 	}
 }
 
+func (d *defaultClientset) newClient(repo v1alpha1.PackageRepository) (RepoClient, error) {
+	if client, err := d.newUnverifiedClient(repo); err != nil {
+		return nil, err
+	} else if len(repo.Spec.TrustedPublicKeys) > 0 {
+		if verifying, err := NewVerifying(client, repo.Spec.TrustedPublicKeys); err != nil {
+			return nil, fmt.Errorf("invalid trusted public keys: %w", err)
+		} else {
+			return verifying, nil
+		}
+	} else {
+		return client, nil
+	}
+}
+
+func (d *defaultClientset) newUnverifiedClient(repo v1alpha1.PackageRepository) (RepoClient, error) {
+	if auth, err := d.newAuthenticator(repo); err != nil {
+		return nil, fmt.Errorf("invalid auth config: %w", err)
+	} else if repo.IsGitRepo() {
+		if gitAuth, err := d.newGitAuthMethod(repo, auth); err != nil {
+			return nil, fmt.Errorf("invalid git auth config: %w", err)
+		} else {
+			return NewGitWithCacheDir(repo.Spec.Url, *repo.Spec.Git, gitAuth, d.maxCacheAge, d.gitCacheDir), nil
+		}
+	} else if repo.IsOCIRepo() {
+		return NewOCI(repo.Spec.Url, auth, d.maxCacheAge), nil
+	} else if repo.IsFileRepo() {
+		return NewFile(repo.Spec.Url), nil
+	} else {
+		return New(repo.Spec.Url, auth, d.maxCacheAge), nil
+	}
+}
+
+func (d *defaultClientset) newGitAuthMethod(
+	repo v1alpha1.PackageRepository,
+	authenticator auth.Authenticator,
+) (transport.AuthMethod, error) {
+	if repo.Spec.Git.SecretRef == nil {
+		if repo.Spec.Auth == nil {
+			return nil, nil
+		}
+		return auth.GitHTTP(authenticator), nil
+	}
+	secret, err := d.client.GetSecret(context.TODO(), repo.Spec.Git.SecretRef.Name, "glasskube-system")
+	if err != nil {
+		return nil, fmt.Errorf("cannot get git credentials: %w", err)
+	}
+	if identity, ok := secret.Data["identity"]; ok {
+		return auth.GitSSH(identity, secret.Data["known_hosts"])
+	} else if username, ok := secret.Data["username"]; ok {
+		return auth.GitBasic(string(username), string(secret.Data["password"])), nil
+	} else {
+		return nil, fmt.Errorf("%v must contain either the key identity or username", secret.Name)
+	}
+}
+
 func (d *defaultClientset) newAuthenticator(repo v1alpha1.PackageRepository) (auth.Authenticator, error) {
 	if repo.Spec.Auth != nil {
 		if repo.Spec.Auth.Basic != nil {
@@ -179,6 +251,49 @@ func (d *defaultClientset) newAuthenticator(repo v1alpha1.PackageRepository) (au
 	return auth.Noop(), nil
 }
 
+// Invalidate implements RepoClientset.
+func (d *defaultClientset) Invalidate(repoName string) {
+	d.repoWithNameMutex.Lock()
+	defer d.repoWithNameMutex.Unlock()
+	d.repoMutex.Lock()
+	defer d.repoMutex.Unlock()
+	d.evict(repoName)
+}
+
+// evict removes the client of the repository with the given name and deletes its cached git clone, unless the clone
+// is still used by the client of another repository.
+// The caller must hold repoMutex.
+func (d *defaultClientset) evict(repoName string) {
+	clientState, ok := d.clients[repoName]
+	if !ok {
+		return
+	}
+	delete(d.clients, repoName)
+	if git := unwrapGitClient(clientState.client); git != nil {
+		for _, other := range d.clients {
+			if otherGit := unwrapGitClient(other.client); otherGit != nil && otherGit.dir == git.dir {
+				return
+			}
+		}
+		if err := git.removeCache(); err != nil {
+			ctrl.Log.WithName("repo-clientset").Error(err, "could not remove git cache", "repository", repoName)
+		}
+	}
+}
+
+func unwrapGitClient(client RepoClient) *gitClient {
+	switch c := client.(type) {
+	case *gitClient:
+		return c
+	case *verifyingClient:
+		return unwrapGitClient(c.RepoClient)
+	case *verifyingFileClient:
+		return unwrapGitClient(c.RepoClient)
+	default:
+		return nil
+	}
+}
+
 // Meta implements RepoClientset.
 func (d *defaultClientset) Meta() RepoMetaclient {
 	return metaclient{clientset: d}
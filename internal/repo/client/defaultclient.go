@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -13,8 +14,11 @@ import (
 	"github.com/glasskube/glasskube/api/v1alpha1"
 	"github.com/glasskube/glasskube/internal/contenttype"
 	"github.com/glasskube/glasskube/internal/httperror"
+	"github.com/glasskube/glasskube/internal/metrics"
 	"github.com/glasskube/glasskube/internal/repo/client/auth"
 	"github.com/glasskube/glasskube/internal/repo/types"
+	"github.com/glasskube/glasskube/internal/signature"
+	"github.com/glasskube/glasskube/internal/tracing"
 	"k8s.io/apimachinery/pkg/util/yaml"
 )
 
@@ -22,8 +26,10 @@ type defaultClient struct {
 	auth.Authenticator
 	url         string
 	maxCacheAge time.Duration
-	cache       sync.Map
+	cache       *sync.Map
 	debug       bool
+	// ctx is used for all requests. If it is nil, context.Background() is used.
+	ctx context.Context
 }
 
 type cacheItem struct {
@@ -33,7 +39,7 @@ type cacheItem struct {
 }
 
 func New(url string, authenticator auth.Authenticator, maxCacheAge time.Duration) *defaultClient {
-	return &defaultClient{url: url, Authenticator: authenticator, maxCacheAge: maxCacheAge}
+	return &defaultClient{url: url, Authenticator: authenticator, maxCacheAge: maxCacheAge, cache: &sync.Map{}}
 }
 
 func NewDebug(url string, authenticator auth.Authenticator, maxCacheAge time.Duration) *defaultClient {
@@ -43,6 +49,13 @@ func NewDebug(url string, authenticator auth.Authenticator, maxCacheAge time.Dur
 }
 
 var _ RepoClient = &defaultClient{}
+var _ SignedPackageManifestFetcher = &defaultClient{}
+
+func (c *defaultClient) withContext(ctx context.Context) RepoClient {
+	copied := *c
+	copied.ctx = ctx
+	return &copied
+}
 
 // FetchLatestPackageManifest implements repo.RepoClient.
 func (c *defaultClient) FetchLatestPackageManifest(name string, target *v1alpha1.PackageManifest) (
@@ -100,13 +113,36 @@ func (c *defaultClient) GetLatestVersion(pkgName string) (string, error) {
 	return "", nil
 }
 
+// FetchSignedPackageManifest implements SignedPackageManifestFetcher.
+func (c *defaultClient) FetchSignedPackageManifest(name, version string) ([]byte, []byte, error) {
+	if url, err := c.GetPackageManifestURL(name, version); err != nil {
+		return nil, nil, err
+	} else if manifest, err := c.fetch(url, true); err != nil {
+		return nil, nil, err
+	} else if sig, err := c.fetch(url+signature.FileExtension, false); err != nil {
+		return nil, nil, err
+	} else {
+		return manifest, sig, nil
+	}
+}
+
 func (c *defaultClient) fetchYAMLOrJSON(url string, target any) error {
+	if bytes, err := c.fetch(url, true); err != nil {
+		return err
+	} else {
+		return yaml.Unmarshal(bytes, target)
+	}
+}
+
+// fetch returns the response body of a GET request to url. If jsonOrYaml is true, the response must have a JSON or
+// YAML content type and must be valid YAML.
+func (c *defaultClient) fetch(url string, jsonOrYaml bool) (_ []byte, err error) {
 	cached := &cacheItem{}
 	if c, hit := c.cache.LoadOrStore(url, cached); hit {
 		if c, ok := c.(*cacheItem); ok {
 			cached = c
 		} else {
-			return errors.New("unexpected cache type")
+			return nil, errors.New("unexpected cache type")
 		}
 	}
 
@@ -115,42 +151,61 @@ func (c *defaultClient) fetchYAMLOrJSON(url string, target any) error {
 
 	// try again after acquiring the mutex
 	if cached.updated.Add(c.maxCacheAge).After(time.Now()) {
+		metrics.RepositoryCacheHitsTotal.WithLabelValues(c.url).Inc()
 		if c.debug {
 			fmt.Fprintln(os.Stderr, "cache hit (after lock)", url)
 		}
-		return yaml.Unmarshal(cached.bytes, target)
+		return cached.bytes, nil
 	}
 
 	if c.debug {
 		fmt.Fprintln(os.Stderr, "cache miss", url)
 	}
 
-	request, err := http.NewRequest(http.MethodGet, url, nil)
+	ctx, span := tracing.Start(contextOrBackground(c.ctx), "fetch repository file",
+		tracing.AttributeRepository.String(c.url), tracing.AttributeURL.String(url))
+	defer func() { tracing.End(span, err) }()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	c.Authenticate(request)
-	request.Header.Add("Accept", contenttype.MediaTypeJSON)
-	request.Header.Add("Accept", contenttype.MediaTypeYAML)
+	if jsonOrYaml {
+		request.Header.Add("Accept", contenttype.MediaTypeJSON)
+		request.Header.Add("Accept", contenttype.MediaTypeYAML)
+	}
 	resp, err := httperror.CheckResponse(http.DefaultClient.Do(request))
 	if err != nil {
-		return fmt.Errorf("failed to fetch %v: %w", url, err)
+		metrics.RepositoryFetchTotal.WithLabelValues(c.url, "failure").Inc()
+		return nil, fmt.Errorf("failed to fetch %v: %w", url, err)
 	}
+	metrics.RepositoryFetchTotal.WithLabelValues(c.url, "success").Inc()
 	defer func() { _ = resp.Body.Close() }()
 
-	if err := contenttype.IsJsonOrYaml(resp); err != nil {
-		return fmt.Errorf("could not decode %v: %w", url, err)
+	if jsonOrYaml {
+		if err := contenttype.IsJsonOrYaml(resp); err != nil {
+			return nil, fmt.Errorf("could not decode %v: %w", url, err)
+		}
 	}
 
 	if bytes, err := io.ReadAll(resp.Body); err != nil {
-		return err
-	} else if err := yaml.Unmarshal(bytes, target); err != nil {
-		return err
+		return nil, err
+	} else if err := validateYAML(bytes, jsonOrYaml); err != nil {
+		return nil, err
 	} else {
 		cached.bytes = bytes
 		cached.updated = time.Now()
+		return bytes, nil
+	}
+}
+
+func validateYAML(bytes []byte, jsonOrYaml bool) error {
+	if !jsonOrYaml {
 		return nil
 	}
+	var target any
+	return yaml.Unmarshal(bytes, &target)
 }
 
 func (c *defaultClient) getPackageRepoIndexURL() (string, error) {
@@ -8,6 +8,7 @@ import (
 	"github.com/glasskube/glasskube/internal/repo/client"
 	"github.com/glasskube/glasskube/internal/repo/client/auth"
 	"github.com/glasskube/glasskube/internal/repo/types"
+	"github.com/glasskube/glasskube/internal/semver"
 )
 
 func ClientsetWithClient(client *fakeClient) *fakeClientset {
@@ -47,6 +48,9 @@ func (f *fakeClientset) ForPackage(pkg ctrlpkg.Package) client.RepoClient {
 	return f.Client
 }
 
+// Invalidate implements client.RepoClientset.
+func (f *fakeClientset) Invalidate(repoName string) {}
+
 var _ client.RepoClientset = &fakeClientset{}
 
 // fakeClient is a mock implementation of RepoClient for use in tests
@@ -114,7 +118,9 @@ func (f *fakeClient) FetchPackageIndex(name string, target *types.PackageIndex)
 	if versions, ok := f.Packages[name]; ok {
 		var result types.PackageIndex
 		for v := range versions {
-			result.LatestVersion = v
+			if result.LatestVersion == "" || semver.IsUpgradable(result.LatestVersion, v) {
+				result.LatestVersion = v
+			}
 			result.Versions = append(result.Versions, types.PackageIndexItem{Version: v})
 		}
 		*target = result
@@ -140,7 +146,9 @@ func (f *fakeClient) FetchPackageRepoIndex(target *types.PackageRepoIndex) error
 	for pkg, versions := range f.Packages {
 		item := types.PackageRepoIndexItem{Name: pkg}
 		for v := range versions {
-			item.LatestVersion = v
+			if item.LatestVersion == "" || semver.IsUpgradable(item.LatestVersion, v) {
+				item.LatestVersion = v
+			}
 		}
 		result.Packages = append(result.Packages, item)
 	}
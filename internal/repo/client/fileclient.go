@@ -0,0 +1,129 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/glasskube/glasskube/api/v1alpha1"
+	"github.com/glasskube/glasskube/internal/repo/client/auth"
+	"github.com/glasskube/glasskube/internal/repo/types"
+	"github.com/glasskube/glasskube/internal/signature"
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// fileClient is a RepoClient for package repositories in a local directory, for example an imported bundle.
+// The directory uses the same layout as a repository served over HTTP.
+type fileClient struct {
+	// Package files are not served over HTTP, so requests need no authentication.
+	auth.NoopAuthenticator
+	dir string
+}
+
+func NewFile(url string) *fileClient {
+	return &fileClient{dir: filepath.FromSlash(strings.TrimPrefix(url, v1alpha1.FileRepoUrlPrefix))}
+}
+
+var _ RepoClient = &fileClient{}
+var _ PackageFileFetcher = &fileClient{}
+var _ SignedPackageManifestFetcher = &fileClient{}
+
+// FetchLatestPackageManifest implements RepoClient.
+func (c *fileClient) FetchLatestPackageManifest(name string, target *v1alpha1.PackageManifest) (
+	version string, err error,
+) {
+	var versions types.PackageIndex
+	if err = c.FetchPackageIndex(name, &versions); err != nil {
+		return
+	} else {
+		version = versions.LatestVersion
+	}
+	err = c.FetchPackageManifest(name, version, target)
+	return
+}
+
+// FetchPackageManifest implements RepoClient.
+func (c *fileClient) FetchPackageManifest(name string, version string, target *v1alpha1.PackageManifest) error {
+	return c.readYAMLOrJSON(path.Join(name, version, "package.yaml"), target)
+}
+
+// FetchPackageIndex implements RepoClient.
+func (c *fileClient) FetchPackageIndex(name string, target *types.PackageIndex) error {
+	return c.readYAMLOrJSON(path.Join(name, "versions.yaml"), target)
+}
+
+// FetchPackageRepoIndex implements RepoClient.
+func (c *fileClient) FetchPackageRepoIndex(target *types.PackageRepoIndex) error {
+	return c.readYAMLOrJSON("index.yaml", target)
+}
+
+// FetchPackageFile implements PackageFileFetcher.
+// Only files inside the directory of the package version can be read.
+func (c *fileClient) FetchPackageFile(name, version, filePath string) ([]byte, error) {
+	if cleanPath := path.Clean(filePath); !isLocalPath(cleanPath) {
+		return nil, fmt.Errorf("invalid path %v", filePath)
+	} else {
+		return c.readFile(path.Join(name, version, cleanPath))
+	}
+}
+
+// FetchSignedPackageManifest implements SignedPackageManifestFetcher.
+func (c *fileClient) FetchSignedPackageManifest(name, version string) ([]byte, []byte, error) {
+	manifestPath := path.Join(name, version, "package.yaml")
+	if manifest, err := c.readFile(manifestPath); err != nil {
+		return nil, nil, err
+	} else if sig, err := c.readFile(manifestPath + signature.FileExtension); err != nil {
+		return nil, nil, err
+	} else {
+		return manifest, sig, nil
+	}
+}
+
+// GetLatestVersion implements RepoClient.
+func (c *fileClient) GetLatestVersion(pkgName string) (string, error) {
+	var idx types.PackageRepoIndex
+	if err := c.FetchPackageRepoIndex(&idx); err != nil {
+		return "", err
+	}
+	for _, pkg := range idx.Packages {
+		if pkg.Name == pkgName {
+			return pkg.LatestVersion, nil
+		}
+	}
+	return "", nil
+}
+
+// GetPackageManifestURL implements RepoClient.
+func (c *fileClient) GetPackageManifestURL(name, version string) (string, error) {
+	return v1alpha1.FileRepoUrlPrefix + filepath.ToSlash(filepath.Join(c.dir, name, version, "package.yaml")), nil
+}
+
+func (c *fileClient) readYAMLOrJSON(filePath string, target any) error {
+	if data, err := c.readFile(filePath); err != nil {
+		return err
+	} else {
+		return yaml.Unmarshal(data, target)
+	}
+}
+
+// readFile reads a file relative to the repository directory. filePath must not leave the repository directory.
+func (c *fileClient) readFile(filePath string) ([]byte, error) {
+	if cleanPath := path.Clean(filePath); !isLocalPath(cleanPath) {
+		return nil, fmt.Errorf("invalid path %v", filePath)
+	} else if data, err := os.ReadFile(filepath.Join(c.dir, filepath.FromSlash(cleanPath))); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("%v not found in repository %v", filePath, c.dir)
+		}
+		return nil, err
+	} else {
+		return data, nil
+	}
+}
+
+// isLocalPath returns true if the cleaned path cleanPath is a relative path that does not start with "..".
+func isLocalPath(cleanPath string) bool {
+	return !path.IsAbs(cleanPath) && cleanPath != ".." && !strings.HasPrefix(cleanPath, "../")
+}
@@ -0,0 +1,56 @@
+package client
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/glasskube/glasskube/api/v1alpha1"
+	"github.com/glasskube/glasskube/internal/repo/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("fileClient", func() {
+	var dir string
+
+	BeforeEach(func() {
+		dir = GinkgoT().TempDir()
+		files := map[string]string{
+			"index.yaml":                   "packages:\n  - name: foo\n    latestVersion: v1.0.0\n",
+			"foo/versions.yaml":            "versions:\n  - version: v1.0.0\nlatestVersion: v1.0.0\n",
+			"foo/v1.0.0/package.yaml":      "name: foo\nshortDescription: local\n",
+			"foo/v1.0.0/manifests/cm.yaml": "apiVersion: v1\nkind: ConfigMap\n",
+			"foo/v1.0.0/package.yaml.sig":  "c2ln",
+			"secret.yaml":                  "not part of the repository",
+		}
+		for name, content := range files {
+			path := filepath.Join(dir, name)
+			Expect(os.MkdirAll(filepath.Dir(path), 0755)).To(Succeed())
+			Expect(os.WriteFile(path, []byte(content), 0644)).To(Succeed())
+		}
+	})
+
+	It("should read the repository from the directory", func() {
+		client := NewFile(v1alpha1.FileRepoUrlPrefix + dir)
+		var index types.PackageRepoIndex
+		Expect(client.FetchPackageRepoIndex(&index)).To(Succeed())
+		Expect(index.Packages).To(HaveLen(1))
+		var manifest v1alpha1.PackageManifest
+		version, err := client.FetchLatestPackageManifest("foo", &manifest)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(version).To(Equal("v1.0.0"))
+		Expect(manifest.ShortDescription).To(Equal("local"))
+		data, err := client.FetchPackageFile("foo", version, "./manifests/cm.yaml")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(data)).To(ContainSubstring("ConfigMap"))
+		_, sig, err := client.FetchSignedPackageManifest("foo", version)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(sig)).To(Equal("c2ln"))
+	})
+
+	It("should not read files outside of the package version", func() {
+		client := NewFile(v1alpha1.FileRepoUrlPrefix + dir)
+		_, err := client.FetchPackageFile("foo", "v1.0.0", "../../secret.yaml")
+		Expect(err).To(MatchError(ContainSubstring("invalid path")))
+	})
+})
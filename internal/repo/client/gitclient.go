@@ -0,0 +1,296 @@
+package client
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/glasskube/glasskube/api/v1alpha1"
+	"github.com/glasskube/glasskube/internal/repo/client/auth"
+	"github.com/glasskube/glasskube/internal/repo/types"
+	"github.com/glasskube/glasskube/internal/signature"
+	"github.com/glasskube/glasskube/internal/tracing"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+const gitRemoteName = "origin"
+
+// gitDirMutexes guards concurrent access to the cache directories, since multiple clients may use the same directory.
+var gitDirMutexes sync.Map
+
+// gitClient is a RepoClient for package repositories that are stored in a git repository.
+// The repository is fetched into a bare repository in a cache directory and files are read from the resolved commit
+// directly, without a worktree.
+type gitClient struct {
+	// Package files are not served over HTTP, so requests need no authentication.
+	auth.NoopAuthenticator
+	url         string
+	ref         string
+	path        string
+	gitAuth     transport.AuthMethod
+	dir         string
+	maxCacheAge time.Duration
+	mutex       *sync.Mutex
+	*gitState
+	// ctx is used for all requests. If it is nil, context.Background() is used.
+	ctx context.Context
+}
+
+// gitState is shared by a gitClient and all of its copies that are bound to a context. It is guarded by the mutex.
+type gitState struct {
+	repo        *git.Repository
+	commit      *object.Commit
+	lastFetched time.Time
+}
+
+// DefaultGitCacheDir returns the directory in which git repositories are cached if no other directory is configured.
+func DefaultGitCacheDir() string {
+	return filepath.Join(os.TempDir(), "glasskube-git")
+}
+
+func NewGit(url string, spec v1alpha1.PackageRepositoryGitSpec, gitAuth transport.AuthMethod,
+	maxCacheAge time.Duration) *gitClient {
+	return NewGitWithCacheDir(url, spec, gitAuth, maxCacheAge, DefaultGitCacheDir())
+}
+
+// NewGitWithCacheDir returns a gitClient that stores its clone in a subdirectory of cacheDir.
+// Clients share a clone only if they use the same url, ref and credentials.
+func NewGitWithCacheDir(url string, spec v1alpha1.PackageRepositoryGitSpec, gitAuth transport.AuthMethod,
+	maxCacheAge time.Duration, cacheDir string) *gitClient {
+	dir := filepath.Join(cacheDir, gitCacheKey(url, spec.Ref, auth.GitIdentity(gitAuth)))
+	mutex, _ := gitDirMutexes.LoadOrStore(dir, &sync.Mutex{})
+	return &gitClient{
+		url:         url,
+		ref:         spec.Ref,
+		path:        spec.Path,
+		gitAuth:     gitAuth,
+		dir:         dir,
+		maxCacheAge: maxCacheAge,
+		mutex:       mutex.(*sync.Mutex),
+		gitState:    &gitState{},
+	}
+}
+
+var _ RepoClient = &gitClient{}
+var _ PackageFileFetcher = &gitClient{}
+var _ SignedPackageManifestFetcher = &gitClient{}
+
+func (c *gitClient) withContext(ctx context.Context) RepoClient {
+	copied := *c
+	copied.ctx = ctx
+	return &copied
+}
+
+// FetchLatestPackageManifest implements RepoClient.
+func (c *gitClient) FetchLatestPackageManifest(name string, target *v1alpha1.PackageManifest) (
+	version string, err error,
+) {
+	var versions types.PackageIndex
+	if err = c.FetchPackageIndex(name, &versions); err != nil {
+		return
+	} else {
+		version = versions.LatestVersion
+	}
+	err = c.FetchPackageManifest(name, version, target)
+	return
+}
+
+// FetchPackageManifest implements RepoClient.
+func (c *gitClient) FetchPackageManifest(name string, version string, target *v1alpha1.PackageManifest) error {
+	return c.readYAMLOrJSON(path.Join(name, version, "package.yaml"), target)
+}
+
+// FetchPackageIndex implements RepoClient.
+func (c *gitClient) FetchPackageIndex(name string, target *types.PackageIndex) error {
+	return c.readYAMLOrJSON(path.Join(name, "versions.yaml"), target)
+}
+
+// FetchPackageRepoIndex implements RepoClient.
+func (c *gitClient) FetchPackageRepoIndex(target *types.PackageRepoIndex) error {
+	return c.readYAMLOrJSON("index.yaml", target)
+}
+
+// FetchPackageFile implements PackageFileFetcher.
+func (c *gitClient) FetchPackageFile(name, version, filePath string) ([]byte, error) {
+	return c.readFile(path.Join(name, version, filePath))
+}
+
+// FetchSignedPackageManifest implements SignedPackageManifestFetcher.
+func (c *gitClient) FetchSignedPackageManifest(name, version string) ([]byte, []byte, error) {
+	manifestPath := path.Join(name, version, "package.yaml")
+	if manifest, err := c.readFile(manifestPath); err != nil {
+		return nil, nil, err
+	} else if sig, err := c.readFile(manifestPath + signature.FileExtension); err != nil {
+		return nil, nil, err
+	} else {
+		return manifest, sig, nil
+	}
+}
+
+// GetLatestVersion implements RepoClient.
+func (c *gitClient) GetLatestVersion(pkgName string) (string, error) {
+	var idx types.PackageRepoIndex
+	if err := c.FetchPackageRepoIndex(&idx); err != nil {
+		return "", err
+	}
+	for _, pkg := range idx.Packages {
+		if pkg.Name == pkgName {
+			return pkg.LatestVersion, nil
+		}
+	}
+	return "", nil
+}
+
+// GetPackageManifestURL implements RepoClient.
+// The returned URL uses the same notation as remote kustomize targets: <repository>//<path>?ref=<ref>
+func (c *gitClient) GetPackageManifestURL(name, version string) (string, error) {
+	result := c.url + "//" + path.Join(c.path, name, version, "package.yaml")
+	if c.ref != "" {
+		result += "?ref=" + url.QueryEscape(c.ref)
+	}
+	return result, nil
+}
+
+func (c *gitClient) readYAMLOrJSON(filePath string, target any) error {
+	if data, err := c.readFile(filePath); err != nil {
+		return err
+	} else {
+		return yaml.Unmarshal(data, target)
+	}
+}
+
+func (c *gitClient) readFile(filePath string) ([]byte, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if err := c.update(); err != nil {
+		return nil, fmt.Errorf("failed to fetch git repository %v: %w", c.url, err)
+	}
+
+	fullPath := path.Join(c.path, filePath)
+	if tree, err := c.commit.Tree(); err != nil {
+		return nil, err
+	} else if file, err := tree.File(fullPath); err != nil {
+		return nil, fmt.Errorf("failed to read %v from git repository %v: %w", fullPath, c.url, err)
+	} else if contents, err := file.Contents(); err != nil {
+		return nil, err
+	} else {
+		return []byte(contents), nil
+	}
+}
+
+// update fetches the remote and resolves the configured ref if the last fetch is older than maxCacheAge.
+// The caller must hold the mutex.
+func (c *gitClient) update() (err error) {
+	if c.commit != nil && c.lastFetched.Add(c.maxCacheAge).After(time.Now()) {
+		return nil
+	}
+
+	ctx, span := tracing.Start(contextOrBackground(c.ctx), "fetch git repository",
+		tracing.AttributeURL.String(c.url))
+	defer func() { tracing.End(span, err) }()
+
+	if c.repo == nil {
+		if repo, err := c.openOrInit(); err != nil {
+			return err
+		} else {
+			c.repo = repo
+		}
+	}
+
+	err = c.repo.FetchContext(ctx, &git.FetchOptions{
+		RemoteName: gitRemoteName,
+		RemoteURL:  c.url,
+		RefSpecs:   c.refSpecs(),
+		Auth:       c.gitAuth,
+		Tags:       git.NoTags,
+		Force:      true,
+	})
+	if err != nil && !errors.Is(err, git.NoErrAlreadyUpToDate) {
+		return err
+	}
+
+	if commit, err := c.resolveCommit(); err != nil {
+		return err
+	} else {
+		c.commit = commit
+		c.lastFetched = time.Now()
+		return nil
+	}
+}
+
+func (c *gitClient) openOrInit() (*git.Repository, error) {
+	if repo, err := git.PlainOpen(c.dir); err == nil {
+		return repo, nil
+	} else if !errors.Is(err, git.ErrRepositoryNotExists) {
+		return nil, err
+	}
+	if err := os.MkdirAll(c.dir, 0o700); err != nil {
+		return nil, err
+	}
+	if repo, err := git.PlainInit(c.dir, true); err != nil {
+		return nil, err
+	} else if _, err := repo.CreateRemote(&config.RemoteConfig{Name: gitRemoteName, URLs: []string{c.url}}); err != nil {
+		return nil, err
+	} else {
+		return repo, nil
+	}
+}
+
+func (c *gitClient) refSpecs() []config.RefSpec {
+	if c.ref == "" {
+		return []config.RefSpec{"+HEAD:refs/remotes/origin/HEAD"}
+	} else {
+		return []config.RefSpec{
+			"+refs/heads/*:refs/remotes/origin/*",
+			"+refs/tags/*:refs/tags/*",
+		}
+	}
+}
+
+func (c *gitClient) resolveCommit() (*object.Commit, error) {
+	var candidates []plumbing.Revision
+	if c.ref == "" {
+		candidates = []plumbing.Revision{"refs/remotes/origin/HEAD"}
+	} else {
+		candidates = []plumbing.Revision{
+			plumbing.Revision("refs/remotes/origin/" + c.ref),
+			plumbing.Revision("refs/tags/" + c.ref),
+			plumbing.Revision(c.ref),
+		}
+	}
+	for _, candidate := range candidates {
+		if hash, err := c.repo.ResolveRevision(candidate); err == nil {
+			return c.repo.CommitObject(*hash)
+		}
+	}
+	return nil, fmt.Errorf("could not resolve ref %q", c.ref)
+}
+
+// removeCache deletes the clone of the repository. It is created again by the next request.
+func (c *gitClient) removeCache() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.repo = nil
+	c.commit = nil
+	return os.RemoveAll(c.dir)
+}
+
+func gitCacheKey(url, ref, identity string) string {
+	hash := sha256.Sum256([]byte(strings.Join([]string{url, ref, identity}, "\x00")))
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,166 @@
+package client
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/glasskube/glasskube/api/v1alpha1"
+	"github.com/glasskube/glasskube/internal/repo/client/auth"
+	"github.com/glasskube/glasskube/internal/repo/types"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("gitClient", func() {
+	var workDir, bareDir, cacheDir string
+	var workRepo *git.Repository
+
+	writeAndCommit := func(files map[string]string) {
+		worktree, err := workRepo.Worktree()
+		Expect(err).NotTo(HaveOccurred())
+		for name, content := range files {
+			Expect(os.MkdirAll(filepath.Dir(filepath.Join(workDir, name)), 0o755)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(workDir, name), []byte(content), 0o644)).To(Succeed())
+			_, err := worktree.Add(name)
+			Expect(err).NotTo(HaveOccurred())
+		}
+		_, err = worktree.Commit("update", &git.CommitOptions{
+			Author: &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()},
+		})
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	push := func() {
+		Expect(workRepo.Push(&git.PushOptions{
+			RemoteName: "origin",
+			RefSpecs:   []config.RefSpec{"+refs/heads/*:refs/heads/*"},
+		})).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		workDir = GinkgoT().TempDir()
+		bareDir = GinkgoT().TempDir()
+		cacheDir = GinkgoT().TempDir()
+		var err error
+		workRepo, err = git.PlainInit(workDir, false)
+		Expect(err).NotTo(HaveOccurred())
+		writeAndCommit(map[string]string{
+			"packages/index.yaml":                "packages:\n  - name: foo\n    latestVersion: v1.0.0\n",
+			"packages/foo/versions.yaml":         "versions:\n  - version: v1.0.0\nlatestVersion: v1.0.0\n",
+			"packages/foo/v1.0.0/package.yaml":   "name: foo\nshortDescription: first\n",
+			"packages/foo/v1.0.0/manifests.yaml": "apiVersion: v1\nkind: ConfigMap\n",
+		})
+		_, err = workRepo.CreateTag("v1", mustHead(workRepo), nil)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = git.PlainClone(bareDir, true, &git.CloneOptions{URL: workDir})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = workRepo.CreateRemote(remoteConfig(bareDir))
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should read the repository index from the default branch", func() {
+		client := NewGitWithCacheDir(bareDir, v1alpha1.PackageRepositoryGitSpec{Path: "packages"}, nil, time.Minute,
+			cacheDir)
+		var index types.PackageRepoIndex
+		Expect(client.FetchPackageRepoIndex(&index)).To(Succeed())
+		Expect(index.Packages).To(HaveLen(1))
+		Expect(index.Packages[0].Name).To(Equal("foo"))
+		version, err := client.GetLatestVersion("foo")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(version).To(Equal("v1.0.0"))
+	})
+
+	It("should read the package manifest and files", func() {
+		client := NewGitWithCacheDir(bareDir, v1alpha1.PackageRepositoryGitSpec{Path: "packages"}, nil, time.Minute,
+			cacheDir)
+		var manifest v1alpha1.PackageManifest
+		version, err := client.FetchLatestPackageManifest("foo", &manifest)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(version).To(Equal("v1.0.0"))
+		Expect(manifest.ShortDescription).To(Equal("first"))
+		data, err := client.FetchPackageFile("foo", "v1.0.0", "./manifests.yaml")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(data)).To(ContainSubstring("ConfigMap"))
+	})
+
+	It("should use the configured ref", func() {
+		writeAndCommit(map[string]string{"packages/foo/v1.0.0/package.yaml": "name: foo\nshortDescription: second\n"})
+		push()
+
+		var manifest v1alpha1.PackageManifest
+		tagClient := NewGitWithCacheDir(bareDir, v1alpha1.PackageRepositoryGitSpec{Path: "packages", Ref: "v1"}, nil,
+			time.Minute, cacheDir)
+		Expect(tagClient.FetchPackageManifest("foo", "v1.0.0", &manifest)).To(Succeed())
+		Expect(manifest.ShortDescription).To(Equal("first"))
+
+		branchClient := NewGitWithCacheDir(bareDir, v1alpha1.PackageRepositoryGitSpec{Path: "packages", Ref: "master"},
+			nil, time.Minute, cacheDir)
+		Expect(branchClient.FetchPackageManifest("foo", "v1.0.0", &manifest)).To(Succeed())
+		Expect(manifest.ShortDescription).To(Equal("second"))
+	})
+
+	It("should fetch new commits after the cache expired", func() {
+		client := NewGitWithCacheDir(bareDir, v1alpha1.PackageRepositoryGitSpec{Path: "packages"}, nil, 0, cacheDir)
+		var manifest v1alpha1.PackageManifest
+		Expect(client.FetchPackageManifest("foo", "v1.0.0", &manifest)).To(Succeed())
+		Expect(manifest.ShortDescription).To(Equal("first"))
+
+		writeAndCommit(map[string]string{"packages/foo/v1.0.0/package.yaml": "name: foo\nshortDescription: second\n"})
+		push()
+
+		Expect(client.FetchPackageManifest("foo", "v1.0.0", &manifest)).To(Succeed())
+		Expect(manifest.ShortDescription).To(Equal("second"))
+	})
+
+	It("should use separate clones for different refs and credentials", func() {
+		spec := v1alpha1.PackageRepositoryGitSpec{Path: "packages"}
+		client := NewGitWithCacheDir(bareDir, spec, nil, time.Minute, cacheDir)
+		Expect(NewGitWithCacheDir(bareDir, spec, nil, time.Minute, cacheDir).dir).To(Equal(client.dir))
+		Expect(NewGitWithCacheDir(bareDir, v1alpha1.PackageRepositoryGitSpec{Path: "packages", Ref: "v1"}, nil,
+			time.Minute, cacheDir).dir).NotTo(Equal(client.dir))
+		Expect(NewGitWithCacheDir(bareDir, spec, auth.GitBasic("user", "secret"), time.Minute, cacheDir).dir).
+			NotTo(Equal(client.dir))
+	})
+
+	It("should remove the clone when the repository is invalidated", func() {
+		clientset := NewClientsetWithGitCacheDir(nil, nil, cacheDir)
+		repo := v1alpha1.PackageRepository{
+			ObjectMeta: metav1.ObjectMeta{Name: "git"},
+			Spec: v1alpha1.PackageRepositorySpec{
+				Url: bareDir,
+				Git: &v1alpha1.PackageRepositoryGitSpec{Path: "packages"},
+			},
+		}
+		var index types.PackageRepoIndex
+		Expect(clientset.ForRepo(repo).FetchPackageRepoIndex(&index)).To(Succeed())
+		Expect(os.ReadDir(cacheDir)).To(HaveLen(1))
+
+		clientset.Invalidate(repo.Name)
+		Expect(os.ReadDir(cacheDir)).To(BeEmpty())
+		Expect(clientset.ForRepo(repo).FetchPackageRepoIndex(&index)).To(Succeed())
+		Expect(index.Packages).To(HaveLen(1))
+	})
+
+	It("should return an error for an unknown ref", func() {
+		client := NewGitWithCacheDir(bareDir, v1alpha1.PackageRepositoryGitSpec{Ref: "does-not-exist"}, nil,
+			time.Minute, cacheDir)
+		var index types.PackageRepoIndex
+		Expect(client.FetchPackageRepoIndex(&index)).NotTo(Succeed())
+	})
+})
+
+func mustHead(repo *git.Repository) plumbing.Hash {
+	head, err := repo.Head()
+	Expect(err).NotTo(HaveOccurred())
+	return head.Hash()
+}
+
+func remoteConfig(url string) *config.RemoteConfig {
+	return &config.RemoteConfig{Name: "origin", URLs: []string{url}}
+}
@@ -0,0 +1,249 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/glasskube/glasskube/api/v1alpha1"
+	"github.com/glasskube/glasskube/internal/repo/client/auth"
+	"github.com/glasskube/glasskube/internal/repo/types"
+	"github.com/glasskube/glasskube/internal/signature"
+	"github.com/glasskube/glasskube/internal/tracing"
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+const (
+	// OCIFileTitleAnnotation is the layer annotation that contains the file name of a layer in a package artifact.
+	OCIFileTitleAnnotation = "org.opencontainers.image.title"
+	// OCIRepoIndexTag is the tag of the artifact containing the "index.yaml" file.
+	OCIRepoIndexTag = "index"
+	// OCIPackageIndexTag is the tag of the artifact containing the "versions.yaml" file of a package.
+	OCIPackageIndexTag = "versions"
+)
+
+// ociClient is a RepoClient for package repositories that are stored as artifacts in an OCI registry.
+//
+// For a repository URL "oci://registry/path", the following artifacts are used:
+//   - "registry/path:index" contains the "index.yaml" file
+//   - "registry/path/<name>:versions" contains the "versions.yaml" file of a package
+//   - "registry/path/<name>:<version>" contains the "package.yaml" file and all other files of a package version
+//
+// Each file is stored as-is in a separate layer, annotated with its file name using OCIFileTitleAnnotation.
+// This is the layout that "oras push" uses for files.
+type ociClient struct {
+	auth.Authenticator
+	repository  string
+	ociAuth     authn.Authenticator
+	maxCacheAge time.Duration
+	cache       *sync.Map
+	// ctx is used for all requests. If it is nil, context.Background() is used.
+	ctx context.Context
+}
+
+type ociCacheItem struct {
+	files   map[string][]byte
+	updated time.Time
+	mutex   sync.Mutex
+}
+
+func NewOCI(url string, authenticator auth.Authenticator, maxCacheAge time.Duration) *ociClient {
+	return &ociClient{
+		Authenticator: authenticator,
+		repository:    strings.TrimSuffix(strings.TrimPrefix(url, v1alpha1.OCIRepoUrlPrefix), "/"),
+		ociAuth:       auth.OCI(authenticator),
+		maxCacheAge:   maxCacheAge,
+		cache:         &sync.Map{},
+	}
+}
+
+var _ RepoClient = &ociClient{}
+var _ PackageFileFetcher = &ociClient{}
+var _ SignedPackageManifestFetcher = &ociClient{}
+
+func (c *ociClient) withContext(ctx context.Context) RepoClient {
+	copied := *c
+	copied.ctx = ctx
+	return &copied
+}
+
+// FetchLatestPackageManifest implements RepoClient.
+func (c *ociClient) FetchLatestPackageManifest(name string, target *v1alpha1.PackageManifest) (
+	version string, err error,
+) {
+	var versions types.PackageIndex
+	if err = c.FetchPackageIndex(name, &versions); err != nil {
+		return
+	} else {
+		version = versions.LatestVersion
+	}
+	err = c.FetchPackageManifest(name, version, target)
+	return
+}
+
+// FetchPackageManifest implements RepoClient.
+func (c *ociClient) FetchPackageManifest(name string, version string, target *v1alpha1.PackageManifest) error {
+	return c.fetchYAMLOrJSON(c.packageVersionRef(name, version), "package.yaml", target)
+}
+
+// FetchPackageIndex implements RepoClient.
+func (c *ociClient) FetchPackageIndex(name string, target *types.PackageIndex) error {
+	return c.fetchYAMLOrJSON(path.Join(c.repository, name)+":"+OCIPackageIndexTag, "versions.yaml", target)
+}
+
+// FetchPackageRepoIndex implements RepoClient.
+func (c *ociClient) FetchPackageRepoIndex(target *types.PackageRepoIndex) error {
+	return c.fetchYAMLOrJSON(c.repository+":"+OCIRepoIndexTag, "index.yaml", target)
+}
+
+// FetchPackageFile implements PackageFileFetcher.
+func (c *ociClient) FetchPackageFile(name, version, filePath string) ([]byte, error) {
+	return c.fetchFile(c.packageVersionRef(name, version), path.Clean(filePath))
+}
+
+// FetchSignedPackageManifest implements SignedPackageManifestFetcher.
+// The signature is expected in the same artifact as the manifest.
+func (c *ociClient) FetchSignedPackageManifest(name, version string) ([]byte, []byte, error) {
+	ref := c.packageVersionRef(name, version)
+	if manifest, err := c.fetchFile(ref, "package.yaml"); err != nil {
+		return nil, nil, err
+	} else if sig, err := c.fetchFile(ref, "package.yaml"+signature.FileExtension); err != nil {
+		return nil, nil, err
+	} else {
+		return manifest, sig, nil
+	}
+}
+
+// GetLatestVersion implements RepoClient.
+func (c *ociClient) GetLatestVersion(pkgName string) (string, error) {
+	var idx types.PackageRepoIndex
+	if err := c.FetchPackageRepoIndex(&idx); err != nil {
+		return "", err
+	}
+	for _, pkg := range idx.Packages {
+		if pkg.Name == pkgName {
+			return pkg.LatestVersion, nil
+		}
+	}
+	return "", nil
+}
+
+// GetPackageManifestURL implements RepoClient.
+func (c *ociClient) GetPackageManifestURL(name, version string) (string, error) {
+	return v1alpha1.OCIRepoUrlPrefix + c.packageVersionRef(name, version), nil
+}
+
+// packageVersionRef returns the reference of the artifact for a package version.
+func (c *ociClient) packageVersionRef(name, version string) string {
+	return path.Join(c.repository, name) + ":" + OCIPackageVersionTag(version)
+}
+
+// OCIPackageVersionTag returns the tag of the artifact for a package version.
+// Since "+" is not allowed in tags, it is replaced with "_", as is common for semver versions in OCI registries.
+func OCIPackageVersionTag(version string) string {
+	return strings.ReplaceAll(version, "+", "_")
+}
+
+func (c *ociClient) fetchYAMLOrJSON(ref, fileName string, target any) error {
+	if data, err := c.fetchFile(ref, fileName); err != nil {
+		return err
+	} else {
+		return yaml.Unmarshal(data, target)
+	}
+}
+
+func (c *ociClient) fetchFile(ref, fileName string) ([]byte, error) {
+	cached := &ociCacheItem{}
+	if c, hit := c.cache.LoadOrStore(ref, cached); hit {
+		if c, ok := c.(*ociCacheItem); ok {
+			cached = c
+		} else {
+			return nil, errors.New("unexpected cache type")
+		}
+	}
+
+	cached.mutex.Lock()
+	defer cached.mutex.Unlock()
+
+	if !cached.updated.Add(c.maxCacheAge).After(time.Now()) {
+		if files, err := c.pull(ref); err != nil {
+			return nil, fmt.Errorf("failed to fetch %v: %w", ref, err)
+		} else {
+			cached.files = files
+			cached.updated = time.Now()
+		}
+	}
+
+	if data, ok := cached.files[fileName]; ok {
+		return data, nil
+	} else {
+		return nil, fmt.Errorf("artifact %v does not contain file %v", ref, fileName)
+	}
+}
+
+// pull fetches all layers of the artifact with the given reference and returns their contents by file name.
+func (c *ociClient) pull(ref string) (_ map[string][]byte, err error) {
+	ctx, span := tracing.Start(contextOrBackground(c.ctx), "pull OCI artifact", tracing.AttributeURL.String(ref))
+	defer func() { tracing.End(span, err) }()
+
+	parsedRef, err := name.ParseReference(ref)
+	if err != nil {
+		return nil, err
+	}
+	image, err := remote.Image(parsedRef, remote.WithAuth(c.ociAuth), remote.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	manifest, err := image.Manifest()
+	if err != nil {
+		return nil, err
+	}
+	files := make(map[string][]byte, len(manifest.Layers))
+	for _, descriptor := range manifest.Layers {
+		fileName, ok := descriptor.Annotations[OCIFileTitleAnnotation]
+		if !ok {
+			continue
+		}
+		if layer, err := image.LayerByDigest(descriptor.Digest); err != nil {
+			return nil, err
+		} else if data, err := readLayer(layer.Compressed); err != nil {
+			return nil, fmt.Errorf("failed to read %v: %w", fileName, err)
+		} else {
+			files[path.Clean(fileName)] = data
+		}
+	}
+	return files, nil
+}
+
+// IsOCINotFound returns true if err was caused by an artifact or repository that does not exist in the registry.
+func IsOCINotFound(err error) bool {
+	var transportErr *transport.Error
+	if !errors.As(err, &transportErr) {
+		return false
+	} else if transportErr.StatusCode == http.StatusNotFound {
+		return true
+	}
+	return slices.ContainsFunc(transportErr.Errors, func(diagnostic transport.Diagnostic) bool {
+		return diagnostic.Code == transport.ManifestUnknownErrorCode || diagnostic.Code == transport.NameUnknownErrorCode
+	})
+}
+
+func readLayer(open func() (io.ReadCloser, error)) ([]byte, error) {
+	if reader, err := open(); err != nil {
+		return nil, err
+	} else {
+		defer func() { _ = reader.Close() }()
+		return io.ReadAll(reader)
+	}
+}
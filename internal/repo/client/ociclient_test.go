@@ -0,0 +1,119 @@
+package client
+
+import (
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/glasskube/glasskube/api/v1alpha1"
+	"github.com/glasskube/glasskube/internal/repo/client/auth"
+	"github.com/glasskube/glasskube/internal/repo/types"
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	ocitypes "github.com/google/go-containerregistry/pkg/v1/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ociClient", func() {
+	var server *httptest.Server
+	var repository string
+
+	push := func(ref string, files map[string]string) {
+		image := empty.Image
+		for fileName, content := range files {
+			var err error
+			image, err = mutate.Append(image, mutate.Addendum{
+				Layer:       static.NewLayer([]byte(content), ocitypes.MediaType("application/yaml")),
+				Annotations: map[string]string{OCIFileTitleAnnotation: fileName},
+			})
+			Expect(err).NotTo(HaveOccurred())
+		}
+		parsedRef, err := name.ParseReference(ref)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(remote.Write(parsedRef, image)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		server = httptest.NewServer(registry.New())
+		DeferCleanup(server.Close)
+		repository = strings.TrimPrefix(server.URL, "http://") + "/packages"
+		push(repository+":index", map[string]string{
+			"index.yaml": "packages:\n  - name: foo\n    latestVersion: v1.0.0+1\n",
+		})
+		push(repository+"/foo:versions", map[string]string{
+			"versions.yaml": "versions:\n  - version: v1.0.0+1\nlatestVersion: v1.0.0+1\n",
+		})
+		push(repository+"/foo:v1.0.0_1", map[string]string{
+			"package.yaml":             "name: foo\nshortDescription: first\n",
+			"manifests/manifests.yaml": "apiVersion: v1\nkind: ConfigMap\n",
+		})
+	})
+
+	It("should read the repository index", func() {
+		client := NewOCI(v1alpha1.OCIRepoUrlPrefix+repository, auth.Noop(), time.Minute)
+		var index types.PackageRepoIndex
+		Expect(client.FetchPackageRepoIndex(&index)).To(Succeed())
+		Expect(index.Packages).To(HaveLen(1))
+		Expect(index.Packages[0].Name).To(Equal("foo"))
+		version, err := client.GetLatestVersion("foo")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(version).To(Equal("v1.0.0+1"))
+	})
+
+	It("should read the latest package manifest and files", func() {
+		client := NewOCI(v1alpha1.OCIRepoUrlPrefix+repository+"/", auth.Noop(), time.Minute)
+		var manifest v1alpha1.PackageManifest
+		version, err := client.FetchLatestPackageManifest("foo", &manifest)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(version).To(Equal("v1.0.0+1"))
+		Expect(manifest.ShortDescription).To(Equal("first"))
+		data, err := client.FetchPackageFile("foo", version, "./manifests/manifests.yaml")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(data)).To(ContainSubstring("ConfigMap"))
+		_, err = client.FetchPackageFile("foo", version, "missing.yaml")
+		Expect(err).To(HaveOccurred())
+		url, err := client.GetPackageManifestURL("foo", version)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(url).To(Equal(v1alpha1.OCIRepoUrlPrefix + repository + "/foo:v1.0.0_1"))
+	})
+
+	It("should cache artifacts until maxCacheAge is exceeded", func() {
+		cachingClient := NewOCI(v1alpha1.OCIRepoUrlPrefix+repository, auth.Noop(), time.Minute)
+		nonCachingClient := NewOCI(v1alpha1.OCIRepoUrlPrefix+repository, auth.Noop(), 0)
+		var index types.PackageIndex
+		Expect(cachingClient.FetchPackageIndex("foo", &index)).To(Succeed())
+		Expect(nonCachingClient.FetchPackageIndex("foo", &index)).To(Succeed())
+		push(repository+"/foo:versions", map[string]string{
+			"versions.yaml": "versions:\n  - version: v1.0.0+1\n  - version: v1.1.0\nlatestVersion: v1.1.0\n",
+		})
+		Expect(cachingClient.FetchPackageIndex("foo", &index)).To(Succeed())
+		Expect(index.LatestVersion).To(Equal("v1.0.0+1"))
+		Expect(nonCachingClient.FetchPackageIndex("foo", &index)).To(Succeed())
+		Expect(index.LatestVersion).To(Equal("v1.1.0"))
+	})
+
+	It("should return an error for missing artifacts", func() {
+		client := NewOCI(v1alpha1.OCIRepoUrlPrefix+repository, auth.Noop(), time.Minute)
+		var index types.PackageIndex
+		Expect(client.FetchPackageIndex("bar", &index)).NotTo(Succeed())
+	})
+})
+
+var _ = Describe("auth.OCI", func() {
+	DescribeTable("should use the credentials of the repository",
+		func(authenticator auth.Authenticator, expected authn.AuthConfig) {
+			config, err := auth.OCI(authenticator).Authorization()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(*config).To(Equal(expected))
+		},
+		Entry("noop", auth.Noop(), authn.AuthConfig{}),
+		Entry("basic", auth.Basic("user", "pass"), authn.AuthConfig{Username: "user", Password: "pass"}),
+		Entry("bearer", auth.Bearer("token"), authn.AuthConfig{RegistryToken: "token"}),
+	)
+})
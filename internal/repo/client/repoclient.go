@@ -1,6 +1,8 @@
 package client
 
 import (
+	"context"
+
 	packagesv1alpha1 "github.com/glasskube/glasskube/api/v1alpha1"
 	"github.com/glasskube/glasskube/internal/controller/ctrlpkg"
 	"github.com/glasskube/glasskube/internal/repo/client/auth"
@@ -21,6 +23,48 @@ type RepoClient interface {
 	GetPackageManifestURL(name, version string) (string, error)
 }
 
+// PackageFileFetcher is implemented by RepoClients that can not serve package files over HTTP.
+// For such clients, files referenced relative to a package manifest must be fetched using FetchPackageFile.
+type PackageFileFetcher interface {
+	FetchPackageFile(name, version, path string) ([]byte, error)
+}
+
+// SignedPackageManifestFetcher is implemented by RepoClients that can provide package manifests together with their
+// detached signature.
+type SignedPackageManifestFetcher interface {
+	// FetchSignedPackageManifest returns the raw "package.yaml" file and the contents of the "package.yaml.sig" file.
+	FetchSignedPackageManifest(name, version string) (manifest []byte, signature []byte, err error)
+}
+
+// PackageManifestVerifier is implemented by RepoClients that verify the signatures of package manifests.
+// Such clients only return package manifests that have a valid signature.
+type PackageManifestVerifier interface {
+	// VerifyPackageManifest returns the fingerprint of the trusted public key that signed the package manifest.
+	VerifyPackageManifest(name, version string) (fingerprint string, err error)
+}
+
+// contextBinder is implemented by RepoClients that can bind their requests to a context.
+type contextBinder interface {
+	withContext(ctx context.Context) RepoClient
+}
+
+// WithContext returns a RepoClient that sends its requests with ctx, so that they are traced as children of the span
+// in ctx and cancelled when ctx is done. The returned client shares all cached data with client.
+// Clients that do not send requests are returned as they are.
+func WithContext(ctx context.Context, client RepoClient) RepoClient {
+	if binder, ok := client.(contextBinder); ok {
+		return binder.withContext(ctx)
+	}
+	return client
+}
+
+func contextOrBackground(ctx context.Context) context.Context {
+	if ctx == nil {
+		return context.Background()
+	}
+	return ctx
+}
+
 type RepoMetaclient interface {
 	LatestVersionGetter
 	FetchMetaIndex(target *types.MetaIndex) error
@@ -33,4 +77,6 @@ type RepoClientset interface {
 	ForRepo(repo packagesv1alpha1.PackageRepository) RepoClient
 	Default() RepoClient
 	Meta() RepoMetaclient
+	// Invalidate discards the client for the repository with the given name, including all cached data.
+	Invalidate(repoName string)
 }
@@ -0,0 +1,62 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/glasskube/glasskube/internal/contenttype"
+	"github.com/glasskube/glasskube/internal/repo/client/auth"
+	"github.com/glasskube/glasskube/internal/repo/types"
+	"github.com/glasskube/glasskube/internal/tracing"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+var _ = Describe("WithContext", func() {
+	var server *httptest.Server
+	var exporter *tracetest.InMemoryExporter
+
+	BeforeEach(func() {
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", contenttype.MediaTypeYAML)
+			_, _ = w.Write([]byte("packages:\n  - name: foo\n"))
+		}))
+		DeferCleanup(server.Close)
+		exporter = tracetest.NewInMemoryExporter()
+		otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+		DeferCleanup(func() { otel.SetTracerProvider(noop.NewTracerProvider()) })
+	})
+
+	It("should trace requests as children of the span in the context", func() {
+		client := New(server.URL, auth.Noop(), time.Minute)
+		ctx, parent := tracing.Start(context.Background(), "parent")
+		var index types.PackageRepoIndex
+		Expect(WithContext(ctx, client).FetchPackageRepoIndex(&index)).To(Succeed())
+		tracing.End(parent, nil)
+
+		Expect(index.Packages).To(HaveLen(1))
+		spans := exporter.GetSpans()
+		Expect(spans).To(HaveLen(2))
+		Expect(spans[0].Name).To(Equal("fetch repository file"))
+		Expect(spans[0].Parent.SpanID()).To(Equal(spans[1].SpanContext.SpanID()))
+		Expect(spans[0].Attributes).To(ContainElement(tracing.AttributeURL.String(server.URL + "/index.yaml")))
+
+		By("sharing the cache with the original client")
+		Expect(client.FetchPackageRepoIndex(&index)).To(Succeed())
+		Expect(exporter.GetSpans()).To(HaveLen(2))
+	})
+
+	It("should bind wrapped clients to the context", func() {
+		client, err := NewVerifying(NewOCI("oci://localhost/packages", auth.Noop(), time.Minute), nil)
+		Expect(err).NotTo(HaveOccurred())
+		bound := WithContext(context.Background(), client)
+		Expect(bound).To(BeAssignableToTypeOf(&verifyingFileClient{}))
+		Expect(bound.(*verifyingFileClient).RepoClient.(*ociClient).ctx).NotTo(BeNil())
+	})
+})
@@ -0,0 +1,13 @@
+package client
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestRepoClient(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "RepoClient Suite")
+}
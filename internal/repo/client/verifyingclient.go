@@ -0,0 +1,97 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/glasskube/glasskube/api/v1alpha1"
+	"github.com/glasskube/glasskube/internal/repo/types"
+	"github.com/glasskube/glasskube/internal/signature"
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// verifyingClient wraps a RepoClient for a repository with trusted public keys. All package manifests are fetched
+// together with their signature and are only returned if the signature was made by one of the trusted keys.
+type verifyingClient struct {
+	RepoClient
+	keys []signature.PublicKey
+}
+
+// verifyingFileClient is a verifyingClient for a RepoClient that also implements PackageFileFetcher.
+// It is needed, so that callers can still detect support for PackageFileFetcher with a type assertion.
+type verifyingFileClient struct {
+	*verifyingClient
+	PackageFileFetcher
+}
+
+func NewVerifying(client RepoClient, trustedPublicKeys []string) (RepoClient, error) {
+	keys, err := signature.ParsePublicKeys(trustedPublicKeys)
+	if err != nil {
+		return nil, err
+	}
+	return newVerifying(client, keys), nil
+}
+
+func newVerifying(client RepoClient, keys []signature.PublicKey) RepoClient {
+	verifying := &verifyingClient{RepoClient: client, keys: keys}
+	if fileFetcher, ok := client.(PackageFileFetcher); ok {
+		return &verifyingFileClient{verifyingClient: verifying, PackageFileFetcher: fileFetcher}
+	}
+	return verifying
+}
+
+var _ RepoClient = &verifyingClient{}
+var _ PackageManifestVerifier = &verifyingClient{}
+var _ PackageFileFetcher = &verifyingFileClient{}
+
+func (c *verifyingClient) withContext(ctx context.Context) RepoClient {
+	return newVerifying(WithContext(ctx, c.RepoClient), c.keys)
+}
+
+// FetchLatestPackageManifest implements RepoClient.
+func (c *verifyingClient) FetchLatestPackageManifest(name string, target *v1alpha1.PackageManifest) (
+	version string, err error,
+) {
+	var versions types.PackageIndex
+	if err = c.FetchPackageIndex(name, &versions); err != nil {
+		return
+	} else {
+		version = versions.LatestVersion
+	}
+	err = c.FetchPackageManifest(name, version, target)
+	return
+}
+
+// FetchPackageManifest implements RepoClient.
+// The manifest is decoded from the same data that was verified.
+func (c *verifyingClient) FetchPackageManifest(name, version string, target *v1alpha1.PackageManifest) error {
+	if data, _, err := c.fetchVerified(name, version); err != nil {
+		return err
+	} else {
+		return yaml.Unmarshal(data, target)
+	}
+}
+
+// VerifyPackageManifest implements PackageManifestVerifier.
+func (c *verifyingClient) VerifyPackageManifest(name, version string) (string, error) {
+	if _, key, err := c.fetchVerified(name, version); err != nil {
+		return "", err
+	} else {
+		return key.Fingerprint, nil
+	}
+}
+
+func (c *verifyingClient) fetchVerified(name, version string) ([]byte, *signature.PublicKey, error) {
+	fetcher, ok := c.RepoClient.(SignedPackageManifestFetcher)
+	if !ok {
+		return nil, nil, fmt.Errorf("repository does not support signed package manifests")
+	}
+	if data, sig, err := fetcher.FetchSignedPackageManifest(name, version); err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch signed package manifest of %v (%v): %w", name, version, err)
+	} else if key, err := signature.Verify(c.keys, data, sig); err != nil {
+		return nil, nil, fmt.Errorf("signature verification of package manifest of %v (%v) failed: %w",
+			name, version, err)
+	} else {
+		return data, key, nil
+	}
+}
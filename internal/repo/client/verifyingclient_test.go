@@ -0,0 +1,109 @@
+package client
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/glasskube/glasskube/api/v1alpha1"
+	"github.com/glasskube/glasskube/internal/contenttype"
+	"github.com/glasskube/glasskube/internal/repo/client/auth"
+	"github.com/glasskube/glasskube/internal/signature"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("verifyingClient", func() {
+	const manifest = "name: foo\nshortDescription: signed\n"
+	var files map[string]string
+	var server *httptest.Server
+	var privateKey ed25519.PrivateKey
+	var trustedKey string
+
+	BeforeEach(func() {
+		publicKey, key, err := ed25519.GenerateKey(rand.Reader)
+		Expect(err).NotTo(HaveOccurred())
+		privateKey = key
+		trustedKey, err = signature.EncodePublicKey(publicKey)
+		Expect(err).NotTo(HaveOccurred())
+		sig, err := signature.Sign(privateKey, []byte(manifest))
+		Expect(err).NotTo(HaveOccurred())
+		files = map[string]string{
+			"/foo/versions.yaml":           "versions:\n  - version: v1.0.0\nlatestVersion: v1.0.0\n",
+			"/foo/v1.0.0/package.yaml":     manifest,
+			"/foo/v1.0.0/package.yaml.sig": string(sig),
+			"/foo/v1.0.1/package.yaml":     manifest,
+			"/foo/v1.0.1/package.yaml.sig": string(sig[:len(sig)-4]) + "AAA=",
+			"/foo/v1.0.2/package.yaml":     manifest,
+			"/foo/v1.0.3/package.yaml":     "name: foo\nshortDescription: tampered\n",
+			"/foo/v1.0.3/package.yaml.sig": string(sig),
+		}
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if content, ok := files[r.URL.Path]; ok {
+				w.Header().Set("Content-Type", contenttype.MediaTypeYAML)
+				_, _ = w.Write([]byte(content))
+			} else {
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		DeferCleanup(server.Close)
+	})
+
+	newClient := func(keys ...string) RepoClient {
+		client, err := NewVerifying(New(server.URL, auth.Noop(), time.Minute), keys)
+		Expect(err).NotTo(HaveOccurred())
+		return client
+	}
+
+	It("should return manifests with a valid signature", func() {
+		client := newClient(trustedKey)
+		var target v1alpha1.PackageManifest
+		version, err := client.FetchLatestPackageManifest("foo", &target)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(version).To(Equal("v1.0.0"))
+		Expect(target.ShortDescription).To(Equal("signed"))
+		Expect(client).To(BeAssignableToTypeOf(&verifyingClient{}))
+		fingerprint, err := client.(PackageManifestVerifier).VerifyPackageManifest("foo", "v1.0.0")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fingerprint).To(HavePrefix("SHA256:"))
+	})
+
+	DescribeTable("should reject manifests without a valid signature",
+		func(version string) {
+			client := newClient(trustedKey)
+			var target v1alpha1.PackageManifest
+			Expect(client.FetchPackageManifest("foo", version, &target)).NotTo(Succeed())
+			Expect(target.Name).To(BeEmpty())
+		},
+		Entry("invalid signature", "v1.0.1"),
+		Entry("missing signature", "v1.0.2"),
+		Entry("modified manifest", "v1.0.3"),
+	)
+
+	It("should reject manifests signed by untrusted keys", func() {
+		otherKey, _, err := ed25519.GenerateKey(rand.Reader)
+		Expect(err).NotTo(HaveOccurred())
+		encoded, err := signature.EncodePublicKey(otherKey)
+		Expect(err).NotTo(HaveOccurred())
+		var target v1alpha1.PackageManifest
+		err = newClient(encoded).FetchPackageManifest("foo", "v1.0.0", &target)
+		Expect(err).To(MatchError(signature.ErrNoValidSignature))
+	})
+
+	It("should fail for invalid trusted keys", func() {
+		_, err := NewVerifying(New(server.URL, auth.Noop(), time.Minute), []string{"invalid"})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should keep support for PackageFileFetcher", func() {
+		client, err := NewVerifying(NewOCI("oci://localhost/packages", auth.Noop(), time.Minute), []string{trustedKey})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(client).To(BeAssignableToTypeOf(&verifyingFileClient{}))
+		_, ok := client.(PackageFileFetcher)
+		Expect(ok).To(BeTrue())
+		_, ok = client.(PackageManifestVerifier)
+		Expect(ok).To(BeTrue())
+	})
+})
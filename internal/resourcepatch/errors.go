@@ -0,0 +1,51 @@
+package resourcepatch
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/glasskube/glasskube/api/v1alpha1"
+)
+
+// TemplateError is returned if the valueTemplate of a target can not be parsed or executed or if its output is not
+// valid JSON.
+type TemplateError struct {
+	target string
+	cause  error
+}
+
+func (err *TemplateError) Error() string {
+	return fmt.Sprintf("invalid valueTemplate for target %v: %v", err.target, err.cause)
+}
+
+func (err *TemplateError) Unwrap() error {
+	return err.cause
+}
+
+func NewTemplateError(target v1alpha1.ValueDefinitionTarget, cause error) error {
+	return &TemplateError{target: describeTarget(target), cause: cause}
+}
+
+// describeTarget returns a short human readable description of target, e.g. "Deployment foo (/spec/replicas)".
+func describeTarget(target v1alpha1.ValueDefinitionTarget) string {
+	var sb strings.Builder
+	if target.Resource != nil {
+		sb.WriteString(target.Resource.Kind)
+		sb.WriteString(" ")
+		if target.Resource.Namespace != nil && *target.Resource.Namespace != "" {
+			sb.WriteString(*target.Resource.Namespace)
+			sb.WriteString("/")
+		}
+		sb.WriteString(target.Resource.Name)
+	} else if target.ChartName != nil {
+		sb.WriteString("chart ")
+		sb.WriteString(*target.ChartName)
+	}
+	if sb.Len() > 0 {
+		sb.WriteString(" ")
+	}
+	sb.WriteString("(")
+	sb.WriteString(target.Patch.Path)
+	sb.WriteString(")")
+	return sb.String()
+}
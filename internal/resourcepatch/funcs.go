@@ -0,0 +1,370 @@
+package resourcepatch
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"text/template"
+	"text/template/parse"
+	"time"
+
+	"sigs.k8s.io/yaml"
+)
+
+// maxTemplateOutput is the maximum number of bytes a single valueTemplate may produce.
+const maxTemplateOutput = 1 << 20
+
+var errTemplateOutputTooLarge = fmt.Errorf("template output exceeds %v bytes", maxTemplateOutput)
+
+// templateTimeout is the maximum time a single valueTemplate may take to execute.
+var templateTimeout = time.Second
+
+var errTemplateTimeout = errors.New("template execution timed out")
+
+var errRangeOverInteger = errors.New("range over an integer is not allowed")
+
+// maxRangeIterations is the maximum total number of iterations of all range actions in a single execution of a
+// valueTemplate.
+const maxRangeIterations = 100000
+
+var errTooManyRangeIterations = fmt.Errorf("template exceeds %v range iterations", maxRangeIterations)
+
+// rangeGuardFunc is the name of the function that parseTemplate appends to the pipeline of every range action.
+const rangeGuardFunc = "_rangeGuard"
+
+// TemplateFuncs returns the functions that are available in every valueTemplate, both for value definitions and for
+// transformations. All functions are pure: none of them has access to the environment, the file system or the
+// network, so that executing a template only depends on its input.
+//
+// Arguments are ordered so that the value being operated on comes last, which makes all functions usable in
+// pipelines (e.g. {{ . | trim | default "foo" | quote }}).
+//
+// Strings:
+//   - lower, upper, trim: change case or remove leading and trailing white space
+//   - trimPrefix PREFIX, trimSuffix SUFFIX: remove a prefix or suffix if present
+//   - replace OLD NEW: replace all occurrences of OLD with NEW
+//   - contains SUBSTR, hasPrefix PREFIX, hasSuffix SUFFIX: boolean string tests
+//   - quote: encode a value as a JSON string
+//   - split SEP: split a string into a list (an empty string results in an empty list)
+//   - join SEP: join the items of a list into a string
+//
+// Defaults:
+//   - default DEFAULT: DEFAULT if the value is empty, otherwise the value itself
+//   - empty: true for nil, false, 0, "" and empty lists or maps
+//   - coalesce: the first argument that is not empty
+//   - ternary A B: A if the value is true, otherwise B
+//
+// Encoding:
+//   - base64, base64Decode: standard base64 encoding
+//   - toJson, fromJson: JSON encoding
+//   - toYaml, fromYaml: YAML encoding
+//
+// Hashing:
+//   - sha256sum: hex encoded SHA-256 hash of a string
+//
+// Arithmetic (integers only, strings are parsed):
+//   - add, sub, mul, div, mod, min, max
+//   - toInt: convert a value to an integer
+//
+// range actions are only allowed over lists and maps, with at most maxRangeIterations iterations in total.
+func TemplateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"lower":      strings.ToLower,
+		"upper":      strings.ToUpper,
+		"trim":       strings.TrimSpace,
+		"trimPrefix": func(prefix, s string) string { return strings.TrimPrefix(s, prefix) },
+		"trimSuffix": func(suffix, s string) string { return strings.TrimSuffix(s, suffix) },
+		"replace":    func(old, new, s string) string { return strings.ReplaceAll(s, old, new) },
+		"contains":   func(substr, s string) bool { return strings.Contains(s, substr) },
+		"hasPrefix":  func(prefix, s string) bool { return strings.HasPrefix(s, prefix) },
+		"hasSuffix":  func(suffix, s string) bool { return strings.HasSuffix(s, suffix) },
+		"quote":      quote,
+		"split":      split,
+		"join":       join,
+
+		"default":  func(def, v any) any { return ternary(def, v, isEmpty(v)) },
+		"empty":    isEmpty,
+		"coalesce": coalesce,
+		"ternary":  ternary,
+
+		"base64":       func(s string) string { return base64.StdEncoding.EncodeToString([]byte(s)) },
+		"base64Decode": base64Decode,
+		"toJson":       toJson,
+		"fromJson":     fromJson,
+		"toYaml":       toYaml,
+		"fromYaml":     fromYaml,
+
+		"sha256sum": func(s string) string {
+			sum := sha256.Sum256([]byte(s))
+			return hex.EncodeToString(sum[:])
+		},
+
+		"add":   arithmetic(func(a, b int64) (int64, error) { return a + b, nil }),
+		"sub":   arithmetic(func(a, b int64) (int64, error) { return a - b, nil }),
+		"mul":   arithmetic(func(a, b int64) (int64, error) { return a * b, nil }),
+		"div":   arithmetic(divide),
+		"mod":   arithmetic(modulo),
+		"min":   arithmetic(func(a, b int64) (int64, error) { return min(a, b), nil }),
+		"max":   arithmetic(func(a, b int64) (int64, error) { return max(a, b), nil }),
+		"toInt": toInt,
+	}
+}
+
+func quote(v any) (string, error) {
+	if s, ok := v.(string); ok {
+		return toJson(s)
+	}
+	return toJson(fmt.Sprint(v))
+}
+
+func split(sep, s string) []string {
+	if s == "" {
+		return []string{}
+	}
+	return strings.Split(s, sep)
+}
+
+func join(sep string, list any) (string, error) {
+	switch l := list.(type) {
+	case []string:
+		return strings.Join(l, sep), nil
+	case []any:
+		items := make([]string, len(l))
+		for i, item := range l {
+			items[i] = fmt.Sprint(item)
+		}
+		return strings.Join(items, sep), nil
+	default:
+		return "", fmt.Errorf("join: cannot join %T", list)
+	}
+}
+
+func isEmpty(v any) bool {
+	if v == nil {
+		return true
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+		return rv.Len() == 0
+	case reflect.Pointer, reflect.Interface:
+		return rv.IsNil()
+	default:
+		return rv.IsZero()
+	}
+}
+
+func coalesce(values ...any) any {
+	for _, v := range values {
+		if !isEmpty(v) {
+			return v
+		}
+	}
+	return nil
+}
+
+func ternary(a, b any, cond bool) any {
+	if cond {
+		return a
+	}
+	return b
+}
+
+func base64Decode(s string) (string, error) {
+	if data, err := base64.StdEncoding.DecodeString(s); err != nil {
+		return "", err
+	} else {
+		return string(data), nil
+	}
+}
+
+func toJson(v any) (string, error) {
+	if data, err := json.Marshal(v); err != nil {
+		return "", err
+	} else {
+		return string(data), nil
+	}
+}
+
+func fromJson(s string) (any, error) {
+	var result any
+	if err := json.Unmarshal([]byte(s), &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func toYaml(v any) (string, error) {
+	if data, err := yaml.Marshal(v); err != nil {
+		return "", err
+	} else {
+		return strings.TrimSuffix(string(data), "\n"), nil
+	}
+}
+
+func fromYaml(s string) (any, error) {
+	var result any
+	if err := yaml.Unmarshal([]byte(s), &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func arithmetic(op func(a, b int64) (int64, error)) func(a, b any) (int64, error) {
+	return func(a, b any) (int64, error) {
+		if x, err := toInt(a); err != nil {
+			return 0, err
+		} else if y, err := toInt(b); err != nil {
+			return 0, err
+		} else {
+			return op(x, y)
+		}
+	}
+}
+
+func divide(a, b int64) (int64, error) {
+	if b == 0 {
+		return 0, errors.New("division by zero")
+	}
+	return a / b, nil
+}
+
+func modulo(a, b int64) (int64, error) {
+	if b == 0 {
+		return 0, errors.New("division by zero")
+	}
+	return a % b, nil
+}
+
+func toInt(v any) (int64, error) {
+	switch n := v.(type) {
+	case int:
+		return int64(n), nil
+	case int64:
+		return n, nil
+	case float64:
+		return int64(n), nil
+	case json.Number:
+		return n.Int64()
+	case string:
+		return strconv.ParseInt(strings.TrimSpace(n), 10, 64)
+	case bool:
+		if n {
+			return 1, nil
+		}
+		return 0, nil
+	default:
+		return 0, fmt.Errorf("cannot convert %T to integer", v)
+	}
+}
+
+// parseTemplate parses text as a valueTemplate. The value of every range action is passed through rangeGuardFunc,
+// which is only defined by executeTemplate.
+func parseTemplate(text string) (*template.Template, error) {
+	tmpl, err := template.New("").Funcs(TemplateFuncs()).Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range tmpl.Templates() {
+		if t.Tree != nil {
+			guardRanges(t.Tree, t.Tree.Root)
+		}
+	}
+	return tmpl, nil
+}
+
+func guardRanges(tree *parse.Tree, node parse.Node) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n != nil {
+			for _, child := range n.Nodes {
+				guardRanges(tree, child)
+			}
+		}
+	case *parse.RangeNode:
+		n.Pipe.Cmds = append(n.Pipe.Cmds, &parse.CommandNode{
+			NodeType: parse.NodeCommand,
+			Pos:      n.Pos,
+			Args:     []parse.Node{parse.NewIdentifier(rangeGuardFunc).SetTree(tree).SetPos(n.Pos)},
+		})
+		guardRanges(tree, n.List)
+		guardRanges(tree, n.ElseList)
+	case *parse.IfNode:
+		guardRanges(tree, n.List)
+		guardRanges(tree, n.ElseList)
+	case *parse.WithNode:
+		guardRanges(tree, n.List)
+		guardRanges(tree, n.ElseList)
+	}
+}
+
+// newRangeGuard returns the rangeGuardFunc for a single execution of a template. It only allows ranges over lists and
+// maps and fails as soon as the total number of iterations exceeds maxRangeIterations, so that a template can not
+// loop for an arbitrary amount of time without producing output.
+func newRangeGuard() func(any) (any, error) {
+	var iterations int
+	return func(value any) (any, error) {
+		switch v := reflect.ValueOf(value); v.Kind() {
+		case reflect.Invalid:
+			return value, nil
+		case reflect.Slice, reflect.Array, reflect.Map:
+			if iterations += v.Len(); iterations > maxRangeIterations {
+				return nil, errTooManyRangeIterations
+			}
+			return value, nil
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			return nil, errRangeOverInteger
+		default:
+			return nil, fmt.Errorf("range over %T is not allowed", value)
+		}
+	}
+}
+
+// executeTemplate executes tmpl and returns its output. Execution fails if it takes longer than templateTimeout.
+// text/template can not be cancelled, so a template that exceeds the deadline keeps running in the background until
+// it finishes, but the caller is not blocked by it. The range guard ensures that it finishes eventually.
+func executeTemplate(tmpl *template.Template, data any) (string, error) {
+	tmpl, err := tmpl.Clone()
+	if err != nil {
+		return "", err
+	}
+	tmpl.Funcs(template.FuncMap{rangeGuardFunc: newRangeGuard()})
+
+	type result struct {
+		output string
+		err    error
+	}
+	done := make(chan result, 1)
+	go func() {
+		var w limitedWriter
+		err := tmpl.Execute(&w, data)
+		done <- result{w.String(), err}
+	}()
+	timer := time.NewTimer(templateTimeout)
+	defer timer.Stop()
+	select {
+	case r := <-done:
+		return r.output, r.err
+	case <-timer.C:
+		return "", errTemplateTimeout
+	}
+}
+
+// limitedWriter fails as soon as more than maxTemplateOutput bytes are written to it.
+type limitedWriter struct {
+	strings.Builder
+}
+
+func (w *limitedWriter) Write(p []byte) (int, error) {
+	if w.Len()+len(p) > maxTemplateOutput {
+		return 0, errTemplateOutputTooLarge
+	}
+	return w.Builder.Write(p)
+}
@@ -0,0 +1,115 @@
+package resourcepatch
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/glasskube/glasskube/api/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TemplateFuncs", func() {
+	DescribeTable("should produce the expected value",
+		func(tmpl string, value any, expected any) {
+			result, err := getActualValue(v1alpha1.ValueDefinitionTarget{ValueTemplate: tmpl}, value)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(expected))
+		},
+		Entry("upper", `{{ . | upper | quote }}`, "foo", "FOO"),
+		Entry("trim and replace", `{{ . | trim | replace "-" "_" | quote }}`, " a-b ", "a_b"),
+		Entry("trimPrefix", `{{ . | trimPrefix "v" | quote }}`, "v1.2.3", "1.2.3"),
+		Entry("contains", `{{ . | contains "oo" }}`, "foo", true),
+		Entry("default for empty value", `{{ . | default "bar" | quote }}`, "", "bar"),
+		Entry("default for non-empty value", `{{ . | default "bar" | quote }}`, "foo", "foo"),
+		Entry("coalesce", `{{ coalesce "" . "baz" | quote }}`, "foo", "foo"),
+		Entry("ternary", `{{ eq . "yes" | ternary 1 2 }}`, "yes", float64(1)),
+		Entry("split", `{{ . | split "," | toJson }}`, "a,b", []any{"a", "b"}),
+		Entry("split empty string", `{{ . | split "," | toJson }}`, "", []any{}),
+		Entry("join list value", `{{ . | join "," | quote }}`, []any{"a", "b"}, "a,b"),
+		Entry("base64 round trip", `{{ . | base64 | base64Decode | quote }}`, "foo", "foo"),
+		Entry("fromJson", `{{ (fromJson .).foo | quote }}`, `{"foo":"bar"}`, "bar"),
+		Entry("toYaml", `{{ fromJson . | toYaml | quote }}`, `{"foo":"bar"}`, "foo: bar"),
+		Entry("fromYaml", `{{ fromYaml . | toJson }}`, "foo: 1", map[string]any{"foo": float64(1)}),
+		Entry("sha256sum", `{{ . | sha256sum | quote }}`, "foo",
+			"2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae"),
+		Entry("add", `{{ add . 1 }}`, "41", float64(42)),
+		Entry("mul and sub", `{{ mul . 3 | sub 20 }}`, "4", float64(8)),
+		Entry("div with piped divisor", `{{ div 12 . }}`, "4", float64(3)),
+		Entry("mod", `{{ mod . 3 }}`, "10", float64(1)),
+		Entry("max", `{{ max . 3 }}`, "10", float64(10)),
+	)
+
+	DescribeTable("should fail",
+		func(tmpl string, value any) {
+			_, err := getActualValue(v1alpha1.ValueDefinitionTarget{ValueTemplate: tmpl}, value)
+			Expect(err).To(HaveOccurred())
+			var templateErr *TemplateError
+			Expect(errors.As(err, &templateErr)).To(BeTrue())
+		},
+		Entry("for division by zero", `{{ div 1 . }}`, "0"),
+		Entry("for non-numeric arithmetic", `{{ add . 1 }}`, "foo"),
+		Entry("for invalid base64", `{{ base64Decode . | quote }}`, "!"),
+		Entry("for unknown function", `{{ env "HOME" }}`, ""),
+		Entry("for invalid JSON output", `{{ . }}`, "foo"),
+		Entry("for too large output", `{{ . }}`, strings.Repeat("1", maxTemplateOutput+1)),
+		Entry("for range over an integer literal", `{{ range 9223372036854775807 }}{{ end }}1`, ""),
+		Entry("for nested range over an integer literal", `{{ if . }}{{ range 10 }}{{ end }}{{ end }}1`, "x"),
+	)
+
+	DescribeTable("should reject range over an integer",
+		func(tmpl string) {
+			_, err := getActualValue(v1alpha1.ValueDefinitionTarget{ValueTemplate: tmpl}, "1000000000000")
+			Expect(err).To(MatchError(errRangeOverInteger))
+		},
+		Entry("parenthesized literal", `{{ range (1000000000000) }}{{ end }}1`),
+		Entry("function result", `{{ range toInt "1000000000000" }}{{ end }}1`),
+		Entry("piped value", `{{ range . | toInt }}{{ end }}1`),
+		Entry("variable", `{{ $n := toInt . }}{{ range $i, $_ := $n }}{{ end }}1`),
+		Entry("defined template", `{{ define "loop" }}{{ range toInt . }}{{ end }}{{ end }}{{ template "loop" . }}1`),
+	)
+
+	It("should limit the number of range iterations", func() {
+		_, err := getActualValue(v1alpha1.ValueDefinitionTarget{
+			ValueTemplate: `{{ range $a := split "," . }}{{ range $b := split "," $ }}{{ end }}{{ end }}1`,
+		}, strings.Repeat(",", 999))
+		Expect(err).To(MatchError(errTooManyRangeIterations))
+	})
+
+	It("should fail if the template does not terminate in time", func() {
+		DeferCleanup(func(timeout time.Duration) { templateTimeout = timeout }, templateTimeout)
+		templateTimeout = 10 * time.Millisecond
+		_, err := getActualValue(v1alpha1.ValueDefinitionTarget{
+			ValueTemplate: `{{ range split "," . }}{{ $_ := sha256sum $ }}{{ end }}1`,
+		}, strings.Repeat(strings.Repeat("a", 50)+",", 2000))
+		Expect(err).To(MatchError(errTemplateTimeout))
+		var templateErr *TemplateError
+		Expect(errors.As(err, &templateErr)).To(BeTrue())
+	})
+
+	It("should allow range over lists", func() {
+		Expect(getActualValue(v1alpha1.ValueDefinitionTarget{
+			ValueTemplate: `{{ range split "," . }}{{ . }}{{ end }}`,
+		}, "1,2")).To(Equal(float64(12)))
+	})
+})
+
+var _ = Describe("TemplateError", func() {
+	It("should contain value name and target", func() {
+		_, err := GeneratePatches(
+			v1alpha1.PackageManifest{
+				ValueDefinitions: map[string]v1alpha1.ValueDefinition{
+					"replicas": {Targets: []v1alpha1.ValueDefinitionTarget{{
+						ChartName:     &foo,
+						Patch:         v1alpha1.PartialJsonPatch{Op: "add", Path: "/replicaCount"},
+						ValueTemplate: "{{ div 1 . }}",
+					}}},
+				}},
+			map[string]string{"replicas": "0"})
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("value replicas"))
+		Expect(err.Error()).To(ContainSubstring("chart foo (/replicaCount)"))
+		Expect(err.Error()).To(ContainSubstring("division by zero"))
+	})
+})
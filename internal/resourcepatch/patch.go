@@ -1,10 +1,9 @@
 package resourcepatch
 
 import (
-	"bytes"
-	"encoding/base64"
 	"encoding/json"
-	"text/template"
+	"fmt"
+	"strconv"
 
 	jsonpatch "github.com/evanphx/json-patch/v5"
 	helmv2 "github.com/fluxcd/helm-controller/api/v2"
@@ -94,22 +93,75 @@ func getActualValue(target v1alpha1.ValueDefinitionTarget, value any) (any, erro
 		return value, nil
 	}
 
-	tmplBase := template.New("").Funcs(template.FuncMap{
-		"base64": func(s string) string { return base64.StdEncoding.EncodeToString([]byte(s)) },
-	})
-
-	if tmpl, err := tmplBase.Parse(target.ValueTemplate); err != nil {
-		return nil, err
+	if tmpl, err := parseTemplate(target.ValueTemplate); err != nil {
+		return nil, NewTemplateError(target, err)
+	} else if output, err := executeTemplate(tmpl, value); err != nil {
+		return nil, NewTemplateError(target, err)
 	} else {
-		var bw bytes.Buffer
-		if err := tmpl.Execute(&bw, value); err != nil {
-			return nil, err
-		}
 		var result any
-		if err := json.Unmarshal(bw.Bytes(), &result); err != nil {
-			return nil, err
+		if err := json.Unmarshal([]byte(output), &result); err != nil {
+			return nil, NewTemplateError(target, fmt.Errorf("output is not valid JSON: %w", err))
+		}
+		return result, nil
+	}
+}
+
+// typedValue converts list and map values to JSON arrays and objects, so that patches contain the actual structure
+// instead of its string representation. Items are converted according to the item type of def.
+// All other values are used as they are.
+func typedValue(def v1alpha1.ValueDefinition, value string) (any, error) {
+	switch def.Type {
+	case v1alpha1.ValueTypeList:
+		var items []string
+		if value != "" {
+			if err := json.Unmarshal([]byte(value), &items); err != nil {
+				return nil, err
+			}
+		}
+		result := make([]any, len(items))
+		for i, item := range items {
+			if typed, err := typedItem(def.Items, item); err != nil {
+				return nil, err
+			} else {
+				result[i] = typed
+			}
 		}
 		return result, nil
+	case v1alpha1.ValueTypeMap:
+		var items map[string]string
+		if value != "" {
+			if err := json.Unmarshal([]byte(value), &items); err != nil {
+				return nil, err
+			}
+		}
+		result := make(map[string]any, len(items))
+		for key, item := range items {
+			if typed, err := typedItem(def.Items, item); err != nil {
+				return nil, err
+			} else {
+				result[key] = typed
+			}
+		}
+		return result, nil
+	default:
+		return value, nil
+	}
+}
+
+func typedItem(itemDef *v1alpha1.ValueItemDefinition, item string) (any, error) {
+	if itemDef == nil {
+		return item, nil
+	}
+	switch itemDef.Type {
+	case v1alpha1.ValueTypeNumber:
+		if _, err := strconv.Atoi(item); err != nil {
+			return nil, err
+		}
+		return json.Number(item), nil
+	case v1alpha1.ValueTypeBoolean:
+		return strconv.ParseBool(item)
+	default:
+		return item, nil
 	}
 }
 
@@ -189,9 +241,13 @@ func GeneratePatches(manifest v1alpha1.PackageManifest, values map[string]string
 	for _, name := range maputils.KeysSorted(manifest.ValueDefinitions) {
 		def := manifest.ValueDefinitions[name]
 		if value, ok := values[name]; ok {
+			typed, err := typedValue(def, value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value %v: %w", name, err)
+			}
 			for _, target := range def.Targets {
-				if patch, err := GenerateTargetPatch(target, value); err != nil {
-					return nil, err
+				if patch, err := GenerateTargetPatch(target, typed); err != nil {
+					return nil, fmt.Errorf("value %v: %w", name, err)
 				} else {
 					result = append(result, *patch)
 				}
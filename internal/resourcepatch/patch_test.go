@@ -63,6 +63,57 @@ var _ = Describe("GeneratePatches", func() {
 			patch:     jsonPatch(`{"op":"add","path":"/spec/replicas","value":"test"}`),
 		}))
 	})
+	It("should generate JSON array for list value", func() {
+		patches, err := GeneratePatches(
+			v1alpha1.PackageManifest{
+				ValueDefinitions: map[string]v1alpha1.ValueDefinition{
+					"foo": {
+						Type:  v1alpha1.ValueTypeList,
+						Items: &v1alpha1.ValueItemDefinition{Type: v1alpha1.ValueTypeNumber},
+						Targets: []v1alpha1.ValueDefinitionTarget{{
+							ChartName: &foo,
+							Patch:     v1alpha1.PartialJsonPatch{Op: "add", Path: "/ports"},
+						}},
+					},
+				}},
+			map[string]string{"foo": `["80","443"]`})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(patches).To(ConsistOf(TargetPatch{
+			helmChart: &foo,
+			patch:     jsonPatch(`{"op":"add","path":"/ports","value":[80,443]}`),
+		}))
+	})
+	It("should generate JSON object for map value", func() {
+		patches, err := GeneratePatches(
+			v1alpha1.PackageManifest{
+				ValueDefinitions: map[string]v1alpha1.ValueDefinition{
+					"foo": {
+						Type: v1alpha1.ValueTypeMap,
+						Targets: []v1alpha1.ValueDefinitionTarget{{
+							ChartName: &foo,
+							Patch:     v1alpha1.PartialJsonPatch{Op: "add", Path: "/labels"},
+						}},
+					},
+				}},
+			map[string]string{"foo": `{"team":"a","tier":"b"}`})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(patches).To(ConsistOf(TargetPatch{
+			helmChart: &foo,
+			patch:     jsonPatch(`{"op":"add","path":"/labels","value":{"team":"a","tier":"b"}}`),
+		}))
+	})
+	It("should fail for invalid list value", func() {
+		_, err := GeneratePatches(
+			v1alpha1.PackageManifest{
+				ValueDefinitions: map[string]v1alpha1.ValueDefinition{
+					"foo": {Type: v1alpha1.ValueTypeList, Targets: []v1alpha1.ValueDefinitionTarget{{
+						ChartName: &foo,
+						Patch:     v1alpha1.PartialJsonPatch{Op: "add", Path: "/ports"},
+					}}},
+				}},
+			map[string]string{"foo": "80"})
+		Expect(err).To(HaveOccurred())
+	})
 })
 
 var _ = Describe("GenerateTargetPatch", func() {
@@ -0,0 +1,109 @@
+// Package revisions maintains the revision history of packages.
+package revisions
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/glasskube/glasskube/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MaxRevisions is the maximum number of revisions that are kept for every package.
+const MaxRevisions = 10
+
+var (
+	ErrNotFound   = errors.New("revision not found")
+	ErrNoPrevious = errors.New("no previous successful revision")
+)
+
+// Record updates revisions for the given spec.
+// If the version or values of spec differ from the latest revision, a new revision is appended and the oldest
+// revisions are dropped, so that at most MaxRevisions remain. Otherwise, the outcome of the latest revision is
+// updated, unless it already succeeded. It returns true if revisions was changed.
+func Record(
+	revisions *[]v1alpha1.PackageRevision,
+	spec v1alpha1.PackageSpec,
+	outcome v1alpha1.RevisionOutcome,
+	now metav1.Time,
+) bool {
+	if latest := Latest(*revisions); latest != nil && Matches(*latest, spec) {
+		if latest.Outcome == outcome || latest.Outcome == v1alpha1.RevisionSucceeded {
+			return false
+		}
+		latest.Outcome = outcome
+		return true
+	} else {
+		*revisions = append(*revisions, v1alpha1.PackageRevision{
+			Revision:  Next(*revisions),
+			Version:   spec.PackageInfo.Version,
+			Values:    copyValues(spec.Values),
+			Timestamp: now,
+			Outcome:   outcome,
+		})
+		if len(*revisions) > MaxRevisions {
+			*revisions = (*revisions)[len(*revisions)-MaxRevisions:]
+		}
+		return true
+	}
+}
+
+// Matches returns true if revision has the same version and values as spec.
+func Matches(revision v1alpha1.PackageRevision, spec v1alpha1.PackageSpec) bool {
+	return revision.Version == spec.PackageInfo.Version && equality.Semantic.DeepEqual(revision.Values, spec.Values)
+}
+
+// Latest returns the most recent revision or nil, if there are no revisions.
+func Latest(revisions []v1alpha1.PackageRevision) *v1alpha1.PackageRevision {
+	if len(revisions) == 0 {
+		return nil
+	}
+	return &revisions[len(revisions)-1]
+}
+
+// Next returns the number of the revision that is appended next.
+func Next(revisions []v1alpha1.PackageRevision) int64 {
+	if latest := Latest(revisions); latest != nil {
+		return latest.Revision + 1
+	}
+	return 1
+}
+
+// Find returns the revision with the given number.
+func Find(revisions []v1alpha1.PackageRevision, number int64) (*v1alpha1.PackageRevision, error) {
+	for i := range revisions {
+		if revisions[i].Revision == number {
+			return &revisions[i], nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %v", ErrNotFound, number)
+}
+
+// Previous returns the most recent successful revision that does not match spec. This is the revision that is
+// restored by a rollback if no revision is specified explicitly.
+func Previous(revisions []v1alpha1.PackageRevision, spec v1alpha1.PackageSpec) (*v1alpha1.PackageRevision, error) {
+	for i := len(revisions) - 1; i >= 0; i-- {
+		if revisions[i].Outcome == v1alpha1.RevisionSucceeded && !Matches(revisions[i], spec) {
+			return &revisions[i], nil
+		}
+	}
+	return nil, ErrNoPrevious
+}
+
+// Apply sets the version and values of spec to those of revision.
+func Apply(revision v1alpha1.PackageRevision, spec *v1alpha1.PackageSpec) {
+	spec.PackageInfo.Version = revision.Version
+	spec.Values = copyValues(revision.Values)
+}
+
+func copyValues(values map[string]v1alpha1.ValueConfiguration) map[string]v1alpha1.ValueConfiguration {
+	if values == nil {
+		return nil
+	}
+	result := make(map[string]v1alpha1.ValueConfiguration, len(values))
+	for name, value := range values {
+		result[name] = *value.DeepCopy()
+	}
+	return result
+}
@@ -0,0 +1,123 @@
+package revisions
+
+import (
+	"github.com/glasskube/glasskube/api/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+func spec(version string, values map[string]string) v1alpha1.PackageSpec {
+	result := v1alpha1.PackageSpec{PackageInfo: v1alpha1.PackageInfoTemplate{Name: "test", Version: version}}
+	if values != nil {
+		result.Values = make(map[string]v1alpha1.ValueConfiguration, len(values))
+		for name, value := range values {
+			result.Values[name] = v1alpha1.ValueConfiguration{
+				InlineValueConfiguration: v1alpha1.InlineValueConfiguration{Value: ptr.To(value)},
+			}
+		}
+	}
+	return result
+}
+
+var _ = Describe("Record", func() {
+	now := metav1.Now()
+
+	It("should append the first revision", func() {
+		var revisions []v1alpha1.PackageRevision
+		Expect(Record(&revisions, spec("v1", nil), v1alpha1.RevisionPending, now)).To(BeTrue())
+		Expect(revisions).To(HaveLen(1))
+		Expect(revisions[0].Revision).To(Equal(int64(1)))
+		Expect(revisions[0].Version).To(Equal("v1"))
+		Expect(revisions[0].Outcome).To(Equal(v1alpha1.RevisionPending))
+	})
+
+	It("should update the outcome of a matching revision", func() {
+		var revisions []v1alpha1.PackageRevision
+		Record(&revisions, spec("v1", nil), v1alpha1.RevisionPending, now)
+		Expect(Record(&revisions, spec("v1", nil), v1alpha1.RevisionPending, now)).To(BeFalse())
+		Expect(Record(&revisions, spec("v1", nil), v1alpha1.RevisionSucceeded, now)).To(BeTrue())
+		Expect(revisions).To(HaveLen(1))
+		Expect(revisions[0].Outcome).To(Equal(v1alpha1.RevisionSucceeded))
+	})
+
+	It("should not downgrade a succeeded revision", func() {
+		var revisions []v1alpha1.PackageRevision
+		Record(&revisions, spec("v1", nil), v1alpha1.RevisionSucceeded, now)
+		Expect(Record(&revisions, spec("v1", nil), v1alpha1.RevisionFailed, now)).To(BeFalse())
+		Expect(revisions[0].Outcome).To(Equal(v1alpha1.RevisionSucceeded))
+	})
+
+	It("should append a revision if values change", func() {
+		var revisions []v1alpha1.PackageRevision
+		Record(&revisions, spec("v1", map[string]string{"a": "1"}), v1alpha1.RevisionSucceeded, now)
+		Expect(Record(&revisions, spec("v1", map[string]string{"a": "2"}), v1alpha1.RevisionPending, now)).
+			To(BeTrue())
+		Expect(revisions).To(HaveLen(2))
+		Expect(revisions[1].Revision).To(Equal(int64(2)))
+		Expect(*revisions[1].Values["a"].Value).To(Equal("2"))
+	})
+
+	It("should keep at most MaxRevisions", func() {
+		var revisions []v1alpha1.PackageRevision
+		for i := 0; i < MaxRevisions+3; i++ {
+			Record(&revisions, spec("v1", map[string]string{"i": string(rune('a' + i))}),
+				v1alpha1.RevisionSucceeded, now)
+		}
+		Expect(revisions).To(HaveLen(MaxRevisions))
+		Expect(revisions[0].Revision).To(Equal(int64(4)))
+		Expect(Latest(revisions).Revision).To(Equal(int64(MaxRevisions + 3)))
+	})
+})
+
+var _ = Describe("Previous", func() {
+	revisions := []v1alpha1.PackageRevision{
+		{Revision: 1, Version: "v1", Outcome: v1alpha1.RevisionSucceeded},
+		{Revision: 2, Version: "v2", Outcome: v1alpha1.RevisionFailed},
+		{Revision: 3, Version: "v3", Outcome: v1alpha1.RevisionPending},
+	}
+
+	It("should return the most recent successful revision", func() {
+		rev, err := Previous(revisions, spec("v3", nil))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(rev.Revision).To(Equal(int64(1)))
+	})
+
+	It("should fail if there is no other successful revision", func() {
+		_, err := Previous(revisions, spec("v1", nil))
+		Expect(err).To(MatchError(ErrNoPrevious))
+	})
+})
+
+var _ = Describe("Next", func() {
+	It("should continue after the latest revision", func() {
+		Expect(Next(nil)).To(Equal(int64(1)))
+		Expect(Next([]v1alpha1.PackageRevision{{Revision: 3}, {Revision: 4}})).To(Equal(int64(5)))
+	})
+})
+
+var _ = Describe("Find", func() {
+	revisions := []v1alpha1.PackageRevision{{Revision: 4, Version: "v1"}}
+
+	It("should find a revision by number", func() {
+		Expect(Find(revisions, 4)).To(HaveField("Version", "v1"))
+	})
+
+	It("should fail for unknown revisions", func() {
+		_, err := Find(revisions, 1)
+		Expect(err).To(MatchError(ErrNotFound))
+	})
+})
+
+var _ = Describe("Apply", func() {
+	It("should restore version and values", func() {
+		rev := v1alpha1.PackageRevision{Version: "v1", Values: spec("", map[string]string{"a": "1"}).Values}
+		target := spec("v2", map[string]string{"b": "2"})
+		Apply(rev, &target)
+		Expect(target.PackageInfo.Version).To(Equal("v1"))
+		Expect(Matches(rev, target)).To(BeTrue())
+		*target.Values["a"].Value = "changed"
+		Expect(*rev.Values["a"].Value).To(Equal("1"))
+	})
+})
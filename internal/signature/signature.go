@@ -0,0 +1,119 @@
+// Package signature implements detached signatures for package manifests.
+//
+// A signature is stored in a file next to the signed file, with the additional extension ".sig". It contains the
+// base64 encoded signature of the SHA-256 digest of the signed file. Ed25519 keys sign the file contents directly.
+// Public keys are PEM encoded in PKIX format.
+package signature
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const FileExtension = ".sig"
+
+var ErrNoValidSignature = errors.New("signature could not be verified with any trusted public key")
+
+type PublicKey struct {
+	crypto.PublicKey
+	// Fingerprint is the SHA-256 digest of the DER encoded key, formatted like the key fingerprints of OpenSSH.
+	Fingerprint string
+}
+
+// ParsePublicKeys parses a list of PEM encoded public keys.
+func ParsePublicKeys(keys []string) ([]PublicKey, error) {
+	result := make([]PublicKey, len(keys))
+	for i, key := range keys {
+		if parsed, err := ParsePublicKey(key); err != nil {
+			return nil, fmt.Errorf("invalid public key at index %v: %w", i, err)
+		} else {
+			result[i] = *parsed
+		}
+	}
+	return result, nil
+}
+
+// ParsePublicKey parses a PEM encoded Ed25519, ECDSA or RSA public key.
+func ParsePublicKey(key string) (*PublicKey, error) {
+	block, _ := pem.Decode([]byte(key))
+	if block == nil {
+		return nil, errors.New("no PEM data found")
+	} else if block.Type != "PUBLIC KEY" {
+		return nil, fmt.Errorf("unexpected PEM block type %v", block.Type)
+	}
+	parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	switch parsed.(type) {
+	case ed25519.PublicKey, *ecdsa.PublicKey, *rsa.PublicKey:
+		digest := sha256.Sum256(block.Bytes)
+		fingerprint := "SHA256:" + base64.RawStdEncoding.EncodeToString(digest[:])
+		return &PublicKey{PublicKey: parsed, Fingerprint: fingerprint}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %T", parsed)
+	}
+}
+
+// Verify checks the base64 encoded signature of data against all keys and returns the first key that verifies it.
+func Verify(keys []PublicKey, data, signature []byte) (*PublicKey, error) {
+	decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(signature)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature: %w", err)
+	}
+	digest := sha256.Sum256(data)
+	for i := range keys {
+		if verifyWithKey(keys[i].PublicKey, data, digest[:], decoded) {
+			return &keys[i], nil
+		}
+	}
+	return nil, ErrNoValidSignature
+}
+
+func verifyWithKey(key crypto.PublicKey, data, digest, signature []byte) bool {
+	switch key := key.(type) {
+	case ed25519.PublicKey:
+		return ed25519.Verify(key, data, signature)
+	case *ecdsa.PublicKey:
+		return ecdsa.VerifyASN1(key, digest, signature)
+	case *rsa.PublicKey:
+		return rsa.VerifyPKCS1v15(key, crypto.SHA256, digest, signature) == nil
+	default:
+		return false
+	}
+}
+
+// Sign creates a base64 encoded signature of data, that can be verified with Verify.
+func Sign(signer crypto.Signer, data []byte) ([]byte, error) {
+	var signature []byte
+	var err error
+	if _, ok := signer.Public().(ed25519.PublicKey); ok {
+		signature, err = signer.Sign(rand.Reader, data, crypto.Hash(0))
+	} else {
+		digest := sha256.Sum256(data)
+		signature, err = signer.Sign(rand.Reader, digest[:], crypto.SHA256)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return []byte(base64.StdEncoding.EncodeToString(signature)), nil
+}
+
+// EncodePublicKey returns the PEM encoding of key, as expected by ParsePublicKey.
+func EncodePublicKey(key crypto.PublicKey) (string, error) {
+	if der, err := x509.MarshalPKIXPublicKey(key); err != nil {
+		return "", err
+	} else {
+		return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})), nil
+	}
+}
@@ -0,0 +1,84 @@
+package signature
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Signature", func() {
+	data := []byte("name: foo\n")
+
+	newEd25519 := func() crypto.Signer {
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		Expect(err).NotTo(HaveOccurred())
+		return key
+	}
+	newECDSA := func() crypto.Signer {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		Expect(err).NotTo(HaveOccurred())
+		return key
+	}
+	newRSA := func() crypto.Signer {
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		Expect(err).NotTo(HaveOccurred())
+		return key
+	}
+
+	parse := func(signers ...crypto.Signer) []PublicKey {
+		encoded := make([]string, len(signers))
+		for i, signer := range signers {
+			var err error
+			encoded[i], err = EncodePublicKey(signer.Public())
+			Expect(err).NotTo(HaveOccurred())
+		}
+		keys, err := ParsePublicKeys(encoded)
+		Expect(err).NotTo(HaveOccurred())
+		return keys
+	}
+
+	DescribeTable("should verify signatures",
+		func(newSigner func() crypto.Signer) {
+			signer := newSigner()
+			other := newSigner()
+			keys := parse(other, signer)
+			signature, err := Sign(signer, data)
+			Expect(err).NotTo(HaveOccurred())
+
+			key, err := Verify(keys, data, append(signature, '\n'))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(key.Fingerprint).To(Equal(keys[1].Fingerprint))
+			Expect(key.Fingerprint).To(HavePrefix("SHA256:"))
+
+			_, err = Verify(keys, []byte("name: bar\n"), signature)
+			Expect(err).To(MatchError(ErrNoValidSignature))
+			_, err = Verify(keys[:1], data, signature)
+			Expect(err).To(MatchError(ErrNoValidSignature))
+		},
+		Entry("ed25519", newEd25519),
+		Entry("ecdsa", newECDSA),
+		Entry("rsa", newRSA),
+	)
+
+	It("should reject malformed signatures", func() {
+		_, err := Verify(parse(newEd25519()), data, []byte("not base64!"))
+		Expect(err).To(HaveOccurred())
+		Expect(err).NotTo(MatchError(ErrNoValidSignature))
+	})
+
+	It("should reject invalid public keys", func() {
+		_, err := ParsePublicKeys([]string{"not a key"})
+		Expect(err).To(HaveOccurred())
+		encoded, err := EncodePublicKey(newEd25519().Public())
+		Expect(err).NotTo(HaveOccurred())
+		_, err = ParsePublicKey(strings.ReplaceAll(encoded, "PUBLIC KEY", "PRIVATE KEY"))
+		Expect(err).To(HaveOccurred())
+	})
+})
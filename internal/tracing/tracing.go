@@ -0,0 +1,106 @@
+// Package tracing provides OpenTelemetry tracing for the package operator.
+//
+// Spans are always created using the global TracerProvider. Unless Init is called with an OTLP endpoint configured,
+// this is the no-op provider of the OpenTelemetry API, so instrumented code does not need to check whether tracing
+// is enabled.
+package tracing
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	"github.com/glasskube/glasskube/internal/controller/ctrlpkg"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	tracerName         = "github.com/glasskube/glasskube"
+	defaultServiceName = "glasskube-package-operator"
+)
+
+const (
+	AttributePackageKind      = attribute.Key("glasskube.package.kind")
+	AttributePackageName      = attribute.Key("glasskube.package.name")
+	AttributePackageNamespace = attribute.Key("glasskube.package.namespace")
+	AttributePackage          = attribute.Key("glasskube.package.package")
+	AttributePackageVersion   = attribute.Key("glasskube.package.version")
+	AttributeRepository       = attribute.Key("glasskube.repository")
+	AttributeURL              = attribute.Key("glasskube.url")
+	AttributeAdapter          = attribute.Key("glasskube.adapter")
+	AttributeObjectCount      = attribute.Key("glasskube.object_count")
+)
+
+// Enabled returns true if an OTLP endpoint is configured using the standard OpenTelemetry environment variables
+// OTEL_EXPORTER_OTLP_ENDPOINT or OTEL_EXPORTER_OTLP_TRACES_ENDPOINT and the SDK is not disabled with
+// OTEL_SDK_DISABLED.
+func Enabled() bool {
+	if strings.EqualFold(os.Getenv("OTEL_SDK_DISABLED"), "true") {
+		return false
+	}
+	return os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") != "" || os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") != ""
+}
+
+// Init sets up the global TracerProvider with an OTLP/HTTP exporter if Enabled returns true.
+// The exporter, sampler and resource are configured using the standard OpenTelemetry environment variables
+// (e.g. OTEL_EXPORTER_OTLP_HEADERS, OTEL_TRACES_SAMPLER or OTEL_SERVICE_NAME).
+// The returned function flushes all pending spans and must be called before the process exits.
+func Init(ctx context.Context) (shutdown func(context.Context) error, err error) {
+	if !Enabled() {
+		return func(context.Context) error { return nil }, nil
+	}
+	exporter, err := otlptracehttp.New(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res, err := resource.New(ctx,
+		resource.WithAttributes(semconv.ServiceName(defaultServiceName)),
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	provider := sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter), sdktrace.WithResource(res))
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{}, propagation.Baggage{}))
+	return provider.Shutdown, nil
+}
+
+// Start creates a span with the given name as a child of the span contained in ctx, if any.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End ends span. If err is not nil, it is recorded and the status of span is set to Error.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// PackageAttributes returns the attributes identifying pkg.
+func PackageAttributes(pkg ctrlpkg.Package) []attribute.KeyValue {
+	kind := "ClusterPackage"
+	if pkg.IsNamespaceScoped() {
+		kind = "Package"
+	}
+	return []attribute.KeyValue{
+		AttributePackageKind.String(kind),
+		AttributePackageName.String(pkg.GetName()),
+		AttributePackageNamespace.String(pkg.GetNamespace()),
+		AttributePackage.String(pkg.GetSpec().PackageInfo.Name),
+		AttributePackageVersion.String(pkg.GetSpec().PackageInfo.Version),
+	}
+}
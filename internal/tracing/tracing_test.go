@@ -0,0 +1,95 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+
+	"github.com/glasskube/glasskube/api/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace/noop"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Spans", func() {
+	var exporter *tracetest.InMemoryExporter
+
+	BeforeEach(func() {
+		exporter = tracetest.NewInMemoryExporter()
+		otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+		DeferCleanup(func() { otel.SetTracerProvider(noop.NewTracerProvider()) })
+	})
+
+	It("should create child spans", func() {
+		ctx, parent := Start(context.Background(), "parent")
+		_, child := Start(ctx, "child", AttributeAdapter.String("helm"))
+		End(child, nil)
+		End(parent, nil)
+
+		spans := exporter.GetSpans()
+		Expect(spans).To(HaveLen(2))
+		Expect(spans[0].Name).To(Equal("child"))
+		Expect(spans[0].Parent.SpanID()).To(Equal(spans[1].SpanContext.SpanID()))
+		Expect(spans[0].Attributes).To(ContainElement(AttributeAdapter.String("helm")))
+		Expect(spans[0].Status.Code).To(Equal(codes.Unset))
+	})
+
+	It("should record errors", func() {
+		_, span := Start(context.Background(), "failing")
+		End(span, errors.New("boom"))
+
+		spans := exporter.GetSpans()
+		Expect(spans).To(HaveLen(1))
+		Expect(spans[0].Status.Code).To(Equal(codes.Error))
+		Expect(spans[0].Status.Description).To(Equal("boom"))
+		Expect(spans[0].Events).To(HaveLen(1))
+		Expect(spans[0].Events[0].Name).To(Equal("exception"))
+	})
+
+	It("should add package attributes", func() {
+		pkg := &v1alpha1.Package{
+			ObjectMeta: metav1.ObjectMeta{Name: "my-pkg", Namespace: "ns"},
+			Spec: v1alpha1.PackageSpec{
+				PackageInfo: v1alpha1.PackageInfoTemplate{Name: "cert-manager", Version: "v1.0.0"},
+			},
+		}
+		_, span := Start(context.Background(), "reconcile package", PackageAttributes(pkg)...)
+		End(span, nil)
+
+		spans := exporter.GetSpans()
+		Expect(spans).To(HaveLen(1))
+		Expect(spans[0].Attributes).To(ConsistOf(
+			AttributePackageKind.String("Package"),
+			AttributePackageName.String("my-pkg"),
+			AttributePackageNamespace.String("ns"),
+			AttributePackage.String("cert-manager"),
+			AttributePackageVersion.String("v1.0.0"),
+		))
+	})
+})
+
+var _ = Describe("Init", func() {
+	It("should not be enabled without an endpoint", func() {
+		GinkgoT().Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+		GinkgoT().Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "")
+		Expect(Enabled()).To(BeFalse())
+		shutdown, err := Init(context.Background())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(shutdown(context.Background())).To(Succeed())
+	})
+
+	It("should be enabled with a traces endpoint", func() {
+		GinkgoT().Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "http://localhost:4318/v1/traces")
+		Expect(Enabled()).To(BeTrue())
+	})
+
+	It("should not be enabled if the SDK is disabled", func() {
+		GinkgoT().Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "http://localhost:4318")
+		GinkgoT().Setenv("OTEL_SDK_DISABLED", "true")
+		Expect(Enabled()).To(BeFalse())
+	})
+})
@@ -0,0 +1,13 @@
+package updatepolicy
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestUpdatePolicy(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "UpdatePolicy Suite")
+}
@@ -0,0 +1,106 @@
+// Package updatepolicy implements version constraints for automatic updates.
+//
+// A policy is one of
+//
+//   - "patch": only versions with the same major and minor version as the installed version are allowed,
+//   - "minor": only versions with the same major version as the installed version are allowed,
+//   - "major" or "": all versions are allowed,
+//   - a semver constraint, e.g. "~1.4" or ">=1.2, <2".
+package updatepolicy
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/glasskube/glasskube/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	Patch = "patch"
+	Minor = "minor"
+	Major = "major"
+)
+
+var ErrInvalid = errors.New("invalid update policy")
+
+// Policy restricts the versions a package may be updated to.
+type Policy struct {
+	source     string
+	constraint *semver.Constraints
+}
+
+// Parse parses a policy. An empty string results in a policy that allows all versions.
+func Parse(s string) (*Policy, error) {
+	s = strings.TrimSpace(s)
+	switch strings.ToLower(s) {
+	case "", Major, Minor, Patch:
+		return &Policy{source: strings.ToLower(s)}, nil
+	}
+	if constraint, err := semver.NewConstraint(s); err != nil {
+		return nil, fmt.Errorf("%w %q: %w", ErrInvalid, s, err)
+	} else {
+		return &Policy{source: s, constraint: constraint}, nil
+	}
+}
+
+// ForPackage returns the policy of pkg as set by the auto-update policy annotation.
+func ForPackage(pkg metav1.Object) (*Policy, error) {
+	return Parse(pkg.GetAnnotations()[v1alpha1.AnnotationAutoUpdatePolicy])
+}
+
+func (p *Policy) String() string {
+	return p.source
+}
+
+// IsRestricted returns false if p allows all versions.
+func (p *Policy) IsRestricted() bool {
+	return p.source != "" && p.source != Major
+}
+
+// Allows returns true if updating from installed to candidate is permitted by p.
+// If either version is not a valid semver version, only unrestricted policies allow the update.
+func (p *Policy) Allows(installed, candidate string) bool {
+	if !p.IsRestricted() {
+		return true
+	}
+	candidateVersion, err := semver.NewVersion(candidate)
+	if err != nil {
+		return false
+	}
+	if p.constraint != nil {
+		return p.constraint.Check(candidateVersion)
+	}
+	installedVersion, err := semver.NewVersion(installed)
+	if err != nil {
+		return false
+	}
+	switch p.source {
+	case Patch:
+		return candidateVersion.Major() == installedVersion.Major() &&
+			candidateVersion.Minor() == installedVersion.Minor()
+	case Minor:
+		return candidateVersion.Major() == installedVersion.Major()
+	default:
+		return false
+	}
+}
+
+// Select returns the highest version of candidates that p allows updating to from installed.
+// If there is no such version, ok is false.
+func (p *Policy) Select(installed string, candidates []string) (version string, ok bool) {
+	var selected *semver.Version
+	for _, candidate := range candidates {
+		if !p.Allows(installed, candidate) {
+			continue
+		}
+		if v, err := semver.NewVersion(candidate); err != nil {
+			continue
+		} else if selected == nil || v.GreaterThan(selected) {
+			selected, version, ok = v, candidate, true
+		}
+	}
+	return
+}
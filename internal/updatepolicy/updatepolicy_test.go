@@ -0,0 +1,70 @@
+package updatepolicy
+
+import (
+	"github.com/glasskube/glasskube/api/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Parse", func() {
+	DescribeTable("valid policies",
+		func(s string, restricted bool) {
+			policy, err := Parse(s)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(policy.IsRestricted()).To(Equal(restricted))
+		},
+		Entry("empty", "", false),
+		Entry("major", "major", false),
+		Entry("minor", "Minor", true),
+		Entry("patch", "patch", true),
+		Entry("tilde constraint", "~1.4", true),
+		Entry("range constraint", ">=1.2, <2", true),
+	)
+
+	It("should reject invalid policies", func() {
+		_, err := Parse("latest")
+		Expect(err).To(MatchError(ErrInvalid))
+	})
+})
+
+var _ = Describe("Policy", func() {
+	candidates := []string{"v1.4.0", "v1.4.3", "v1.5.0", "v1.5.2+1", "v2.0.0", "not a version"}
+
+	DescribeTable("Select",
+		func(policy, installed, expected string) {
+			p, err := Parse(policy)
+			Expect(err).NotTo(HaveOccurred())
+			version, ok := p.Select(installed, candidates)
+			Expect(ok).To(Equal(expected != ""))
+			Expect(version).To(Equal(expected))
+		},
+		Entry("unrestricted", "", "v1.4.0", "v2.0.0"),
+		Entry("patch", "patch", "v1.4.0", "v1.4.3"),
+		Entry("minor", "minor", "v1.4.0", "v1.5.2+1"),
+		Entry("tilde constraint", "~1.4", "v1.4.0", "v1.4.3"),
+		Entry("no allowed version", "patch", "v1.3.0", ""),
+		Entry("invalid installed version", "minor", "not a version", ""),
+	)
+
+	It("should allow all versions if unrestricted", func() {
+		p, err := Parse("major")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(p.Allows("v1.0.0", "not a version")).To(BeTrue())
+	})
+})
+
+var _ = Describe("ForPackage", func() {
+	It("should use the annotation", func() {
+		pkg := &metav1.ObjectMeta{Annotations: map[string]string{v1alpha1.AnnotationAutoUpdatePolicy: "minor"}}
+		p, err := ForPackage(pkg)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(p.String()).To(Equal("minor"))
+	})
+
+	It("should be unrestricted without annotation", func() {
+		p, err := ForPackage(&metav1.ObjectMeta{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(p.IsRestricted()).To(BeFalse())
+	})
+})
@@ -1,9 +1,12 @@
 package components
 
 import (
+	"encoding/json"
 	"fmt"
 	"strconv"
 
+	"github.com/glasskube/glasskube/internal/manifestvalues"
+	"github.com/glasskube/glasskube/internal/maputils"
 	"github.com/glasskube/glasskube/internal/web/util"
 
 	"github.com/glasskube/glasskube/internal/controller/ctrlpkg"
@@ -31,6 +34,7 @@ type pkgConfigInputInput struct {
 	ValueDefinition    v1alpha1.ValueDefinition
 	StringValue        string
 	BoolValue          bool
+	HasStoredValue     bool // only set for secret values, which are never sent to the browser
 	FormLabel          string
 	FormId             string
 	ContainerId        string
@@ -42,7 +46,60 @@ type pkgConfigInputInput struct {
 	PackageHref        string
 }
 
+type pkgConfigInputItemRow struct {
+	FormValueName  string
+	ItemDefinition v1alpha1.ValueDefinition
+	IsMap          bool
+	Key            string
+	Value          string
+}
+
+// ConditionInJSON returns the allowed values of the condition of the value definition as JSON array.
+func (input *pkgConfigInputInput) ConditionInJSON() string {
+	if input.ValueDefinition.When == nil || input.ValueDefinition.When.In == nil {
+		return ""
+	}
+	data, _ := json.Marshal(input.ValueDefinition.When.In)
+	return string(data)
+}
+
+// ItemRows returns one row for each item of a list or map value.
+func (input *pkgConfigInputInput) ItemRows() []pkgConfigInputItemRow {
+	var rows []pkgConfigInputItemRow
+	switch input.ValueDefinition.Type {
+	case v1alpha1.ValueTypeList:
+		items, _ := manifestvalues.ParseList(input.StringValue)
+		for _, item := range items {
+			rows = append(rows, input.itemRow("", item))
+		}
+	case v1alpha1.ValueTypeMap:
+		items, _ := manifestvalues.ParseMap(input.StringValue)
+		for _, key := range maputils.KeysSorted(items) {
+			rows = append(rows, input.itemRow(key, items[key]))
+		}
+	}
+	return rows
+}
+
+// EmptyItemRow returns the row that is used as template for adding new items.
+func (input *pkgConfigInputInput) EmptyItemRow() pkgConfigInputItemRow {
+	return input.itemRow("", "")
+}
+
+func (input *pkgConfigInputInput) itemRow(key, value string) pkgConfigInputItemRow {
+	return pkgConfigInputItemRow{
+		FormValueName:  input.FormValueName,
+		ItemDefinition: manifestvalues.ItemDefinition(input.ValueDefinition),
+		IsMap:          input.ValueDefinition.Type == v1alpha1.ValueTypeMap,
+		Key:            key,
+		Value:          value,
+	}
+}
+
 func getStringValue(pkg ctrlpkg.Package, valueName string, valueDefinition *v1alpha1.ValueDefinition) string {
+	if valueDefinition.Type == v1alpha1.ValueTypeSecret && hasStoredValue(pkg, valueName) {
+		return ""
+	}
 	if !pkg.IsNil() {
 		if valueConfiguration, ok := pkg.GetSpec().Values[valueName]; ok {
 			if valueConfiguration.Value != nil {
@@ -63,6 +120,16 @@ func getBoolValue(pkg ctrlpkg.Package, valueName string, valueDefinition *v1alph
 	return false
 }
 
+// hasStoredValue returns true if pkg has an inline value for valueName or a reference to its values Secret.
+func hasStoredValue(pkg ctrlpkg.Package, valueName string) bool {
+	if !pkg.IsNil() {
+		if val, ok := pkg.GetSpec().Values[valueName]; ok {
+			return val.Value != nil || manifestvalues.IsValuesSecretRef(pkg, val.ValueFrom)
+		}
+	}
+	return false
+}
+
 func getLabel(valueName string, valueDefinition *v1alpha1.ValueDefinition) string {
 	inputLabel := valueName
 	if valueDefinition.Metadata.Label != "" {
@@ -74,13 +141,16 @@ func getLabel(valueName string, valueDefinition *v1alpha1.ValueDefinition) strin
 func getExistingReferenceAndKind(pkg ctrlpkg.Package, valueName string) (*v1alpha1.ValueReference, string) {
 	if !pkg.IsNil() {
 		if val, ok := pkg.GetSpec().Values[valueName]; ok {
-			if val.Value == nil && val.ValueFrom != nil {
+			// references to the generated values Secret are displayed like inline values
+			if val.Value == nil && val.ValueFrom != nil && !manifestvalues.IsValuesSecretRef(pkg, val.ValueFrom) {
 				if val.ValueFrom.ConfigMapRef != nil {
 					return val.ValueFrom, "ConfigMap"
 				} else if val.ValueFrom.SecretRef != nil {
 					return val.ValueFrom, "Secret"
 				} else if val.ValueFrom.PackageRef != nil {
 					return val.ValueFrom, "Package"
+				} else if val.ValueFrom.ResourceRef != nil {
+					return val.ValueFrom, "Resource"
 				}
 			}
 		}
@@ -124,6 +194,7 @@ func ForPkgConfigInput(
 		ValueDefinition:    valueDefinition,
 		StringValue:        getStringValue(pkg, valueName, &valueDefinition),
 		BoolValue:          getBoolValue(pkg, valueName, &valueDefinition),
+		HasStoredValue:     valueDefinition.Type == v1alpha1.ValueTypeSecret && hasStoredValue(pkg, valueName),
 		FormLabel:          getLabel(valueName, &valueDefinition),
 		FormId:             fmt.Sprintf("input-%v", valueName),
 		ContainerId:        fmt.Sprintf("input-container-%v", valueName),
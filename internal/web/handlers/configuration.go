@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"os"
@@ -9,6 +10,8 @@ import (
 
 	"github.com/glasskube/glasskube/api/v1alpha1"
 	"github.com/glasskube/glasskube/internal/clicontext"
+	"github.com/glasskube/glasskube/internal/controller/ctrlpkg"
+	"github.com/glasskube/glasskube/internal/manifestvalues"
 	"github.com/glasskube/glasskube/internal/web/components"
 	"github.com/glasskube/glasskube/internal/web/components/toast"
 	opts "github.com/glasskube/glasskube/internal/web/options"
@@ -24,10 +27,16 @@ const (
 	keyKey           = "key"
 	packageKey       = "package"
 	valueKey         = "value"
+	apiVersionKey    = "apiVersion"
+	kindKey          = "kind"
+	pathKey          = "path"
 	refKindKey       = "refKind"
+	itemKey          = "item"
+	itemKeyKey       = "itemKey"
 	refKindConfigMap = "ConfigMap"
 	refKindSecret    = "Secret"
 	refKindPackage   = "Package"
+	refKindResource  = "Resource"
 )
 
 func formKey(valueName string, key string) string {
@@ -60,9 +69,23 @@ func extractValues(r *http.Request, manifest *v1alpha1.PackageManifest) (map[str
 					PackageRef: extractPackageValueSource(r, valueName),
 				},
 			}
+		} else if refKindVal == refKindResource {
+			values[valueName] = v1alpha1.ValueConfiguration{
+				ValueFrom: &v1alpha1.ValueReference{
+					ResourceRef: extractResourceValueSource(r, valueName),
+				},
+			}
 		} else if refKindVal == "" {
 			formVal := r.Form.Get(fmt.Sprintf("%v.%v", formValuePrefix, valueName))
-			if valueDef.Type == v1alpha1.ValueTypeBoolean {
+			if valueDef.Type == v1alpha1.ValueTypeList || valueDef.Type == v1alpha1.ValueTypeMap {
+				structuredVal, err := extractStructuredValue(r, valueName, valueDef)
+				if err != nil {
+					return nil, err
+				}
+				values[valueName] = v1alpha1.ValueConfiguration{
+					InlineValueConfiguration: v1alpha1.InlineValueConfiguration{Value: &structuredVal},
+				}
+			} else if valueDef.Type == v1alpha1.ValueTypeBoolean {
 				boolStr := strconv.FormatBool(false)
 				if strings.ToLower(formVal) == "on" {
 					boolStr = strconv.FormatBool(true)
@@ -75,9 +98,76 @@ func extractValues(r *http.Request, manifest *v1alpha1.PackageManifest) (map[str
 			return nil, fmt.Errorf("cannot extract value %v because of unknown reference kind %v", valueName, refKindVal)
 		}
 	}
+	for valueName := range values {
+		// inputs of values that do not apply are hidden in the form, so they must not be configured
+		if !manifestvalues.IsApplicable(*manifest, valueName, values) {
+			delete(values, valueName)
+		}
+	}
 	return values, nil
 }
 
+// extractStructuredValue extracts the items of a list or map value, which are submitted as repeated form fields.
+// Rows with an empty item (for lists) or an empty key (for maps) are ignored.
+func extractStructuredValue(r *http.Request, valueName string, valueDef v1alpha1.ValueDefinition) (string, error) {
+	items := r.Form[formKey(valueName, itemKey)]
+	if valueDef.Type == v1alpha1.ValueTypeList {
+		var list []string
+		for _, item := range items {
+			if item != "" {
+				list = append(list, item)
+			}
+		}
+		return manifestvalues.FormatList(list), nil
+	}
+	keys := r.Form[formKey(valueName, itemKeyKey)]
+	if len(keys) != len(items) {
+		return "", fmt.Errorf("cannot extract value %v because the number of keys and items differ", valueName)
+	}
+	m := make(map[string]string, len(keys))
+	for i, key := range keys {
+		if key != "" {
+			m[key] = items[i]
+		}
+	}
+	return manifestvalues.FormatMap(m), nil
+}
+
+// keepStoredSecretValues replaces empty secret values with the value that pkg already has, because stored secret
+// values are never sent to the browser, so an empty input means that the value should not be changed.
+func keepStoredSecretValues(
+	pkg ctrlpkg.Package,
+	manifest *v1alpha1.PackageManifest,
+	values map[string]v1alpha1.ValueConfiguration,
+) {
+	for valueName, value := range values {
+		if valueDef, ok := manifest.ValueDefinitions[valueName]; !ok || valueDef.Type != v1alpha1.ValueTypeSecret ||
+			value.Value == nil || *value.Value != "" {
+			continue
+		}
+		if oldValue, ok := pkg.GetSpec().Values[valueName]; ok &&
+			(oldValue.Value != nil || manifestvalues.IsValuesSecretRef(pkg, oldValue.ValueFrom)) {
+			values[valueName] = oldValue
+		}
+	}
+}
+
+// storeSecretValues moves the inline secret values of pkg into its values Secret.
+func storeSecretValues(
+	ctx context.Context,
+	pkg ctrlpkg.Package,
+	manifest *v1alpha1.PackageManifest,
+	dryRun []string,
+) error {
+	if secret := manifestvalues.ExtractSecretValues(pkg, manifest); secret != nil {
+		k8sClient := clicontext.KubernetesClientFromContext(ctx)
+		if err := manifestvalues.ApplySecretValues(ctx, k8sClient, secret, pkg, dryRun); err != nil {
+			return fmt.Errorf("failed to store secret values: %w", err)
+		}
+	}
+	return nil
+}
+
 func extractObjectKeyValueSource(r *http.Request, valueName string) *v1alpha1.ObjectKeyValueSource {
 	namespaceFormKey := formKey(valueName, namespaceKey)
 	nameFormKey := formKey(valueName, nameKey)
@@ -98,6 +188,16 @@ func extractPackageValueSource(r *http.Request, valueName string) *v1alpha1.Pack
 	}
 }
 
+func extractResourceValueSource(r *http.Request, valueName string) *v1alpha1.ResourceValueSource {
+	return &v1alpha1.ResourceValueSource{
+		APIVersion: r.Form.Get(formKey(valueName, apiVersionKey)),
+		Kind:       r.Form.Get(formKey(valueName, kindKey)),
+		Namespace:  r.Form.Get(formKey(valueName, namespaceKey)),
+		Name:       r.Form.Get(formKey(valueName, nameKey)),
+		Path:       r.Form.Get(formKey(valueName, pathKey)),
+	}
+}
+
 // GetPackageConfigurationInput is like GetClusterPackageConfigurationInput but for packages
 func GetPackageConfigurationInput(w http.ResponseWriter, r *http.Request) {
 	pCtx := getPackageContext(r)
@@ -119,7 +219,7 @@ func GetPackageConfigurationInput(w http.ResponseWriter, r *http.Request) {
 // The endpoint requires the pkgName query parameter to be set, as well as the valueName query parameter (which holds
 // the name of the desired value according to the package value definitions).
 // An optional query parameter refKind can be passed to request the snippet in a certain variant, where the accepted
-// refKind values are: ConfigMap, Secret, Package. If no refKind is given, the "regular" input is returned.
+// refKind values are: ConfigMap, Secret, Package, Resource. If no refKind is given, the "regular" input is returned.
 // In any case, the input container consists of a button where the user can change the type of reference or remove the
 // reference, and the actual input field(s).
 func GetClusterPackageConfigurationInput(w http.ResponseWriter, r *http.Request) {
@@ -157,7 +257,7 @@ func handleConfigurationInput(w http.ResponseWriter, r *http.Request, d *package
 	refKind := r.URL.Query().Get("refKind")
 	if valueDefinition, ok := d.manifest.ValueDefinitions[valueName]; ok {
 		options := components.PkgConfigInputDatalistOptions{}
-		if refKind == refKindConfigMap || refKind == refKindSecret {
+		if refKind == refKindConfigMap || refKind == refKindSecret || refKind == refKindResource {
 			if opts, err := opts.GetNamespaceOptions(ctx); err != nil {
 				fmt.Fprintf(os.Stderr, "failed to get namespace options: %v\n", err)
 			} else {
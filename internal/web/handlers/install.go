@@ -92,7 +92,9 @@ func PostPackageDetail(w http.ResponseWriter, r *http.Request) {
 			WithNamespace(namespace).
 			WithName(name).
 			BuildPackage()
-		err := install.NewInstaller(pkgClient).Install(ctx, pkg, opts)
+		err := install.NewInstaller(pkgClient).
+			WithSecretValues(clicontext.KubernetesClientFromContext(ctx), mf).
+			Install(ctx, pkg, opts)
 		if err != nil {
 			responder.SendToast(w, toast.WithErr(fmt.Errorf("failed to install %v: %w", p.manifestName, err)))
 		} else if dryRun {
@@ -108,18 +110,23 @@ func PostPackageDetail(w http.ResponseWriter, r *http.Request) {
 	} else {
 		pkg.Spec.PackageInfo.Version = p.version
 		pkg.Spec.PackageInfo.RepositoryName = p.repositoryName
+		keepStoredSecretValues(pkg, mf, values)
 		pkg.Spec.Values = values
 		pkg.SetAutoUpdatesEnabled(autoUpdate)
 		opts := v1.UpdateOptions{}
 		if dryRun {
 			opts.DryRun = []string{v1.DryRunAll}
 		}
+		if err := storeSecretValues(ctx, pkg, mf, opts.DryRun); err != nil {
+			responder.SendToast(w, toast.WithErr(fmt.Errorf("failed to configure %v: %w", p.manifestName, err)))
+			return
+		}
 		if err := pkgClient.Packages(pkg.GetNamespace()).Update(ctx, pkg, opts); err != nil {
 			responder.SendToast(w, toast.WithErr(fmt.Errorf("failed to configure %v: %w", p.manifestName, err)))
 			return
 		}
 		valueResolver := cliutils.ValueResolver(ctx)
-		_, resolveErr := valueResolver.Resolve(ctx, values)
+		_, resolveErr := valueResolver.ResolvePackage(ctx, pkg)
 		if dryRun {
 			if yamlOutput, err := clientutils.Format(clientutils.OutputFormatYAML, false, pkg); err != nil {
 				responder.SendToast(w, toast.WithErr(fmt.Errorf("failed to render yaml: %w", err)))
@@ -184,7 +191,9 @@ func PostClusterPackageDetail(w http.ResponseWriter, r *http.Request) {
 		if dryRun {
 			opts.DryRun = []string{v1.DryRunAll}
 		}
-		err := install.NewInstaller(pkgClient).Install(ctx, pkg, opts)
+		err := install.NewInstaller(pkgClient).
+			WithSecretValues(clicontext.KubernetesClientFromContext(ctx), mf).
+			Install(ctx, pkg, opts)
 		if err != nil {
 			responder.SendToast(w, toast.WithErr(fmt.Errorf("failed to install %v: %w", p.manifestName, err)))
 			return
@@ -198,18 +207,23 @@ func PostClusterPackageDetail(w http.ResponseWriter, r *http.Request) {
 	} else {
 		pkg.Spec.PackageInfo.Version = p.version
 		pkg.Spec.PackageInfo.RepositoryName = p.repositoryName
+		keepStoredSecretValues(pkg, mf, values)
 		pkg.Spec.Values = values
 		pkg.SetAutoUpdatesEnabled(autoUpdate)
 		opts := v1.UpdateOptions{}
 		if dryRun {
 			opts.DryRun = []string{v1.DryRunAll}
 		}
+		if err := storeSecretValues(ctx, pkg, mf, opts.DryRun); err != nil {
+			responder.SendToast(w, toast.WithErr(fmt.Errorf("failed to configure %v: %w", p.manifestName, err)))
+			return
+		}
 		if err := pkgClient.ClusterPackages().Update(ctx, pkg, opts); err != nil {
 			responder.SendToast(w, toast.WithErr(fmt.Errorf("failed to configure %v: %w", p.manifestName, err)))
 			return
 		}
 		valueResolver := cliutils.ValueResolver(ctx)
-		_, resolveErr := valueResolver.Resolve(ctx, values)
+		_, resolveErr := valueResolver.ResolvePackage(ctx, pkg)
 		if dryRun {
 			if yamlOutput, err := clientutils.Format(clientutils.OutputFormatYAML, false, pkg); err != nil {
 				responder.SendToast(w, toast.WithErr(fmt.Errorf("failed to render yaml: %w", err)))
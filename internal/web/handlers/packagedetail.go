@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"os"
 	"slices"
+	"time"
 
 	"github.com/glasskube/glasskube/api/v1alpha1"
 	clientadapter "github.com/glasskube/glasskube/internal/adapter/goclient"
@@ -26,21 +27,27 @@ import (
 	webtypes "github.com/glasskube/glasskube/internal/web/types"
 	webutil "github.com/glasskube/glasskube/internal/web/util"
 	"github.com/glasskube/glasskube/pkg/client"
+	"github.com/glasskube/glasskube/pkg/condition"
 	"github.com/glasskube/glasskube/pkg/describe"
 	"go.uber.org/multierr"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 type packageDetailCommonData struct {
-	Package              ctrlpkg.Package
-	Status               *client.PackageStatus
-	Manifest             *v1alpha1.PackageManifest
-	PackageManifestUrl   string
-	LatestVersion        string
-	UpdateAvailable      bool
-	ShowDiscussionLink   bool
-	PackageHref          string
-	AutoUpdaterInstalled bool
+	Package            ctrlpkg.Package
+	Status             *client.PackageStatus
+	Manifest           *v1alpha1.PackageManifest
+	PackageManifestUrl string
+	LatestVersion      string
+	UpdateAvailable    bool
+	ShowDiscussionLink bool
+	PackageHref        string
+	// AutoUpdateFailure is the reason why the last automatic update was not applied, if any.
+	AutoUpdateFailure string
+	// AutoUpdateWindow describes the next maintenance window for automatic updates, if any.
+	AutoUpdateWindow string
 }
 
 type packageDetailTemplateData struct {
@@ -189,7 +196,7 @@ func renderPackageDetailPage(w http.ResponseWriter, r *http.Request, p *packageC
 		nsOptions, _ := opts.GetNamespaceOptions(ctx)
 		if !p.pkg.IsNil() {
 			pkgsOptions, _ := opts.GetPackagesOptions(r.Context())
-			for key, v := range p.pkg.GetSpec().Values {
+			for key, v := range manifestvalues.WithDefaultNamespace(p.pkg.GetSpec().Values, p.pkg.GetNamespace()) {
 				k8sClient := clicontext.KubernetesClientFromContext(ctx)
 				valueResolver := manifestvalues.NewResolver(
 					clientadapter.NewPackageClientAdapter(pkgClient),
@@ -282,21 +289,30 @@ func resolvePkgDetailCommon(w http.ResponseWriter, ctx context.Context, p *packa
 		packageManifestUrl = url
 	}
 
-	autoUpdaterInstalled, err := clientutils.IsAutoUpdaterInstalled(ctx)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "failed to check whether auto updater is installed: %v\n", err)
+	var autoUpdateWindow, autoUpdateFailure string
+	if !p.pkg.IsNil() && p.pkg.AutoUpdatesEnabled() {
+		if cond := meta.FindStatusCondition(p.pkg.GetStatus().Conditions, string(condition.AutoUpdated)); cond != nil &&
+			cond.Status == metav1.ConditionFalse {
+			autoUpdateFailure = cond.Message
+		}
+		if defaultSchedule, err := clientutils.GetDefaultMaintenanceWindow(ctx); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to get default maintenance window: %v\n", err)
+		} else {
+			autoUpdateWindow = clientutils.AutoUpdateWindowDescription(p.pkg, defaultSchedule, time.Now())
+		}
 	}
 
 	return &packageDetailCommonData{
-		Package:              p.pkg,
-		Status:               client.GetStatusOrPending(p.pkg),
-		Manifest:             p.manifest,
-		PackageManifestUrl:   packageManifestUrl,
-		LatestVersion:        latestVersion,
-		UpdateAvailable:      isUpdateAvailableForPkg(ctx, p.pkg),
-		ShowDiscussionLink:   usedRepo.IsGlasskubeRepo(),
-		PackageHref:          webutil.GetPackageHrefWithFallback(p.pkg, p.manifest),
-		AutoUpdaterInstalled: autoUpdaterInstalled,
+		Package:            p.pkg,
+		Status:             client.GetStatusOrPending(p.pkg),
+		Manifest:           p.manifest,
+		PackageManifestUrl: packageManifestUrl,
+		LatestVersion:      latestVersion,
+		UpdateAvailable:    isUpdateAvailableForPkg(ctx, p.pkg),
+		ShowDiscussionLink: usedRepo.IsGlasskubeRepo(),
+		PackageHref:        webutil.GetPackageHrefWithFallback(p.pkg, p.manifest),
+		AutoUpdateFailure:  autoUpdateFailure,
+		AutoUpdateWindow:   autoUpdateWindow,
 	}, repos, idx, repoErr
 }
 
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/glasskube/glasskube/internal/clientutils"
+	"github.com/glasskube/glasskube/internal/revisions"
+	"github.com/glasskube/glasskube/internal/web/components/toast"
+	"github.com/glasskube/glasskube/internal/web/responder"
+	webutil "github.com/glasskube/glasskube/internal/web/util"
+	"github.com/glasskube/glasskube/pkg/update"
+)
+
+func PostRollback(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	gitopsModeEnabled := webutil.IsGitopsModeEnabled(r)
+	updater := update.NewUpdater(ctx)
+
+	pkg, err := getPackageFromRequest(r)
+	if err != nil {
+		responder.SendToast(w, toast.WithErr(err))
+		return
+	}
+	number, err := strconv.ParseInt(r.FormValue("revision"), 10, 64)
+	if err != nil {
+		responder.SendToast(w, toast.WithErr(fmt.Errorf("invalid revision: %w", err)))
+		return
+	}
+	revision, err := revisions.Find(pkg.GetStatus().Revisions, number)
+	if err != nil {
+		responder.SendToast(w, toast.WithErr(err))
+		return
+	}
+
+	if tx, err := updater.PrepareRollback(ctx, pkg, *revision); err != nil {
+		responder.SendToast(w, toast.WithErr(fmt.Errorf("failed to prepare rollback: %w", err)))
+	} else if len(tx.ConflictItems) > 0 {
+		responder.SendToast(w, toast.WithErr(fmt.Errorf("cannot roll back %v due to dependency conflicts: %v",
+			pkg.GetName(), tx.ConflictItems[0].Conflicts)))
+	} else if updatedPackages, err := updater.Apply(ctx, tx,
+		update.ApplyUpdateOptions{DryRun: gitopsModeEnabled}); err != nil {
+		responder.SendToast(w, toast.WithErr(fmt.Errorf("failed to roll back %v: %w", pkg.GetName(), err)))
+	} else if gitopsModeEnabled {
+		if yamlOutput, err := clientutils.Format(clientutils.OutputFormatYAML, false, updatedPackages...); err != nil {
+			responder.SendToast(w, toast.WithErr(fmt.Errorf("failed to render yaml: %w", err)))
+		} else {
+			responder.SendYamlModal(w, yamlOutput, nil)
+		}
+	} else {
+		responder.SendToast(w,
+			toast.WithMessage(fmt.Sprintf("%v is being rolled back to revision %v", pkg.GetName(), revision.Revision)))
+	}
+}
@@ -43,6 +43,8 @@ func GetDatalistOptions(ctx context.Context, ref *v1alpha1.ValueReference, names
 				datalistOptions.Keys = keyOptions
 			}
 		}
+	} else if ref.ResourceRef != nil {
+		datalistOptions.Namespaces = namespaceOptions
 	} else if ref.PackageRef != nil {
 		datalistOptions.Names = pkgsOptions
 		if keyOptions, err := GetPackageValuesOptions(ctx, ref.PackageRef.Name); err != nil {
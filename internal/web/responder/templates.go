@@ -18,6 +18,7 @@ import (
 	"github.com/fsnotify/fsnotify"
 	"github.com/glasskube/glasskube/api/v1alpha1"
 	"github.com/glasskube/glasskube/internal/controller/ctrlpkg"
+	"github.com/glasskube/glasskube/internal/revisions"
 	"github.com/glasskube/glasskube/internal/semver"
 	"github.com/glasskube/glasskube/internal/web/components/pkg_detail_btns"
 	"github.com/glasskube/glasskube/internal/web/components/pkg_overview_btn"
@@ -132,6 +133,12 @@ func (t *templates) ParseTemplates() {
 			}
 			return false
 		},
+		"IsCurrentRevision": func(pkg ctrlpkg.Package, revision v1alpha1.PackageRevision) bool {
+			if pkg != nil && !pkg.IsNil() {
+				return revisions.Matches(revision, *pkg.GetSpec())
+			}
+			return false
+		},
 	}
 
 	var paths []string
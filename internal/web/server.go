@@ -210,6 +210,10 @@ func (s *server) Start(ctx context.Context) error {
 	router.Handle("POST /clusterpackages/{manifestName}/resume", s.requireReady(handlers.PostResume))
 	router.Handle("POST /packages/{manifestName}/{namespace}/{name}/resume", s.requireReady(handlers.PostResume))
 
+	// rollback
+	router.Handle("POST /clusterpackages/{manifestName}/rollback", s.requireReady(handlers.PostRollback))
+	router.Handle("POST /packages/{manifestName}/{namespace}/{name}/rollback", s.requireReady(handlers.PostRollback))
+
 	// setup
 	router.HandleFunc("GET /support", s.supportPage)
 	router.HandleFunc("GET /kubeconfig", s.getKubeconfigPage)
@@ -10,7 +10,6 @@ import (
 	"github.com/glasskube/glasskube/internal/controller/owners"
 	"github.com/glasskube/glasskube/internal/dependency"
 	"github.com/glasskube/glasskube/internal/dependency/graph"
-	"github.com/glasskube/glasskube/internal/manifestvalues"
 	repoclient "github.com/glasskube/glasskube/internal/repo/client"
 	"go.uber.org/multierr"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -108,29 +107,7 @@ func (p *PackageValidatingWebhook) ValidateDelete(ctx context.Context, obj runti
 }
 
 func (p *PackageValidatingWebhook) validateCreateOrUpdate(ctx context.Context, pkg ctrlpkg.Package) error {
-	// We must expect that this package is not installed in this version, so the PackageInfo does not exist.
-	var manifest v1alpha1.PackageManifest
-	err := p.RepoClient.ForPackage(pkg).FetchPackageManifest(
-		pkg.GetSpec().PackageInfo.Name,
-		pkg.GetSpec().PackageInfo.Version,
-		&manifest,
-	)
-	if err != nil {
-		return err
-	}
-
-	// Scope validation
-	if manifest.Scope.IsCluster() && pkg.IsNamespaceScoped() {
-		return errors.New("invalid scope, expected ClusterPackage but found Package")
-	} else if manifest.Scope.IsNamespaced() && !pkg.IsNamespaceScoped() {
-		return errors.New("invalid scope, expected Package but found ClusterPackage")
-	}
-
-	if err := manifestvalues.ValidatePackage(manifest, pkg); err != nil {
-		return err
-	}
-
-	if result, err := p.Validate(ctx, pkg.GetName(), pkg.GetNamespace(), &manifest, pkg.GetSpec().PackageInfo.Version); err != nil {
+	if result, err := p.ValidatePackage(ctx, p.RepoClient, pkg); err != nil {
 		return err
 	} else if len(result.Conflicts) > 0 {
 		// Conflicts are not allowed.
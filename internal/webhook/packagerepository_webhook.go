@@ -18,6 +18,7 @@ package webhook
 
 import (
 	"context"
+	"reflect"
 
 	"github.com/glasskube/glasskube/api/v1alpha1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -59,7 +60,7 @@ func (p *PackageRepositoryValidatingWebhook) ValidateUpdate(ctx context.Context,
 	if oldRepo, ok := oldObj.(*v1alpha1.PackageRepository); ok {
 		if newRepo, ok := newObj.(*v1alpha1.PackageRepository); ok {
 			log.Info("validate update", "name", newRepo.Name)
-			if oldRepo.Spec.Url != newRepo.Spec.Url {
+			if oldRepo.Spec.Url != newRepo.Spec.Url || !reflect.DeepEqual(oldRepo.Spec.Git, newRepo.Spec.Git) {
 				return nil, p.validateUpdateOrDelete(ctx, oldRepo)
 			} else {
 				return nil, nil
@@ -6,8 +6,13 @@ type (
 )
 
 const (
-	Ready  Type = "Ready"
-	Failed Type = "Failed"
+	Ready   Type = "Ready"
+	Failed  Type = "Failed"
+	Drifted Type = "Drifted"
+	// UpdateAvailable is True if a newer version of a package is available.
+	UpdateAvailable Type = "UpdateAvailable"
+	// AutoUpdated reports the result of the last automatic update of a package.
+	AutoUpdated Type = "AutoUpdated"
 )
 
 const (
@@ -19,5 +24,18 @@ const (
 	ValueConfigurationInvalid Reason = "ValueConfigurationInvalid"
 	InstallationSucceeded     Reason = "InstallationSucceeded"
 	InstallationFailed        Reason = "InstallationFailed"
+	HookFailed                Reason = "HookFailed"
 	Pending                   Reason = "Pending"
+	NoDrift                   Reason = "NoDrift"
+	DriftDetected             Reason = "DriftDetected"
+	DriftCorrected            Reason = "DriftCorrected"
+	NewVersionAvailable       Reason = "NewVersionAvailable"
+	HeldBackByPolicy          Reason = "HeldBackByPolicy"
+	UpdateCheckFailed         Reason = "UpdateCheckFailed"
+	UpdateConflict            Reason = "UpdateConflict"
+	OutsideMaintenanceWindow  Reason = "OutsideMaintenanceWindow"
+	AutoUpdateSucceeded       Reason = "AutoUpdateSucceeded"
+	AutoUpdateFailed          Reason = "AutoUpdateFailed"
+	DeliverySucceeded         Reason = "DeliverySucceeded"
+	DeliveryFailed            Reason = "DeliveryFailed"
 )
@@ -11,6 +11,7 @@ import (
 
 	"github.com/glasskube/glasskube/api/v1alpha1"
 	"github.com/glasskube/glasskube/internal/cliutils"
+	repoclient "github.com/glasskube/glasskube/internal/repo/client"
 	"github.com/glasskube/glasskube/pkg/manifest"
 )
 
@@ -63,20 +64,9 @@ func DescribeLatestVersion(ctx context.Context, repositoryName string, packageNa
 	*v1alpha1.PackageManifest, string, error) {
 
 	repoClient := cliutils.RepositoryClientset(ctx)
-	var repoErr error
+	repositoryName, repoErr := resolveRepositoryName(repoClient, repositoryName, packageName)
 	if len(repositoryName) == 0 {
-		var repos []v1alpha1.PackageRepository
-		repos, repoErr = repoClient.Meta().GetReposForPackage(packageName)
-		if len(repos) == 0 {
-			return nil, "", multierr.Append(fmt.Errorf("no repo found for package %v", packageName), repoErr)
-		} else {
-			for _, repo := range repos {
-				repositoryName = repo.Name
-				if repo.IsDefaultRepository() {
-					break
-				}
-			}
-		}
+		return nil, "", repoErr
 	}
 	var packageManifest v1alpha1.PackageManifest
 	if latestVersion, err := repoClient.ForRepoWithName(repositoryName).
@@ -86,3 +76,40 @@ func DescribeLatestVersion(ctx context.Context, repositoryName string, packageNa
 		return &packageManifest, latestVersion, repoErr
 	}
 }
+
+// DescribeSignature verifies the signature of the package manifest of the given package version.
+// If repositoryName is empty, the same repository as in DescribeLatestVersion is used.
+func DescribeSignature(
+	ctx context.Context,
+	repositoryName string,
+	packageName string,
+	version string,
+) SignatureVerification {
+	repoClient := cliutils.RepositoryClientset(ctx)
+	if repositoryName, err := resolveRepositoryName(repoClient, repositoryName, packageName); len(repositoryName) == 0 {
+		return SignatureVerification{Err: err}
+	} else {
+		return VerifySignature(repoClient.ForRepoWithName(repositoryName), packageName, version)
+	}
+}
+
+// resolveRepositoryName returns repositoryName if it is not empty. Otherwise it returns the name of a repository that
+// contains the package, preferring the default repository.
+// A non-empty name may be returned together with an error, if not all repositories could be checked.
+func resolveRepositoryName(repoClient repoclient.RepoClientset, repositoryName string, packageName string) (
+	string, error) {
+	if len(repositoryName) > 0 {
+		return repositoryName, nil
+	}
+	repos, repoErr := repoClient.Meta().GetReposForPackage(packageName)
+	if len(repos) == 0 {
+		return "", multierr.Append(fmt.Errorf("no repo found for package %v", packageName), repoErr)
+	}
+	for _, repo := range repos {
+		repositoryName = repo.Name
+		if repo.IsDefaultRepository() {
+			break
+		}
+	}
+	return repositoryName, repoErr
+}
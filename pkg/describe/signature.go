@@ -0,0 +1,56 @@
+package describe
+
+import (
+	"fmt"
+
+	repoclient "github.com/glasskube/glasskube/internal/repo/client"
+)
+
+// SignatureVerification describes whether the manifest of a package version has a valid signature.
+type SignatureVerification struct {
+	// Required is true if the repository only accepts signed package manifests.
+	Required bool
+	// KeyFingerprint is the fingerprint of the trusted public key that signed the package manifest.
+	KeyFingerprint string
+	Err            error
+}
+
+// VerifySignature verifies the signature of a package manifest, if the repository requires signed package manifests.
+func VerifySignature(client repoclient.RepoClient, packageName, version string) SignatureVerification {
+	if verifier, ok := client.(repoclient.PackageManifestVerifier); !ok {
+		return SignatureVerification{}
+	} else if fingerprint, err := verifier.VerifyPackageManifest(packageName, version); err != nil {
+		return SignatureVerification{Required: true, Err: err}
+	} else {
+		return SignatureVerification{Required: true, KeyFingerprint: fingerprint}
+	}
+}
+
+func (v SignatureVerification) IsVerified() bool {
+	return v.Required && v.Err == nil
+}
+
+func (v SignatureVerification) String() string {
+	if v.Err != nil {
+		return fmt.Sprintf("verification failed: %v", v.Err)
+	} else if !v.Required {
+		return "not verified (the repository does not require signed manifests)"
+	} else {
+		return fmt.Sprintf("verified (signed by %v)", v.KeyFingerprint)
+	}
+}
+
+// AsMap returns the verification state for structured output formats.
+func (v SignatureVerification) AsMap() map[string]any {
+	result := map[string]any{
+		"required": v.Required,
+		"verified": v.IsVerified(),
+	}
+	if v.KeyFingerprint != "" {
+		result["keyFingerprint"] = v.KeyFingerprint
+	}
+	if v.Err != nil {
+		result["error"] = v.Err.Error()
+	}
+	return result
+}
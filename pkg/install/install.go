@@ -7,16 +7,21 @@ import (
 
 	"github.com/glasskube/glasskube/api/v1alpha1"
 	"github.com/glasskube/glasskube/internal/controller/ctrlpkg"
+	"github.com/glasskube/glasskube/internal/manifestvalues"
 	"github.com/glasskube/glasskube/pkg/client"
 	"github.com/glasskube/glasskube/pkg/condition"
 	"github.com/glasskube/glasskube/pkg/statuswriter"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
 )
 
 type installer struct {
-	client client.PackageV1Alpha1Client
-	status statuswriter.StatusWriter
+	client         client.PackageV1Alpha1Client
+	status         statuswriter.StatusWriter
+	secretClient   kubernetes.Interface
+	secretManifest *v1alpha1.PackageManifest
 }
 
 func NewInstaller(pkgClient client.PackageV1Alpha1Client) *installer {
@@ -28,6 +33,17 @@ func (obj *installer) WithStatusWriter(sw statuswriter.StatusWriter) *installer
 	return obj
 }
 
+// WithSecretValues enables storing secret values of the package in a Secret instead of the package spec.
+// manifest is used to determine which values are secret.
+func (obj *installer) WithSecretValues(
+	kubernetesClient kubernetes.Interface,
+	manifest *v1alpha1.PackageManifest,
+) *installer {
+	obj.secretClient = kubernetesClient
+	obj.secretManifest = manifest
+	return obj
+}
+
 // InstallBlocking creates a new v1alpha1.Package custom resource in the cluster and waits until
 // the package has either status Ready or Failed.
 func (obj *installer) InstallBlocking(
@@ -65,14 +81,34 @@ func (obj *installer) install(
 	opts metav1.CreateOptions,
 ) (ctrlpkg.Package, error) {
 	obj.status.SetStatus(fmt.Sprintf("Installing %v...", pkg.GetName()))
+	var secret *corev1.Secret
+	if obj.secretClient != nil && obj.secretManifest != nil {
+		// The Secret is created before the package, so that its values can be resolved right away.
+		if secret = manifestvalues.ExtractSecretValues(pkg, obj.secretManifest); secret != nil {
+			if err := manifestvalues.ApplySecretValues(ctx, obj.secretClient, secret, nil, opts.DryRun); err != nil {
+				return nil, fmt.Errorf("failed to create secret for values: %w", err)
+			}
+		}
+	}
+	var err error
 	switch pkg := pkg.(type) {
 	case *v1alpha1.ClusterPackage:
-		return pkg, obj.client.ClusterPackages().Create(ctx, pkg, opts)
+		err = obj.client.ClusterPackages().Create(ctx, pkg, opts)
 	case *v1alpha1.Package:
-		return pkg, obj.client.Packages(pkg.GetNamespace()).Create(ctx, pkg, opts)
+		err = obj.client.Packages(pkg.GetNamespace()).Create(ctx, pkg, opts)
 	default:
 		return nil, fmt.Errorf("unexpected package type: %T", pkg)
 	}
+	if err != nil {
+		return nil, err
+	}
+	if secret != nil && !isDryRun(opts) {
+		// Now that the package exists, it becomes the owner of the Secret.
+		if err := manifestvalues.ApplySecretValues(ctx, obj.secretClient, secret, pkg, opts.DryRun); err != nil {
+			return nil, fmt.Errorf("failed to set owner of secret for values: %w", err)
+		}
+	}
+	return pkg, nil
 }
 
 func (obj *installer) awaitInstall(ctx context.Context, pkg ctrlpkg.Package) (*client.PackageStatus, error) {
@@ -5,14 +5,18 @@ import (
 	"errors"
 	"fmt"
 	"slices"
+	"time"
 
 	"github.com/glasskube/glasskube/api/v1alpha1"
 	"github.com/glasskube/glasskube/internal/cliutils"
 	"github.com/glasskube/glasskube/internal/controller/ctrlpkg"
 	"github.com/glasskube/glasskube/internal/dependency"
+	"github.com/glasskube/glasskube/internal/maintenancewindow"
 	"github.com/glasskube/glasskube/internal/repo"
 	repoclient "github.com/glasskube/glasskube/internal/repo/client"
+	"github.com/glasskube/glasskube/internal/revisions"
 	"github.com/glasskube/glasskube/internal/semver"
+	"github.com/glasskube/glasskube/internal/updatepolicy"
 	"github.com/glasskube/glasskube/pkg/client"
 	"github.com/glasskube/glasskube/pkg/condition"
 	"github.com/glasskube/glasskube/pkg/statuswriter"
@@ -21,11 +25,19 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/util/retry"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+var errNoPackageClient = errors.New("updater has no package client")
+
+// ErrOutsideMaintenanceWindow is returned by CheckMaintenanceWindow if the maintenance window of a package is closed.
+var ErrOutsideMaintenanceWindow = errors.New("outside of maintenance window")
+
 type UpdateTransaction struct {
 	Items         []updateTransactionItem
 	ConflictItems []updateTransactionItemConflict
+	// HeldBackItems contains packages that are not updated to the latest version because of their update policy.
+	HeldBackItems []updateTransactionItemHeldBack
 	Requirements  []dependency.Requirement
 	Pruned        []dependency.Requirement
 }
@@ -49,15 +61,27 @@ type updateTransactionItemConflict struct {
 	Conflicts dependency.Conflicts
 }
 
+type updateTransactionItemHeldBack struct {
+	Package ctrlpkg.Package
+	// Version is the version the package would be updated to without its update policy.
+	Version string
+	Policy  string
+}
+
 func (txi updateTransactionItem) UpdateRequired() bool {
 	return txi.Version != ""
 }
 
 type updater struct {
-	client     client.PackageV1Alpha1Client
-	repoClient repoclient.RepoClientset
-	status     statuswriter.StatusWriter
-	dm         *dependency.DependendcyManager
+	client client.PackageV1Alpha1Client
+	// objectClient is used instead of client to update packages, if it is set.
+	objectClient ctrlclient.Client
+	repoClient   repoclient.RepoClientset
+	status       statuswriter.StatusWriter
+	dm           *dependency.DependendcyManager
+	// maintenanceWindows is true if only items whose maintenance window is open should be applied.
+	maintenanceWindows bool
+	defaultSchedule    maintenancewindow.Schedule
 }
 
 func NewUpdater(ctx context.Context) *updater {
@@ -69,11 +93,34 @@ func NewUpdater(ctx context.Context) *updater {
 	}
 }
 
+// NewPreparer creates an updater that does not depend on a CLI context, e.g. for use in the operator.
+// Packages are updated using the given controller-runtime client. Blocking updates are not supported.
+func NewPreparer(
+	objectClient ctrlclient.Client,
+	repoClient repoclient.RepoClientset,
+	dm *dependency.DependendcyManager,
+) *updater {
+	return &updater{
+		status:       statuswriter.Noop(),
+		objectClient: objectClient,
+		repoClient:   repoClient,
+		dm:           dm,
+	}
+}
+
 func (c *updater) WithStatusWriter(writer statuswriter.StatusWriter) *updater {
 	c.status = writer
 	return c
 }
 
+// WithMaintenanceWindows restricts Apply to items whose maintenance window is open at the time of the update.
+// defaultSchedule is used for all packages that do not have a maintenance window of their own.
+func (c *updater) WithMaintenanceWindows(defaultSchedule maintenancewindow.Schedule) *updater {
+	c.maintenanceWindows = true
+	c.defaultSchedule = defaultSchedule
+	return c
+}
+
 func (c *updater) PrepareForVersion(
 	ctx context.Context, pkg ctrlpkg.Package, pkgVersion string,
 ) (*UpdateTransaction, error) {
@@ -105,6 +152,41 @@ func (c *updater) PrepareForVersion(
 	return &tx, nil
 }
 
+// PrepareRollback prepares a transaction that restores the version and values of revision for pkg.
+// The restored spec is validated in the same way as by the validating webhook, so that conflicts are detected before
+// anything is changed. Unlike PrepareForVersion, this allows to downgrade a package.
+func (c *updater) PrepareRollback(
+	ctx context.Context, pkg ctrlpkg.Package, revision v1alpha1.PackageRevision,
+) (*UpdateTransaction, error) {
+	c.status.Start()
+	defer c.status.Stop()
+	c.status.SetStatus(fmt.Sprintf("Validating revision %v", revision.Revision))
+
+	target, ok := pkg.DeepCopyObject().(ctrlpkg.Package)
+	if !ok {
+		return nil, fmt.Errorf("unexpected object kind: %v", pkg.GroupVersionKind().Kind)
+	}
+	revisions.Apply(revision, target.GetSpec())
+
+	var tx UpdateTransaction
+	if result, err := c.dm.ValidatePackage(ctx, c.repoClient, target); err != nil {
+		return nil, err
+	} else {
+		// The version is set by UpdatePackage, so the current version can still be shown in the transaction.
+		target.GetSpec().PackageInfo.Version = pkg.GetSpec().PackageInfo.Version
+		item := updateTransactionItem{Package: target, Version: revision.Version}
+		if len(result.Conflicts) > 0 {
+			tx.ConflictItems = append(tx.ConflictItems, updateTransactionItemConflict{item, result.Conflicts})
+		} else {
+			tx.Requirements = result.Requirements
+			tx.Pruned = result.Pruned
+			tx.Items = append(tx.Items, item)
+		}
+	}
+
+	return &tx, nil
+}
+
 func (c *updater) Prepare(ctx context.Context, getters ...PackagesGetter) (*UpdateTransaction, error) {
 	c.status.Start()
 	defer c.status.Stop()
@@ -141,15 +223,21 @@ outer:
 
 		for _, indexItem := range index.Packages {
 			if indexItem.Name == pkg.GetSpec().PackageInfo.Name {
-				if semver.IsUpgradable(pkg.GetSpec().PackageInfo.Version, indexItem.LatestVersion) {
-					item := updateTransactionItem{Package: pkg, Version: indexItem.LatestVersion}
+				version, heldBack, err := c.selectVersion(repoClient, pkg, indexItem.LatestVersion)
+				if err != nil {
+					return nil, err
+				} else if heldBack != nil {
+					tx.HeldBackItems = append(tx.HeldBackItems, *heldBack)
+				}
+				if version != "" && semver.IsUpgradable(pkg.GetSpec().PackageInfo.Version, version) {
+					item := updateTransactionItem{Package: pkg, Version: version}
 					var manifest v1alpha1.PackageManifest
 					if err := repoClient.FetchPackageManifest(
-						pkg.GetSpec().PackageInfo.Name, indexItem.LatestVersion, &manifest); err != nil {
+						pkg.GetSpec().PackageInfo.Name, version, &manifest); err != nil {
 						return nil, err
 					}
 					if result, err := c.dm.Validate(ctx, pkg.GetName(), pkg.GetNamespace(),
-						&manifest, indexItem.LatestVersion); err != nil {
+						&manifest, version); err != nil {
 						return nil, err
 					} else if len(result.Conflicts) > 0 {
 						// This package can't be updated due to conflicts
@@ -202,6 +290,38 @@ outer:
 	return &tx, nil
 }
 
+// selectVersion returns the highest version pkg may be updated to according to its update policy.
+// If the policy prevents an update to latestVersion, the returned updateTransactionItemHeldBack is not nil and version
+// may be empty. A package with an invalid policy is not updated at all.
+func (c *updater) selectVersion(
+	repoClient repoclient.RepoClient, pkg ctrlpkg.Package, latestVersion string,
+) (version string, heldBack *updateTransactionItemHeldBack, err error) {
+	installedVersion := pkg.GetSpec().PackageInfo.Version
+	if !semver.IsUpgradable(installedVersion, latestVersion) {
+		return latestVersion, nil, nil
+	}
+	policy, err := updatepolicy.ForPackage(pkg)
+	if err != nil {
+		return "", &updateTransactionItemHeldBack{
+			Package: pkg,
+			Version: latestVersion,
+			Policy:  pkg.GetAnnotations()[v1alpha1.AnnotationAutoUpdatePolicy],
+		}, nil
+	} else if policy.Allows(installedVersion, latestVersion) {
+		return latestVersion, nil, nil
+	}
+	var index repo.PackageIndex
+	if err := repoClient.FetchPackageIndex(pkg.GetSpec().PackageInfo.Name, &index); err != nil {
+		return "", nil, fmt.Errorf("failed to fetch package index: %w", err)
+	}
+	versions := make([]string, len(index.Versions))
+	for i, item := range index.Versions {
+		versions[i] = item.Version
+	}
+	version, _ = policy.Select(installedVersion, versions)
+	return version, &updateTransactionItemHeldBack{Package: pkg, Version: latestVersion, Policy: policy.String()}, nil
+}
+
 type ApplyUpdateOptions struct {
 	Blocking bool
 	DryRun   bool
@@ -216,7 +336,11 @@ func (c *updater) Apply(
 	defer c.status.Stop()
 	var updatedPackages []ctrlpkg.Package
 	for _, item := range tx.Items {
-		if item.UpdateRequired() {
+		if !item.UpdateRequired() {
+			continue
+		} else if err := c.CheckMaintenanceWindow(item.Package, time.Now()); err != nil {
+			c.status.SetStatus(fmt.Sprintf("Skipping %v (%v)", item.Package.GetName(), err))
+		} else {
 			c.status.SetStatus(fmt.Sprintf("Updating %v", item.Package.GetName()))
 			err := retry.OnError(retry.DefaultRetry,
 				apierrors.IsNotFound,
@@ -237,7 +361,31 @@ func (c *updater) Apply(
 	return updatedPackages, nil
 }
 
+// CheckMaintenanceWindow returns nil if maintenance windows are disabled or the window of pkg is open at now.
+// If the window is closed, the error wraps ErrOutsideMaintenanceWindow and describes when it opens. Packages with an
+// invalid maintenance window are never updated, so the parse error is returned for them.
+func (c *updater) CheckMaintenanceWindow(pkg ctrlpkg.Package, now time.Time) error {
+	if !c.maintenanceWindows {
+		return nil
+	} else if schedule, err := maintenancewindow.ForPackage(pkg, c.defaultSchedule); err != nil {
+		return err
+	} else if !schedule.IsOpen(now) {
+		return fmt.Errorf("%w (%v)", ErrOutsideMaintenanceWindow, schedule.Describe(now))
+	} else {
+		return nil
+	}
+}
+
 func (c *updater) UpdatePackage(ctx context.Context, pkg ctrlpkg.Package, version string, DryRun bool) error {
+	if c.objectClient != nil {
+		pkg.GetSpec().PackageInfo.Version = version
+		if DryRun {
+			return c.objectClient.Update(ctx, pkg, ctrlclient.DryRunAll)
+		}
+		return c.objectClient.Update(ctx, pkg)
+	} else if c.client == nil {
+		return errNoPackageClient
+	}
 	opts := metav1.UpdateOptions{}
 	if DryRun {
 		opts.DryRun = []string{metav1.DryRunAll}
@@ -254,6 +402,9 @@ func (c *updater) UpdatePackage(ctx context.Context, pkg ctrlpkg.Package, versio
 }
 
 func (c *updater) awaitUpdate(ctx context.Context, pkg ctrlpkg.Package) error {
+	if c.client == nil {
+		return errNoPackageClient
+	}
 	switch pkg := pkg.(type) {
 	case *v1alpha1.ClusterPackage:
 		watcher, err := c.client.ClusterPackages().Watch(ctx, metav1.ListOptions{})
@@ -32,6 +32,83 @@
   });
 })();
 
+// rows of list and map inputs in the package configuration form
+document.addEventListener('click', (evt) => {
+  const addButton = evt.target.closest('[data-config-item-add]');
+  if (addButton) {
+    const container = document.getElementById(addButton.dataset.configItemAdd);
+    const row = container.querySelector('template').content.cloneNode(true);
+    container.querySelector('.config-items').appendChild(row);
+    updateConfigConditions();
+    return;
+  }
+  const removeButton = evt.target.closest('[data-config-item-remove]');
+  if (removeButton) {
+    removeButton.closest('.config-item').remove();
+    updateConfigConditions();
+  }
+});
+
+// values of the package configuration form that only apply if a condition on another value holds
+function getConfigValue(container) {
+  if (container.querySelector('[name$="[refKind]"]')) {
+    return undefined; // references can not be evaluated in the browser
+  }
+  const name = container.dataset.valueName;
+  const input = container.querySelector(`[name="values.${name}"]`);
+  if (input) {
+    return input.type === 'checkbox' ? String(input.checked) : input.value;
+  }
+  const items = container.querySelectorAll(
+    `.config-items [name="values.${name}[item]"]`,
+  );
+  return items.length > 0 ? 'items' : '';
+}
+
+function isConfigConditionMet(container, containers) {
+  const dependency = containers.get(container.dataset.whenValue);
+  if (!dependency || dependency.hidden) {
+    return false;
+  }
+  const value = getConfigValue(dependency);
+  if (value === undefined) {
+    return true;
+  } else if (container.dataset.whenEquals !== undefined) {
+    return value === container.dataset.whenEquals;
+  } else if (container.dataset.whenIn !== undefined) {
+    return JSON.parse(container.dataset.whenIn).includes(value);
+  } else {
+    return value !== '' && value !== 'false' && value !== '0';
+  }
+}
+
+function updateConfigConditions() {
+  const containers = new Map(
+    [...document.querySelectorAll('[data-value-name]')].map((elem) => [
+      elem.dataset.valueName,
+      elem,
+    ]),
+  );
+  // repeat until nothing changes, because conditions can depend on values that have conditions themselves
+  for (let changed = true, i = 0; changed && i <= containers.size; i++) {
+    changed = false;
+    for (const container of containers.values()) {
+      if (container.dataset.whenValue === undefined) continue;
+      const hidden = !isConfigConditionMet(container, containers);
+      if (container.hidden !== hidden) {
+        container.hidden = hidden;
+        // hidden inputs are disabled, so that they are neither validated nor submitted
+        container
+          .querySelectorAll('input, select')
+          .forEach((input) => (input.disabled = hidden));
+        changed = true;
+      }
+    }
+  }
+}
+document.addEventListener('change', updateConfigConditions);
+htmx.onLoad(updateConfigConditions);
+
 function setSSEDisconnected() {
   const elem = document.getElementById('disconnected-toast');
   if (elem && !elem.classList.contains('show')) {